@@ -34,9 +34,12 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/api"
 	"github.com/mxab/nacp/pkg/admissionctrl"
+	"github.com/mxab/nacp/pkg/admissionctrl/executil"
+	"github.com/mxab/nacp/pkg/admissionctrl/grpcutil"
 	"github.com/mxab/nacp/pkg/admissionctrl/mutator"
 	"github.com/mxab/nacp/pkg/admissionctrl/notation"
 	"github.com/mxab/nacp/pkg/admissionctrl/validator"
+	"github.com/mxab/nacp/pkg/admissionctrl/wasmutil"
 	"github.com/mxab/nacp/pkg/config"
 	"github.com/mxab/nacp/pkg/helper"
 	"github.com/notaryproject/notation-go/dir"
@@ -52,20 +55,40 @@ var (
 type contextKeyWarnings struct{}
 type contextKeyValidationError struct{}
 type contextKeyRequestContext struct{}
+type contextKeyMutateParse struct{}
+type contextKeyProvenance struct{}
 
 var (
 	ctxWarnings        = contextKeyWarnings{}
 	ctxValidationError = contextKeyValidationError{}
 	ctxRequestContext  = contextKeyRequestContext{}
-	jobPathRegex       = regexp.MustCompile(`^/v1/job/[^/]+$`)
+	ctxMutateParse     = contextKeyMutateParse{}
+	ctxProvenance      = contextKeyProvenance{}
+	jobPathRegex       = regexp.MustCompile(`^/v1/job/([^/]+)$`)
 	jobPlanPathRegex   = regexp.MustCompile(`^/v1/job/[^/]+/plan$`)
+	jobDispatchRegex   = regexp.MustCompile(`^/v1/job/([^/]+)/dispatch$`)
+	jobScaleRegex      = regexp.MustCompile(`^/v1/job/([^/]+)/scale$`)
 
 	nomadTimeout         = 310 * time.Second
 	tokenResolveTimeout  = 30 * time.Second
+	jobLookupTimeout     = 30 * time.Second
 	shutdownTimeout      = 30 * time.Second
 	maxAdmissionBodySize = int64(32 << 20)
 )
 
+// warningsHeader carries admission warnings for responses Nomad has no
+// warnings field in, the parse preview, dispatches and deregistrations, one
+// header value per warning.
+const warningsHeader = "X-NACP-Warnings"
+
+// mutationsHeader carries the provenance of a registered or planned job, the
+// mutators that changed it with a digest of their patches.
+const mutationsHeader = "X-NACP-Mutations"
+
+// policyOverrideHeader asks NACP to override soft-mandatory validators, like
+// the -policy-override flag of nomad job run does for register and plan.
+const policyOverrideHeader = "X-NACP-Policy-Override"
+
 // New function to get client IP
 func getClientIP(r *http.Request) string {
 	// Check X-Forwarded-For header first
@@ -107,30 +130,120 @@ func resolveTokenAccessor(ctx context.Context, transport http.RoundTripper, noma
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, admissionctrl.UpstreamError(fmt.Errorf("failed to resolve token: %w", err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to resolve token: %s", resp.Status)
+		return nil, nomadStatusError(fmt.Errorf("failed to resolve token: %s", resp.Status), resp.StatusCode)
 	}
 
 	var aclToken api.ACLToken
 	if err := json.NewDecoder(resp.Body).Decode(&aclToken); err != nil {
-		return nil, err
+		return nil, admissionctrl.UpstreamError(fmt.Errorf("failed to decode token: %w", err))
 	}
 
 	return &aclToken, nil
 }
-func NewProxyAsHandlerFunc(nomadAddress *url.URL, jobHandler *admissionctrl.JobHandler, logger *slog.Logger, transport http.RoundTripper) http.HandlerFunc {
 
-	proxy := newProxyHandler(nomadAddress, jobHandler, logger, transport)
+// fetchJob loads the job currently registered under jobID, or returns nil if
+// there is no such job. The caller's token, namespace and region are forwarded
+// so Nomad applies the caller's ACLs.
+func fetchJob(ctx context.Context, transport http.RoundTripper, nomadAddress *url.URL, r *http.Request, jobID string) (*api.Job, error) {
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   jobLookupTimeout,
+	}
+	if transport == nil {
+		client.Transport = http.DefaultTransport
+	}
+
+	jobURL := *nomadAddress
+	jobURL.Path = "/v1/job/" + jobID
+	jobURL.RawPath = "/v1/job/" + url.PathEscape(jobID)
+	query := url.Values{}
+	for _, key := range []string{"namespace", "region"} {
+		if value := r.URL.Query().Get(key); value != "" {
+			query.Set(key, value)
+		}
+	}
+	jobURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", jobURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if token := r.Header.Get("X-Nomad-Token"); token != "" {
+		req.Header.Set("X-Nomad-Token", token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, admissionctrl.UpstreamError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nomadStatusError(fmt.Errorf("failed to fetch job: %s", resp.Status), resp.StatusCode)
+	}
+
+	var job api.Job
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return nil, admissionctrl.UpstreamError(fmt.Errorf("failed to decode job: %w", err))
+	}
+
+	return &job, nil
+}
+
+// nomadStatusError classifies a failed Nomad lookup. Nomad's own permission
+// errors are passed on as denials, anything else is an upstream failure.
+func nomadStatusError(err error, status int) error {
+	if status == http.StatusForbidden || status == http.StatusUnauthorized {
+		return admissionctrl.NewError(admissionctrl.KindDenied, err)
+	}
+	return admissionctrl.NewError(admissionctrl.KindUpstream, err)
+}
+
+type proxyOptions struct {
+	mutateParse bool
+	submission  *config.Submission
+}
+
+type ProxyOption func(*proxyOptions)
+
+// WithParseMutation makes the proxy run the mutators on jobs returned by
+// Nomad's /v1/jobs/parse endpoint, so previews show the admitted job.
+func WithParseMutation(enabled bool) ProxyOption {
+	return func(o *proxyOptions) {
+		o.mutateParse = enabled
+	}
+}
+
+// WithSubmission sets how the proxy treats the source submitted with a job
+// registration once mutators changed the job. Nil keeps it untouched.
+func WithSubmission(submission *config.Submission) ProxyOption {
+	return func(o *proxyOptions) {
+		o.submission = submission
+	}
+}
+
+func NewProxyAsHandlerFunc(nomadAddress *url.URL, jobHandler *admissionctrl.JobHandler, logger *slog.Logger, transport http.RoundTripper, opts ...ProxyOption) http.HandlerFunc {
+
+	proxy := newProxyHandler(nomadAddress, jobHandler, logger, transport, opts...)
 	handlerFunc := http.HandlerFunc(proxy)
 	handlerFunc = otelhttp.NewHandler(handlerFunc, "/").(http.HandlerFunc)
 
 	return handlerFunc
 }
-func newProxyHandler(nomadAddress *url.URL, jobHandler *admissionctrl.JobHandler, logger *slog.Logger, transport http.RoundTripper) func(http.ResponseWriter, *http.Request) {
+func newProxyHandler(nomadAddress *url.URL, jobHandler *admissionctrl.JobHandler, logger *slog.Logger, transport http.RoundTripper, opts ...ProxyOption) func(http.ResponseWriter, *http.Request) {
+
+	options := &proxyOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
 
 	proxy := httputil.NewSingleHostReverseProxy(nomadAddress)
 
@@ -139,12 +252,16 @@ func newProxyHandler(nomadAddress *url.URL, jobHandler *admissionctrl.JobHandler
 	}
 
 	proxy.ModifyResponse = func(resp *http.Response) error {
-		return modifyProxyResponse(resp, logger)
+		return modifyProxyResponse(resp, logger, jobHandler)
 	}
 	var proxyHandler http.Handler = proxy
 
 	nacpHandler := func(w http.ResponseWriter, r *http.Request) {
 
+		if options.mutateParse && isParse(r) {
+			r = r.WithContext(context.WithValue(r.Context(), ctxMutateParse, true))
+		}
+
 		r, err := resolveRequestContext(w, r, jobHandler, nomadAddress, transport, logger)
 		if err != nil {
 			logger.ErrorContext(r.Context(), "Resolving token failed", "error", err)
@@ -152,7 +269,7 @@ func newProxyHandler(nomadAddress *url.URL, jobHandler *admissionctrl.JobHandler
 			return
 		}
 
-		r, err = applyAdmission(r, logger, jobHandler)
+		r, err = applyAdmission(r, logger, jobHandler, nomadAddress, transport, options)
 		if err != nil {
 			logger.WarnContext(r.Context(), "Error applying admission controllers", "error", err)
 			writeError(w, err)
@@ -165,16 +282,24 @@ func newProxyHandler(nomadAddress *url.URL, jobHandler *admissionctrl.JobHandler
 
 }
 
-func modifyProxyResponse(resp *http.Response, logger *slog.Logger) error {
+func modifyProxyResponse(resp *http.Response, logger *slog.Logger, jobHandler *admissionctrl.JobHandler) error {
 
 	var err error
 
-	if isRegister(resp.Request) {
+	if provenance, ok := resp.Request.Context().Value(ctxProvenance).(string); ok && provenance != "" {
+		resp.Header.Set(mutationsHeader, provenance)
+	}
+
+	if isRegister(resp.Request) || isScale(resp.Request) {
 		err = handRegisterResponse(resp, logger)
 	} else if isPlan(resp.Request) {
 		err = handleJobPlanResponse(resp, logger)
 	} else if isValidate(resp.Request) {
 		err = handleJobValdidateResponse(resp, logger)
+	} else if isDispatch(resp.Request) || isDeregister(resp.Request) {
+		handleWarningsHeaderResponse(resp)
+	} else if isParseMutation(resp.Request) {
+		err = handleJobParseResponse(resp, logger, jobHandler)
 	}
 	if err != nil {
 		logger.ErrorContext(resp.Request.Context(), "Preparing response failed", "error", err)
@@ -194,8 +319,15 @@ func resolveRequestContext(w http.ResponseWriter, r *http.Request, jobHandler *a
 		ClientIP:     getClientIP(r),
 		ResolveToken: jobHandler.ResolveToken(),
 	}
+	if override, err := strconv.ParseBool(r.Header.Get(policyOverrideHeader)); err == nil {
+		reqCtx.PolicyOverride = override
+	}
 
-	isAdmissionActionable := isRegister(r) || isPlan(r) || isValidate(r)
+	isAdmissionActionable := isRegister(r) || isPlan(r) || isValidate(r) ||
+		(isDispatch(r) && jobHandler.HandlesOperation(config.OperationDispatch)) ||
+		(isDeregister(r) && jobHandler.HandlesOperation(config.OperationDeregister)) ||
+		(isScale(r) && jobHandler.HandlesOperation(config.OperationScale)) ||
+		isParseMutation(r)
 	if isAdmissionActionable {
 		r.Body = http.MaxBytesReader(w, r.Body, maxAdmissionBodySize)
 	}
@@ -217,9 +349,9 @@ func resolveRequestContext(w http.ResponseWriter, r *http.Request, jobHandler *a
 	return r.WithContext(context.WithValue(ctx, ctxRequestContext, reqCtx)), nil
 }
 
-func applyAdmission(r *http.Request, logger *slog.Logger, jobHandler *admissionctrl.JobHandler) (*http.Request, error) {
+func applyAdmission(r *http.Request, logger *slog.Logger, jobHandler *admissionctrl.JobHandler, nomadAddress *url.URL, transport http.RoundTripper, options *proxyOptions) (*http.Request, error) {
 	if isRegister(r) {
-		return handleRegister(r, logger, jobHandler)
+		return handleRegister(r, logger, jobHandler, options.submission)
 	}
 	if isPlan(r) {
 		return handlePlan(r, logger, jobHandler)
@@ -227,6 +359,15 @@ func applyAdmission(r *http.Request, logger *slog.Logger, jobHandler *admissionc
 	if isValidate(r) {
 		return handleValidate(r, logger, jobHandler)
 	}
+	if isDispatch(r) {
+		return handleDispatch(r, logger, jobHandler, nomadAddress, transport)
+	}
+	if isDeregister(r) {
+		return handleDeregister(r, logger, jobHandler, nomadAddress, transport)
+	}
+	if isScale(r) {
+		return handleScale(r, logger, jobHandler, nomadAddress, transport)
+	}
 	return r, nil
 }
 
@@ -341,7 +482,8 @@ func handleJobValdidateResponse(resp *http.Response, logger *slog.Logger) error
 	if validationErr != nil {
 		validationErrors := []string{}
 		var validationError string
-		if merr, ok := validationErr.(*multierror.Error); ok {
+		var merr *multierror.Error
+		if errors.As(validationErr, &merr) {
 			for _, err := range merr.Errors {
 				validationErrors = append(validationErrors, err.Error())
 			}
@@ -371,6 +513,76 @@ func handleJobValdidateResponse(resp *http.Response, logger *slog.Logger) error
 	return nil
 }
 
+// handleWarningsHeaderResponse returns the admission warnings of a dispatch
+// or deregistration in the warningsHeader, as Nomad's responses to them have
+// no warnings field the Nomad api client would decode.
+func handleWarningsHeaderResponse(resp *http.Response) {
+	warnings, ok := resp.Request.Context().Value(ctxWarnings).([]error)
+	if !ok || !isSuccessfulResponse(resp) {
+		return
+	}
+	addWarningsHeader(resp.Header, warnings)
+}
+
+func addWarningsHeader(header http.Header, warnings []error) {
+	for _, warning := range warnings {
+		header.Add(warningsHeader, strings.Join(strings.Fields(warning.Error()), " "))
+	}
+}
+
+// handleJobParseResponse runs the mutators on the job Nomad parsed, as they
+// would run when the job is registered, and returns the mutated job instead.
+// Mutator warnings are returned in the warningsHeader.
+func handleJobParseResponse(resp *http.Response, logger *slog.Logger, jobHandler *admissionctrl.JobHandler) error {
+	if !isSuccessfulResponse(resp) {
+		return nil
+	}
+	ctx := resp.Request.Context()
+
+	isGzip, reader, err := checkIfGzipAndTransformReader(resp, resp.Body)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	job := &api.Job{}
+	if err := json.NewDecoder(reader).Decode(job); err != nil {
+		return err
+	}
+
+	payload := &types.Payload{
+		Job:       job,
+		Operation: config.OperationRegister,
+	}
+	if reqCtx, ok := ctx.Value(ctxRequestContext).(*config.RequestContext); ok {
+		payload.Context = reqCtx
+	}
+
+	mutatedJob, warnings, err := jobHandler.AdmissionMutators(ctx, payload)
+	if err != nil {
+		logger.WarnContext(ctx, "Error applying mutators to parsed job", "error", err)
+		status, body := renderError(err)
+		resp.StatusCode = status
+		resp.Status = fmt.Sprintf("%d %s", status, http.StatusText(status))
+		resp.Header.Del("Content-Encoding")
+		setErrorHeaders(resp.Header)
+		rewriteResponse(resp, []byte(body))
+		return nil
+	}
+	addWarningsHeader(resp.Header, warnings)
+
+	responseData, err := json.Marshal(mutatedJob)
+	if err != nil {
+		return err
+	}
+
+	if isGzip {
+		return rewriteResponseGzip(resp, responseData)
+	}
+	rewriteResponse(resp, responseData)
+	return nil
+}
+
 func buildFullWarningMsg(upstreamResponseWarnings string, warnings []error) string {
 	allWarnings := &multierror.Error{}
 
@@ -409,29 +621,39 @@ func rewriteRequest(r *http.Request, data []byte) {
 	r.Body = io.NopCloser(bytes.NewBuffer(data))
 }
 
-func handleRegister(r *http.Request, appLogger *slog.Logger, jobHandler *admissionctrl.JobHandler) (*http.Request, error) {
+func handleRegister(r *http.Request, appLogger *slog.Logger, jobHandler *admissionctrl.JobHandler, submission *config.Submission) (*http.Request, error) {
 	body := r.Body
 	jobRegisterRequest := &api.JobRegisterRequest{}
 
 	ctx := r.Context()
 	if err := json.NewDecoder(body).Decode(jobRegisterRequest); err != nil {
 
-		return r, fmt.Errorf("failed decoding job, skipping admission controller: %w", err)
+		return r, admissionctrl.NewError(admissionctrl.KindBadRequest, fmt.Errorf("failed decoding job, skipping admission controller: %w", err))
 	}
 	orginalJob := jobRegisterRequest.Job
 	payload := &types.Payload{
-		Job: orginalJob,
+		Job:       orginalJob,
+		Operation: config.OperationRegister,
 	}
 
 	if reqCtx, ok := ctx.Value(ctxRequestContext).(*config.RequestContext); ok {
+		reqCtx.PolicyOverride = reqCtx.PolicyOverride || jobRegisterRequest.PolicyOverride
 		payload.Context = reqCtx
 	}
 
-	job, warnings, err := jobHandler.ApplyAdmissionControllers(ctx, payload)
+	// the submission strategy runs before the validators, so they see the
+	// annotated job that is sent to Nomad
+	admission, err := jobHandler.Admit(ctx, payload, func(admission *admissionctrl.Admission) error {
+		jobRegisterRequest.Job = admission.Job
+		if err := applySubmissionStrategy(jobRegisterRequest, admission.Mutations, submission); err != nil {
+			return fmt.Errorf("failed to apply submission strategy: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
 		return r, fmt.Errorf("admission controllers send an error, returning error: %w", err)
 	}
-	jobRegisterRequest.Job = job
+	warnings := admission.Warnings
 
 	data, err := json.Marshal(jobRegisterRequest)
 
@@ -442,34 +664,66 @@ func handleRegister(r *http.Request, appLogger *slog.Logger, jobHandler *admissi
 	if len(warnings) > 0 {
 		ctx = context.WithValue(ctx, ctxWarnings, warnings)
 	}
+	ctx = context.WithValue(ctx, ctxProvenance, admission.Provenance)
 
 	appLogger.Debug("Job after admission controllers", "job", string(data))
 	r = r.WithContext(ctx)
 	rewriteRequest(r, data)
 	return r, nil
 }
+
+// applySubmissionStrategy updates the submitted job source once mutators
+// changed the job, so Nomad does not show a definition that no longer matches
+// what runs.
+func applySubmissionStrategy(req *api.JobRegisterRequest, mutations []admissionctrl.Mutation, submission *config.Submission) error {
+	if submission == nil || req.Submission == nil || len(mutations) == 0 {
+		return nil
+	}
+	switch submission.Strategy {
+	case config.SubmissionDrop:
+		req.Submission = nil
+	case config.SubmissionAnnotate:
+		if req.Job.Meta == nil {
+			req.Job.Meta = map[string]string{}
+		}
+		req.Job.Meta[submission.MetaKey] = admissionctrl.MutationSummary(mutations)
+	case config.SubmissionRegenerate:
+		data, err := json.MarshalIndent(req.Job, "", "  ")
+		if err != nil {
+			return err
+		}
+		req.Submission = &api.JobSubmission{
+			Source: string(data),
+			Format: "json",
+		}
+	}
+	return nil
+}
+
 func handlePlan(r *http.Request, appLogger *slog.Logger, jobHandler *admissionctrl.JobHandler) (*http.Request, error) {
 	body := r.Body
 	jobPlanRequest := &api.JobPlanRequest{}
 
 	if err := json.NewDecoder(body).Decode(jobPlanRequest); err != nil {
-		return r, fmt.Errorf("failed decoding job, skipping admission controller: %w", err)
+		return r, admissionctrl.NewError(admissionctrl.KindBadRequest, fmt.Errorf("failed decoding job, skipping admission controller: %w", err))
 	}
 	orginalJob := jobPlanRequest.Job
 	payload := &types.Payload{
-		Job: orginalJob,
+		Job:       orginalJob,
+		Operation: config.OperationPlan,
 	}
 
 	if reqCtx, ok := r.Context().Value(ctxRequestContext).(*config.RequestContext); ok {
+		reqCtx.PolicyOverride = reqCtx.PolicyOverride || jobPlanRequest.PolicyOverride
 		payload.Context = reqCtx
 	}
 
-	job, warnings, err := jobHandler.ApplyAdmissionControllers(r.Context(), payload)
+	admission, err := jobHandler.Admit(r.Context(), payload)
 	if err != nil {
 		return r, fmt.Errorf("admission controllers send an error, returning error: %w", err)
 	}
 
-	jobPlanRequest.Job = job
+	jobPlanRequest.Job = admission.Job
 
 	data, err := json.Marshal(jobPlanRequest)
 
@@ -477,10 +731,11 @@ func handlePlan(r *http.Request, appLogger *slog.Logger, jobHandler *admissionct
 		return r, fmt.Errorf("error marshalling job: %w", err)
 	}
 	ctx := r.Context()
-	if len(warnings) > 0 {
-		ctx = context.WithValue(ctx, ctxWarnings, warnings)
+	if len(admission.Warnings) > 0 {
+		ctx = context.WithValue(ctx, ctxWarnings, admission.Warnings)
 
 	}
+	ctx = context.WithValue(ctx, ctxProvenance, admission.Provenance)
 	r = r.WithContext(ctx)
 	appLogger.Debug("Job after admission controllers", "job", string(data))
 	rewriteRequest(r, data)
@@ -494,11 +749,12 @@ func handleValidate(r *http.Request, applogger *slog.Logger, jobHandler *admissi
 	jobValidateRequest := &api.JobValidateRequest{}
 	err := json.NewDecoder(body).Decode(jobValidateRequest)
 	if err != nil {
-		return r, err
+		return r, admissionctrl.NewError(admissionctrl.KindBadRequest, fmt.Errorf("failed decoding job, skipping admission controller: %w", err))
 	}
 	job := jobValidateRequest.Job
 	payload := &types.Payload{
-		Job: job,
+		Job:       job,
+		Operation: config.OperationValidate,
 	}
 
 	if reqCtx, ok := ctx.Value(ctxRequestContext).(*config.RequestContext); ok {
@@ -534,9 +790,247 @@ func handleValidate(r *http.Request, applogger *slog.Logger, jobHandler *admissi
 
 }
 
+// handleDispatch runs the dispatch validators against the parent job and the
+// dispatch request. The request itself is forwarded unchanged.
+func handleDispatch(r *http.Request, appLogger *slog.Logger, jobHandler *admissionctrl.JobHandler, nomadAddress *url.URL, transport http.RoundTripper) (*http.Request, error) {
+	if !jobHandler.HandlesOperation(config.OperationDispatch) {
+		return r, nil
+	}
+
+	ctx := r.Context()
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return r, admissionctrl.NewError(admissionctrl.KindBadRequest, fmt.Errorf("failed reading dispatch request: %w", err))
+	}
+	rewriteRequest(r, data)
+
+	dispatchRequest := &api.JobDispatchRequest{}
+	if err := json.Unmarshal(data, dispatchRequest); err != nil {
+		return r, admissionctrl.NewError(admissionctrl.KindBadRequest, fmt.Errorf("failed decoding dispatch request, skipping admission controller: %w", err))
+	}
+
+	jobID := jobDispatchRegex.FindStringSubmatch(r.URL.Path)[1]
+	parentJob, err := fetchJob(ctx, transport, nomadAddress, r, jobID)
+	if err != nil {
+		return r, fmt.Errorf("failed fetching parent job %q: %w", jobID, err)
+	}
+	if parentJob == nil {
+		// Nothing to admit, Nomad answers the dispatch of an unknown job itself.
+		return r, nil
+	}
+
+	payload := &types.Payload{
+		Job:       parentJob,
+		Operation: config.OperationDispatch,
+		Dispatch: &types.Dispatch{
+			Meta:             dispatchRequest.Meta,
+			PayloadSize:      len(dispatchRequest.Payload),
+			IdPrefixTemplate: dispatchRequest.IdPrefixTemplate,
+			Priority:         dispatchRequest.Priority,
+		},
+	}
+	if reqCtx, ok := ctx.Value(ctxRequestContext).(*config.RequestContext); ok {
+		payload.Context = reqCtx
+	}
+
+	warnings, err := jobHandler.AdmissionValidators(ctx, payload)
+	if err != nil {
+		return r, fmt.Errorf("admission controllers send an error, returning error: %w", err)
+	}
+	if len(warnings) > 0 {
+		ctx = context.WithValue(ctx, ctxWarnings, warnings)
+	}
+
+	appLogger.Debug("Dispatch admitted", "job", jobID)
+	return r.WithContext(ctx), nil
+}
+
+// handleDeregister runs the deregister validators against the job about to be
+// stopped. The request itself is forwarded unchanged.
+func handleDeregister(r *http.Request, appLogger *slog.Logger, jobHandler *admissionctrl.JobHandler, nomadAddress *url.URL, transport http.RoundTripper) (*http.Request, error) {
+	if !jobHandler.HandlesOperation(config.OperationDeregister) {
+		return r, nil
+	}
+
+	ctx := r.Context()
+	query := r.URL.Query()
+	purge, err := parseBoolQuery(query, "purge")
+	if err != nil {
+		return r, admissionctrl.NewError(admissionctrl.KindBadRequest, err)
+	}
+	global, err := parseBoolQuery(query, "global")
+	if err != nil {
+		return r, admissionctrl.NewError(admissionctrl.KindBadRequest, err)
+	}
+
+	jobID := jobPathRegex.FindStringSubmatch(r.URL.Path)[1]
+	job, err := fetchJob(ctx, transport, nomadAddress, r, jobID)
+	if err != nil {
+		return r, fmt.Errorf("failed fetching job %q: %w", jobID, err)
+	}
+	if job == nil {
+		// Nothing to protect, Nomad answers the deregistration of an unknown job itself.
+		return r, nil
+	}
+
+	payload := &types.Payload{
+		Job:       job,
+		Operation: config.OperationDeregister,
+		Deregister: &types.Deregister{
+			Purge:  purge,
+			Global: global,
+		},
+	}
+	if reqCtx, ok := ctx.Value(ctxRequestContext).(*config.RequestContext); ok {
+		payload.Context = reqCtx
+	}
+
+	warnings, err := jobHandler.AdmissionValidators(ctx, payload)
+	if err != nil {
+		return r, fmt.Errorf("admission controllers denied the deregistration: %w", err)
+	}
+	if len(warnings) > 0 {
+		ctx = context.WithValue(ctx, ctxWarnings, warnings)
+	}
+
+	appLogger.Debug("Deregistration admitted", "job", jobID, "purge", purge, "global", global)
+	return r.WithContext(ctx), nil
+}
+
+// handleScale admits a scaling request as a change of the target group's count.
+// Mutators and validators see the current job with the requested count applied
+// to the group; the count they leave on the group is forwarded to Nomad.
+func handleScale(r *http.Request, appLogger *slog.Logger, jobHandler *admissionctrl.JobHandler, nomadAddress *url.URL, transport http.RoundTripper) (*http.Request, error) {
+	if !jobHandler.HandlesOperation(config.OperationScale) {
+		return r, nil
+	}
+
+	ctx := r.Context()
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return r, admissionctrl.NewError(admissionctrl.KindBadRequest, fmt.Errorf("failed reading scaling request: %w", err))
+	}
+	rewriteRequest(r, data)
+
+	scalingRequest := &api.ScalingRequest{}
+	if err := json.Unmarshal(data, scalingRequest); err != nil {
+		return r, admissionctrl.NewError(admissionctrl.KindBadRequest, fmt.Errorf("failed decoding scaling request, skipping admission controller: %w", err))
+	}
+	groupName := scalingRequest.Target["Group"]
+	if scalingRequest.Count == nil || groupName == "" {
+		// Scaling events without a count do not change the job.
+		return r, nil
+	}
+
+	jobID := jobScaleRegex.FindStringSubmatch(r.URL.Path)[1]
+	job, err := fetchJob(ctx, transport, nomadAddress, r, jobID)
+	if err != nil {
+		return r, fmt.Errorf("failed fetching job %q: %w", jobID, err)
+	}
+	group := findTaskGroup(job, groupName)
+	if group == nil {
+		// Nomad rejects scaling an unknown job or group itself.
+		return r, nil
+	}
+
+	scale := &types.Scale{
+		Group:   groupName,
+		Count:   int(*scalingRequest.Count),
+		Scaling: group.Scaling,
+		Message: scalingRequest.Message,
+		Meta:    scalingRequest.Meta,
+	}
+	if group.Count != nil {
+		scale.PreviousCount = *group.Count
+	}
+	group.Count = &scale.Count
+
+	payload := &types.Payload{
+		Job:       job,
+		Operation: config.OperationScale,
+		Scale:     scale,
+	}
+	if reqCtx, ok := ctx.Value(ctxRequestContext).(*config.RequestContext); ok {
+		payload.Context = reqCtx
+	}
+
+	admittedJob, warnings, err := jobHandler.ApplyAdmissionControllers(ctx, payload)
+	if err != nil {
+		return r, fmt.Errorf("admission controllers send an error, returning error: %w", err)
+	}
+
+	admittedGroup := findTaskGroup(admittedJob, groupName)
+	if admittedGroup == nil || admittedGroup.Count == nil {
+		return r, admissionctrl.NewError(admissionctrl.KindInternal, fmt.Errorf("admission controllers removed the count of task group %q", groupName))
+	}
+	if count := int64(*admittedGroup.Count); count != *scalingRequest.Count {
+		appLogger.Debug("Scaling count changed by admission controllers", "job", jobID, "group", groupName, "requested", *scalingRequest.Count, "admitted", count)
+		scalingRequest.Count = &count
+		data, err = json.Marshal(scalingRequest)
+		if err != nil {
+			return r, fmt.Errorf("error marshalling scaling request: %w", err)
+		}
+		rewriteRequest(r, data)
+	}
+
+	if len(warnings) > 0 {
+		ctx = context.WithValue(ctx, ctxWarnings, warnings)
+	}
+	return r.WithContext(ctx), nil
+}
+
+func findTaskGroup(job *api.Job, name string) *api.TaskGroup {
+	if job == nil {
+		return nil
+	}
+	for _, group := range job.TaskGroups {
+		if group.Name != nil && *group.Name == name {
+			return group
+		}
+	}
+	return nil
+}
+
+func parseBoolQuery(query url.Values, key string) (bool, error) {
+	value := query.Get(key)
+	if value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s value %q: %w", key, value, err)
+	}
+	return parsed, nil
+}
+
+// writeError answers with the status of the admission error in err's chain.
+// Like Nomad, the body is plain text, which Nomad clients show verbatim; it
+// names the controllers that rejected the request or failed.
 func writeError(w http.ResponseWriter, err error) {
-	w.WriteHeader(http.StatusInternalServerError)
-	w.Write([]byte(err.Error()))
+	status, body := renderError(err)
+	setErrorHeaders(w.Header())
+	w.WriteHeader(status)
+	w.Write([]byte(body))
+}
+
+// renderError returns the status code and plain-text body err is reported
+// to clients with.
+func renderError(err error) (int, string) {
+	admissionErr := admissionctrl.AsError(err)
+	body := strings.TrimSpace(err.Error())
+	if names := strings.Join(admissionErr.Controllers, ", "); names != "" {
+		if admissionErr.Rejected() {
+			body = fmt.Sprintf("rejected by admission controllers %s: %s", names, body)
+		} else {
+			body = fmt.Sprintf("admission controllers %s failed: %s", names, body)
+		}
+	}
+	return admissionErr.StatusCode(), body
+}
+
+func setErrorHeaders(header http.Header) {
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	header.Set("X-Content-Type-Options", "nosniff")
 }
 func isRegister(r *http.Request) bool {
 	isRegister := isCreate(r) || isUpdate(r)
@@ -559,6 +1053,29 @@ func isValidate(r *http.Request) bool {
 
 	return (r.Method == "PUT" || r.Method == "POST") && r.URL.Path == "/v1/validate/job"
 }
+func isDeregister(r *http.Request) bool {
+
+	return r.Method == "DELETE" && jobPathRegex.MatchString(r.URL.Path)
+}
+func isParse(r *http.Request) bool {
+
+	return (r.Method == "PUT" || r.Method == "POST") && r.URL.Path == "/v1/jobs/parse"
+}
+
+// isParseMutation reports whether r is a parse request whose result the
+// mutators should be applied to.
+func isParseMutation(r *http.Request) bool {
+	mutateParse, _ := r.Context().Value(ctxMutateParse).(bool)
+	return mutateParse && isParse(r)
+}
+func isScale(r *http.Request) bool {
+
+	return (r.Method == "PUT" || r.Method == "POST") && jobScaleRegex.MatchString(r.URL.Path)
+}
+func isDispatch(r *http.Request) bool {
+
+	return (r.Method == "PUT" || r.Method == "POST") && jobDispatchRegex.MatchString(r.URL.Path)
+}
 
 func buildSlogHandler(json bool, level slog.Level) slog.Handler {
 	opts := &slog.HandlerOptions{
@@ -627,11 +1144,15 @@ func run(c *config.Config) (err error) {
 		defer stopOPA()
 	}
 
-	server, err := buildServer(c, rootFactory, opaSDK)
+	server, controllers, err := buildServer(c, rootFactory, opaSDK)
 
 	if err != nil {
 		return fmt.Errorf("failed to build server: %w", err)
 	}
+	// plugin processes, wasm runtimes and gRPC connections outlive requests
+	defer func() {
+		err = errors.Join(err, controllers.Close())
+	}()
 
 	srvErr := make(chan error, 1)
 
@@ -669,13 +1190,15 @@ func run(c *config.Config) (err error) {
 
 }
 
-func buildServer(c *config.Config, loggerFactory *logutil.LoggerFactory, sdk *sdk.OPA) (*http.Server, error) {
+// buildServer builds the server and the job handler serving it, which has
+// to be closed when the server stopped.
+func buildServer(c *config.Config, loggerFactory *logutil.LoggerFactory, sdk *sdk.OPA) (*http.Server, *admissionctrl.JobHandler, error) {
 	if err := c.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid config: %w", err)
+		return nil, nil, fmt.Errorf("invalid config: %w", err)
 	}
 	backend, err := url.Parse(c.Nomad.Address)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse nomad address: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse nomad address: %w", err)
 	}
 	proxyTransport := http.DefaultTransport.(*http.Transport).Clone()
 	proxyTransport.DialContext = (&net.Dialer{
@@ -689,20 +1212,25 @@ func buildServer(c *config.Config, loggerFactory *logutil.LoggerFactory, sdk *sd
 	if c.Nomad.TLS != nil {
 		nomadTlsConfig, err := buildTlsConfig(*c.Nomad.TLS)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create custom transport: %w", err)
+			return nil, nil, fmt.Errorf("failed to create custom transport: %w", err)
 
 		}
 		proxyTransport.TLSClientConfig = nomadTlsConfig
 	}
 
+	handlerOptions, err := jobHandlerOptions(c)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create job handler options: %w", err)
+	}
+
 	jobMutators, resolveTokenMutators, err := createMutators(c, loggerFactory, sdk)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create mutators: %w", err)
+		return nil, nil, fmt.Errorf("failed to create mutators: %w", err)
 	}
 
 	jobValidators, resolveTokenValidators, err := createValidators(c, loggerFactory, sdk)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create validators: %w", err)
+		return nil, nil, errors.Join(fmt.Errorf("failed to create validators: %w", err), admissionctrl.CloseControllers(jobMutators, nil))
 	}
 
 	var resolveToken bool
@@ -716,9 +1244,13 @@ func buildServer(c *config.Config, loggerFactory *logutil.LoggerFactory, sdk *sd
 		jobValidators,
 		loggerFactory.GetLogger("handler"),
 		resolveToken,
+		handlerOptions...,
 	)
 
-	handlerFunc := NewProxyAsHandlerFunc(backend, jobHandler, loggerFactory.GetLogger("proxy-handler"), instrumentedProxyTransport)
+	handlerFunc := NewProxyAsHandlerFunc(backend, jobHandler, loggerFactory.GetLogger("proxy-handler"), instrumentedProxyTransport,
+		WithParseMutation(c.MutateParsedJobs),
+		WithSubmission(c.Submission),
+	)
 
 	bind := fmt.Sprintf("%s:%d", c.Bind, c.Port)
 	var tlsConfig *tls.Config
@@ -728,7 +1260,7 @@ func buildServer(c *config.Config, loggerFactory *logutil.LoggerFactory, sdk *sd
 		if c.Tls.CaFile != "" {
 			tlsConfig, err = createTlsConfig(c.Tls.CaFile, c.Tls.NoClientCert)
 			if err != nil {
-				return nil, fmt.Errorf("failed to create tls config: %w", err)
+				return nil, nil, errors.Join(fmt.Errorf("failed to create tls config: %w", err), jobHandler.Close())
 			}
 		}
 	}
@@ -742,7 +1274,88 @@ func buildServer(c *config.Config, loggerFactory *logutil.LoggerFactory, sdk *sd
 		WriteTimeout:      nomadTimeout,
 		IdleTimeout:       120 * time.Second,
 	}
-	return server, nil
+	return server, jobHandler, nil
+}
+
+// jobHandlerOptions translates the per-controller settings of c into options
+// for the job handler.
+func jobHandlerOptions(c *config.Config) ([]admissionctrl.Option, error) {
+	opts := make([]admissionctrl.Option, 0, len(c.Mutators)+len(c.Validators)+2)
+	opts = append(opts, admissionctrl.WithMode(c.Mode), admissionctrl.WithMaxConcurrency(c.MaxConcurrency), admissionctrl.WithMaxMutatorPasses(c.MaxMutatorPasses))
+	if c.Provenance != nil {
+		opts = append(opts, admissionctrl.WithProvenance(c.Provenance.MetaKey))
+	}
+	for _, mutatorConfig := range c.Mutators {
+		match, err := buildMatch(mutatorConfig.Match)
+		if err != nil {
+			return nil, fmt.Errorf("mutator %q: %w", mutatorConfig.Name, err)
+		}
+		settings := admissionctrl.MutatorSettings{
+			Operations:         mutatorConfig.Operations,
+			Mode:               mutatorConfig.Mode,
+			Match:              match,
+			FailurePolicy:      mutatorConfig.FailurePolicy,
+			ReinvocationPolicy: mutatorConfig.ReinvocationPolicy,
+		}
+		if settings.Timeout, err = parseTimeout(mutatorConfig.Timeout); err != nil {
+			return nil, fmt.Errorf("mutator %q: %w", mutatorConfig.Name, err)
+		}
+		opts = append(opts, admissionctrl.WithMutatorSettings(mutatorConfig.Name, settings))
+	}
+	for _, validatorConfig := range c.Validators {
+		match, err := buildMatch(validatorConfig.Match)
+		if err != nil {
+			return nil, fmt.Errorf("validator %q: %w", validatorConfig.Name, err)
+		}
+		settings := admissionctrl.ValidatorSettings{
+			Operations:       validatorConfig.Operations,
+			EnforcementLevel: validatorConfig.EnforcementLevel,
+			Mode:             validatorConfig.Mode,
+			Match:            match,
+			FailurePolicy:    validatorConfig.FailurePolicy,
+		}
+		if settings.Timeout, err = parseTimeout(validatorConfig.Timeout); err != nil {
+			return nil, fmt.Errorf("validator %q: %w", validatorConfig.Name, err)
+		}
+		if validatorConfig.Override != nil {
+			settings.OverridePolicies = validatorConfig.Override.Policies
+			settings.OverrideRoles = validatorConfig.Override.Roles
+		}
+		opts = append(opts, admissionctrl.WithValidatorSettings(validatorConfig.Name, settings))
+	}
+	return opts, nil
+}
+
+func parseTimeout(timeout string) (time.Duration, error) {
+	if timeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout: %w", err)
+	}
+	return d, nil
+}
+
+func buildMatch(matchConfig *config.Match) (*admissionctrl.Match, error) {
+	if matchConfig == nil {
+		return nil, nil
+	}
+	match := &admissionctrl.Match{
+		Namespaces: matchConfig.Namespaces,
+		JobTypes:   matchConfig.JobTypes,
+		Regions:    matchConfig.Regions,
+		NodePools:  matchConfig.NodePools,
+		Meta:       matchConfig.Meta,
+	}
+	if matchConfig.JobID != "" {
+		jobIDRegex, err := regexp.Compile(matchConfig.JobID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid match job_id: %w", err)
+		}
+		match.JobID = jobIDRegex
+	}
+	return match, nil
 }
 
 func buildConfig(configPath string) (*config.Config, error) {
@@ -793,7 +1406,7 @@ func createMutators(c *config.Config, loggerFactory *logutil.LoggerFactory, opaS
 		resolveToken = resolveToken || mutatorConfig.ResolveToken
 		jobMutator, err := createMutator(mutatorConfig, loggerFactory, opaSDK)
 		if err != nil {
-			return nil, resolveToken, err
+			return nil, resolveToken, errors.Join(err, admissionctrl.CloseControllers(jobMutators, nil))
 		}
 		jobMutators = append(jobMutators, jobMutator)
 	}
@@ -815,25 +1428,206 @@ func createMutator(mutatorConfig config.Mutator, loggerFactory *logutil.LoggerFa
 		if mutatorConfig.Webhook == nil {
 			return nil, fmt.Errorf("mutator %q requires a webhook block", mutatorConfig.Name)
 		}
-		return mutator.NewJsonPatchWebhookMutator(mutatorConfig.Name, mutatorConfig.Webhook.Endpoint, mutatorConfig.Webhook.Method, loggerFactory.GetLogger("json_patch_webhook_mutator"))
+		signingSecret, err := readSigningSecret(mutatorConfig.Webhook)
+		if err != nil {
+			return nil, err
+		}
+		return mutator.NewJsonPatchWebhookMutator(mutatorConfig.Name, mutatorConfig.Webhook.Endpoint, mutatorConfig.Webhook.Method, signingSecret, loggerFactory.GetLogger("json_patch_webhook_mutator"))
+	case "webhook":
+		if mutatorConfig.Webhook == nil {
+			return nil, fmt.Errorf("mutator %q requires a webhook block", mutatorConfig.Name)
+		}
+		signingSecret, err := readSigningSecret(mutatorConfig.Webhook)
+		if err != nil {
+			return nil, err
+		}
+		return mutator.NewWebhookMutator(mutatorConfig.Name, mutatorConfig.Webhook.Endpoint, mutatorConfig.Webhook.Method, signingSecret, loggerFactory.GetLogger("webhook_mutator"))
 	case "opa_bundle_json_patch":
 		if mutatorConfig.OpaSdkRule == nil {
 			return nil, fmt.Errorf("mutator %q requires an opa_sdk_rule block", mutatorConfig.Name)
 		}
 		return mutator.NewOpaBundleMutator(mutatorConfig.Name, mutatorConfig.OpaSdkRule.Path, loggerFactory.GetLogger("opa_bundle_mutator"), opaSDK)
+	case "defaults":
+		if mutatorConfig.Defaults == nil {
+			return nil, fmt.Errorf("mutator %q requires a defaults block", mutatorConfig.Name)
+		}
+		return mutator.NewDefaultsMutator(mutatorConfig.Name, buildJobDefaults(mutatorConfig.Defaults), buildScope(mutatorConfig.Defaults.Scope), mutatorConfig.Defaults.Overwrite), nil
+	case "cel":
+		if mutatorConfig.Cel == nil {
+			return nil, fmt.Errorf("mutator %q requires a cel block", mutatorConfig.Name)
+		}
+		return mutator.NewCelMutator(mutatorConfig.Name, mutatorConfig.Cel.Mutations, loggerFactory.GetLogger("cel_mutator"))
+	case "wasm":
+		if mutatorConfig.Wasm == nil {
+			return nil, fmt.Errorf("mutator %q requires a wasm block", mutatorConfig.Name)
+		}
+		options, err := buildWasmOptions(mutatorConfig.Wasm)
+		if err != nil {
+			return nil, err
+		}
+		return mutator.NewWasmMutator(mutatorConfig.Name, mutatorConfig.Wasm.Module, options, loggerFactory.GetLogger("wasm_mutator"))
+	case "exec":
+		if mutatorConfig.Exec == nil {
+			return nil, fmt.Errorf("mutator %q requires an exec block", mutatorConfig.Name)
+		}
+		options, err := buildExecOptions(mutatorConfig.Exec)
+		if err != nil {
+			return nil, err
+		}
+		return mutator.NewExecMutator(mutatorConfig.Name, options, loggerFactory.GetLogger("exec_mutator"))
+	case "grpc_mutator":
+		if mutatorConfig.Grpc == nil {
+			return nil, fmt.Errorf("mutator %q requires a grpc block", mutatorConfig.Name)
+		}
+		options, err := buildGrpcOptions(mutatorConfig.Grpc)
+		if err != nil {
+			return nil, err
+		}
+		return mutator.NewGrpcMutator(mutatorConfig.Name, options, loggerFactory.GetLogger("grpc_mutator"))
+	case "inject_meta":
+		injectMeta := mutatorConfig.InjectMeta
+		if injectMeta == nil || len(injectMeta.Meta) == 0 {
+			return nil, fmt.Errorf("mutator %q requires an inject_meta block with meta", mutatorConfig.Name)
+		}
+		return mutator.NewInjectMetaMutator(mutatorConfig.Name, injectMeta.Meta, injectMeta.Target, buildScope(injectMeta.Scope), injectMeta.Overwrite), nil
+	case "add_constraint":
+		addConstraint := mutatorConfig.AddConstraint
+		if addConstraint == nil || addConstraint.Constraint == nil {
+			return nil, fmt.Errorf("mutator %q requires an add_constraint block with a constraint", mutatorConfig.Name)
+		}
+		return mutator.NewConstraintMutator(mutatorConfig.Name, buildConstraint(addConstraint.Constraint), addConstraint.Target, buildScope(addConstraint.Scope)), nil
 	default:
 		return nil, fmt.Errorf("unknown mutator type %s", mutatorConfig.Type)
 	}
 }
 
+func buildJobDefaults(defaultsConfig *config.JobDefaults) mutator.JobDefaults {
+	defaults := mutator.JobDefaults{
+		Namespace:   defaultsConfig.Namespace,
+		NodePool:    defaultsConfig.NodePool,
+		Datacenters: defaultsConfig.Datacenters,
+	}
+	if defaultsConfig.Resources != nil {
+		defaults.Resources = buildResources(defaultsConfig.Resources)
+	}
+	return defaults
+}
+
+func buildResources(resources *config.TaskResources) *api.Resources {
+	return &api.Resources{
+		CPU:         resources.CPU,
+		Cores:       resources.Cores,
+		MemoryMB:    resources.MemoryMB,
+		MemoryMaxMB: resources.MemoryMaxMB,
+	}
+}
+
+func buildConstraint(constraintConfig *config.Constraint) *api.Constraint {
+	operator := constraintConfig.Operator
+	if operator == "" {
+		operator = "="
+	}
+	return &api.Constraint{
+		LTarget: constraintConfig.Attribute,
+		RTarget: constraintConfig.Value,
+		Operand: operator,
+	}
+}
+
+func buildScope(scopeConfig *config.Scope) *types.Scope {
+	if scopeConfig == nil {
+		return nil
+	}
+	return &types.Scope{
+		TaskGroups: scopeConfig.TaskGroups,
+		Tasks:      scopeConfig.Tasks,
+	}
+}
+
+func buildWasmOptions(wasmConfig *config.Wasm) (wasmutil.Options, error) {
+	options := wasmutil.Options{PoolSize: wasmConfig.PoolSize}
+	if wasmConfig.MemoryLimitMB != nil {
+		options.MemoryLimitMB = *wasmConfig.MemoryLimitMB
+	}
+	if wasmConfig.CallTimeout != "" {
+		timeout, err := time.ParseDuration(wasmConfig.CallTimeout)
+		if err != nil {
+			return options, fmt.Errorf("invalid wasm call_timeout: %w", err)
+		}
+		options.CallTimeout = timeout
+	}
+	return options, nil
+}
+
+// readSigningSecret reads the secret webhook requests are signed with, if
+// one is configured.
+func readSigningSecret(webhook *config.Webhook) ([]byte, error) {
+	if webhook.SigningSecretFile == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(webhook.SigningSecretFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook signing secret: %w", err)
+	}
+	secret := bytes.TrimSpace(data)
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("webhook signing secret file %s is empty", webhook.SigningSecretFile)
+	}
+	return secret, nil
+}
+
+func buildExecOptions(execConfig *config.Exec) (executil.Options, error) {
+	options := executil.Options{
+		Command:         execConfig.Command,
+		Args:            execConfig.Args,
+		Env:             execConfig.Env,
+		RejectExitCodes: execConfig.RejectExitCodes,
+		Persistent:      execConfig.Persistent,
+	}
+	if execConfig.CallTimeout != "" {
+		timeout, err := time.ParseDuration(execConfig.CallTimeout)
+		if err != nil {
+			return options, fmt.Errorf("invalid exec call_timeout: %w", err)
+		}
+		options.CallTimeout = timeout
+	}
+	return options, nil
+}
+
+func buildGrpcOptions(grpcConfig *config.Grpc) (grpcutil.Options, error) {
+	options := grpcutil.Options{Address: grpcConfig.Address}
+	if grpcConfig.CallTimeout != "" {
+		timeout, err := time.ParseDuration(grpcConfig.CallTimeout)
+		if err != nil {
+			return options, fmt.Errorf("invalid grpc call_timeout: %w", err)
+		}
+		options.CallTimeout = timeout
+	}
+	if grpcConfig.TLS != nil {
+		tlsConfig, err := buildTlsConfig(config.NomadServerTLS{
+			CaFile:             grpcConfig.TLS.CaFile,
+			CertFile:           grpcConfig.TLS.CertFile,
+			KeyFile:            grpcConfig.TLS.KeyFile,
+			InsecureSkipVerify: grpcConfig.TLS.InsecureSkipVerify,
+		})
+		if err != nil {
+			return options, fmt.Errorf("failed to build grpc TLS config: %w", err)
+		}
+		tlsConfig.ServerName = grpcConfig.TLS.ServerName
+		options.TLS = tlsConfig
+	}
+	return options, nil
+}
+
 func createValidators(c *config.Config, loggerFactory *logutil.LoggerFactory, opaSDK *sdk.OPA) ([]admissionctrl.JobValidator, bool, error) {
 	jobValidators := make([]admissionctrl.JobValidator, 0, len(c.Validators))
 	var resolveToken bool
 	for _, validatorConfig := range c.Validators {
-		resolveToken = resolveToken || validatorConfig.ResolveToken
+		// overrides are granted by the caller's token policies and roles
+		resolveToken = resolveToken || validatorConfig.ResolveToken || validatorConfig.Override != nil
 		jobValidator, err := createValidator(validatorConfig, loggerFactory, opaSDK)
 		if err != nil {
-			return nil, resolveToken, err
+			return nil, resolveToken, errors.Join(err, admissionctrl.CloseControllers(nil, jobValidators))
 		}
 		jobValidators = append(jobValidators, jobValidator)
 	}
@@ -860,13 +1654,90 @@ func createValidator(validatorConfig config.Validator, loggerFactory *logutil.Lo
 		if validatorConfig.Webhook == nil {
 			return nil, fmt.Errorf("validator %q requires a webhook block", validatorConfig.Name)
 		}
-		return validator.NewWebhookValidator(validatorConfig.Name, validatorConfig.Webhook.Endpoint, validatorConfig.Webhook.Method, loggerFactory.GetLogger("webhook_validator"))
+		signingSecret, err := readSigningSecret(validatorConfig.Webhook)
+		if err != nil {
+			return nil, err
+		}
+		return validator.NewWebhookValidator(validatorConfig.Name, validatorConfig.Webhook.Endpoint, validatorConfig.Webhook.Method, signingSecret, loggerFactory.GetLogger("webhook_validator"))
 	case "notation":
 		notationVerifier, err := buildVerifier(validatorConfig.Notation, loggerFactory.GetLogger("notation_verifier"))
 		if err != nil {
 			return nil, err
 		}
 		return validator.NewNotationValidator(loggerFactory.GetLogger("notation_validator"), validatorConfig.Name, notationVerifier), nil
+	case "cel":
+		if validatorConfig.Cel == nil {
+			return nil, fmt.Errorf("validator %q requires a cel block", validatorConfig.Name)
+		}
+		return validator.NewCelValidator(validatorConfig.Name, validatorConfig.Cel.Validations, loggerFactory.GetLogger("cel_validator"))
+	case "json_schema":
+		if validatorConfig.JSONSchema == nil {
+			return nil, fmt.Errorf("validator %q requires a json_schema block", validatorConfig.Name)
+		}
+		return validator.NewJSONSchemaValidator(validatorConfig.Name, validatorConfig.JSONSchema.Files, validatorConfig.JSONSchema.Payload, loggerFactory.GetLogger("json_schema_validator"))
+	case "wasm":
+		if validatorConfig.Wasm == nil {
+			return nil, fmt.Errorf("validator %q requires a wasm block", validatorConfig.Name)
+		}
+		options, err := buildWasmOptions(validatorConfig.Wasm)
+		if err != nil {
+			return nil, err
+		}
+		return validator.NewWasmValidator(validatorConfig.Name, validatorConfig.Wasm.Module, options, loggerFactory.GetLogger("wasm_validator"))
+	case "exec":
+		if validatorConfig.Exec == nil {
+			return nil, fmt.Errorf("validator %q requires an exec block", validatorConfig.Name)
+		}
+		options, err := buildExecOptions(validatorConfig.Exec)
+		if err != nil {
+			return nil, err
+		}
+		return validator.NewExecValidator(validatorConfig.Name, options, loggerFactory.GetLogger("exec_validator"))
+	case "grpc_validator":
+		if validatorConfig.Grpc == nil {
+			return nil, fmt.Errorf("validator %q requires a grpc block", validatorConfig.Name)
+		}
+		options, err := buildGrpcOptions(validatorConfig.Grpc)
+		if err != nil {
+			return nil, err
+		}
+		return validator.NewGrpcValidator(validatorConfig.Name, options, loggerFactory.GetLogger("grpc_validator"))
+	case "resource_limits":
+		resourceLimits := validatorConfig.ResourceLimits
+		if resourceLimits == nil || resourceLimits.Limits == nil {
+			return nil, fmt.Errorf("validator %q requires a resource_limits block with limits", validatorConfig.Name)
+		}
+		return validator.NewResourceLimitsValidator(validatorConfig.Name, buildResources(resourceLimits.Limits), buildScope(resourceLimits.Scope), resourceLimits.Message), nil
+	case "allowed_drivers":
+		allowedDrivers := validatorConfig.AllowedDrivers
+		if allowedDrivers == nil {
+			return nil, fmt.Errorf("validator %q requires an allowed_drivers block", validatorConfig.Name)
+		}
+		return validator.NewAllowedDriversValidator(validatorConfig.Name, allowedDrivers.Drivers, buildScope(allowedDrivers.Scope), allowedDrivers.Message), nil
+	case "required_meta":
+		requiredMeta := validatorConfig.RequiredMeta
+		if requiredMeta == nil {
+			return nil, fmt.Errorf("validator %q requires a required_meta block", validatorConfig.Name)
+		}
+		return validator.NewRequiredMetaValidator(validatorConfig.Name, requiredMeta.Keys, requiredMeta.Target, buildScope(requiredMeta.Scope), requiredMeta.Message), nil
+	case "image_registries":
+		imageRegistries := validatorConfig.ImageRegistries
+		if imageRegistries == nil {
+			return nil, fmt.Errorf("validator %q requires an image_registries block", validatorConfig.Name)
+		}
+		return validator.NewImageRegistryValidator(validatorConfig.Name, imageRegistries.Registries, buildScope(imageRegistries.Scope), imageRegistries.Message), nil
+	case "forbid_privileged":
+		forbidPrivileged := validatorConfig.ForbidPrivileged
+		if forbidPrivileged == nil {
+			forbidPrivileged = &config.ForbidPrivileged{}
+		}
+		return validator.NewPrivilegedValidator(validatorConfig.Name, buildScope(forbidPrivileged.Scope), forbidPrivileged.Message), nil
+	case "max_parallel":
+		maxParallel := validatorConfig.MaxParallel
+		if maxParallel == nil {
+			return nil, fmt.Errorf("validator %q requires a max_parallel block", validatorConfig.Name)
+		}
+		return validator.NewMaxParallelValidator(validatorConfig.Name, maxParallel.Max, buildScope(maxParallel.Scope), maxParallel.Message), nil
 	default:
 		return nil, fmt.Errorf("unknown validator type %s", validatorConfig.Type)
 	}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"compress/gzip"
+	"context"
 	"crypto/x509"
 	"encoding/json"
 	"errors"
@@ -306,6 +307,35 @@ func TestProxy(t *testing.T) {
 			},
 			mutators: []admissionctrl.JobMutator{},
 		},
+		{
+			name:   "validate job shows violation paths",
+			path:   "/v1/validate/job",
+			method: "PUT",
+
+			requestSender: func(c *api.Client) (interface{}, any, error) {
+				return c.Jobs().Validate(testutil.BaseJob(), nil)
+			},
+			wantNomadRequestJson: toJson(t, &api.JobValidateRequest{Job: testutil.BaseJob()}),
+
+			wantProxyResponse: &api.JobValidateResponse{
+				ValidationErrors: []string{"[MEM001] /TaskGroups/0/Tasks/0/Resources/MemoryMB: memory too high"},
+				Error:            "1 error occurred:\n\t* [MEM001] /TaskGroups/0/Tasks/0/Resources/MemoryMB: memory too high\n\n",
+			},
+
+			nomadResponse: toJson(t, &api.JobValidateResponse{}),
+			validators: []admissionctrl.JobValidator{
+				func() *testutil.MockValidator {
+					validator := new(testutil.MockValidator)
+					validator.On("Validate", mock.Anything, mock.Anything).Return([]error{}, multierror.Append(nil, &types.Violation{
+						Message: "memory too high",
+						Code:    "MEM001",
+						Path:    "/TaskGroups/0/Tasks/0/Resources/MemoryMB",
+					}))
+					return validator
+				}(),
+			},
+			mutators: []admissionctrl.JobMutator{},
+		},
 		{
 			name:   "validate job appends warnings and handles gzip",
 			path:   "/v1/validate/job",
@@ -706,10 +736,698 @@ func TestAdmissionControllerErrors(t *testing.T) {
 	jobRequestJson := registerRequestJson(t, testutil.ReadJob(t, "job.json"))
 	res, err := sendPut(t, fmt.Sprintf("%s%s", proxyServer.URL, "/v1/jobs"), strings.NewReader(jobRequestJson))
 	require.NoError(t, err, "No http call error")
-	assert.Equal(t, 500, res.StatusCode, "Should return 400")
-	_, err = io.ReadAll(res.Body)
+	assert.Equal(t, http.StatusBadGateway, res.StatusCode, "Should return 502")
+	body, err := io.ReadAll(res.Body)
 	require.NoError(t, err)
+	assert.Contains(t, string(body), "admission controllers mock-validator failed:")
+
+}
+
+func TestDispatchAdmission(t *testing.T) {
+	parentJob := testutil.ReadJob(t, "job.json")
+	dispatchRequest := &api.JobDispatchRequest{
+		JobID:   "example",
+		Meta:    map[string]string{"input": "data.csv"},
+		Payload: []byte("hello"),
+	}
+
+	tests := []struct {
+		name              string
+		validator         func() *testutil.MockValidator
+		operations        []string
+		wantStatus        int
+		wantWarnings      []string
+		wantJobFetched    bool
+		wantDispatchProxy bool
+	}{
+		{
+			name: "dispatch validator sees parent job and dispatch request",
+			validator: func() *testutil.MockValidator {
+				validator := new(testutil.MockValidator)
+				validator.On("Validate", mock.Anything, mock.MatchedBy(func(payload *types.Payload) bool {
+					return payload.Operation == config.OperationDispatch &&
+						*payload.Job.ID == *parentJob.ID &&
+						payload.Dispatch.Meta["input"] == "data.csv" &&
+						payload.Dispatch.PayloadSize == len("hello")
+				})).Return([]error{errors.New("large dispatch")}, nil)
+				return validator
+			},
+			operations:        []string{config.OperationDispatch},
+			wantStatus:        http.StatusOK,
+			wantWarnings:      []string{"large dispatch"},
+			wantJobFetched:    true,
+			wantDispatchProxy: true,
+		},
+		{
+			name: "dispatch validator error rejects the dispatch",
+			validator: func() *testutil.MockValidator {
+				return testutil.MockValidatorRejecting("dispatch denied")
+			},
+			operations:     []string{config.OperationDispatch},
+			wantStatus:     http.StatusForbidden,
+			wantJobFetched: true,
+		},
+		{
+			name: "job validators do not run for dispatches",
+			validator: func() *testutil.MockValidator {
+				return new(testutil.MockValidator)
+			},
+			wantStatus:        http.StatusOK,
+			wantDispatchProxy: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var jobFetched, dispatchProxied bool
+			nomadDummy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				switch {
+				case req.Method == http.MethodGet && req.URL.Path == "/v1/job/example":
+					jobFetched = true
+					assert.Equal(t, "test-token", req.Header.Get("X-Nomad-Token"))
+					assert.Equal(t, "prod", req.URL.Query().Get("namespace"))
+					json.NewEncoder(rw).Encode(parentJob)
+				case req.URL.Path == "/v1/job/example/dispatch":
+					dispatchProxied = true
+					body, err := io.ReadAll(req.Body)
+					require.NoError(t, err)
+					assert.JSONEq(t, toJson(t, dispatchRequest), string(body), "dispatch request is forwarded unchanged")
+					json.NewEncoder(rw).Encode(&api.JobDispatchResponse{DispatchedJobID: "example/dispatch-1"})
+				default:
+					t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+				}
+			}))
+			defer nomadDummy.Close()
+
+			nomad, err := url.Parse(nomadDummy.URL)
+			require.NoError(t, err)
+
+			validator := tc.validator()
+			jobHandler := admissionctrl.NewJobHandler(
+				[]admissionctrl.JobMutator{},
+				[]admissionctrl.JobValidator{validator},
+				slog.New(slog.DiscardHandler),
+				false,
+				admissionctrl.WithValidatorSettings(validator.Name(), admissionctrl.ValidatorSettings{Operations: tc.operations}),
+			)
+			proxy := NewProxyAsHandlerFunc(nomad, jobHandler, slog.New(slog.DiscardHandler), nil)
+			proxyServer := httptest.NewServer(proxy)
+			defer proxyServer.Close()
+
+			req, err := http.NewRequest(http.MethodPut, proxyServer.URL+"/v1/job/example/dispatch?namespace=prod", strings.NewReader(toJson(t, dispatchRequest)))
+			require.NoError(t, err)
+			req.Header.Set("X-Nomad-Token", "test-token")
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.wantStatus, res.StatusCode)
+			assert.Equal(t, tc.wantJobFetched, jobFetched, "parent job lookup")
+			assert.Equal(t, tc.wantDispatchProxy, dispatchProxied, "dispatch forwarded to Nomad")
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			response := &api.JobDispatchResponse{}
+			require.NoError(t, json.NewDecoder(res.Body).Decode(response))
+			assert.Equal(t, "example/dispatch-1", response.DispatchedJobID)
+			assert.Equal(t, tc.wantWarnings, res.Header.Values(warningsHeader))
+			validator.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeregisterAdmission(t *testing.T) {
+	job := testutil.ReadJob(t, "job.json")
+
+	tests := []struct {
+		name             string
+		validator        func() *testutil.MockValidator
+		operations       []string
+		jobExists        bool
+		wantErr          string
+		wantJobFetched   bool
+		wantDeregistered bool
+	}{
+		{
+			name: "deregister validator sees job and flags",
+			validator: func() *testutil.MockValidator {
+				validator := new(testutil.MockValidator)
+				validator.On("Validate", mock.Anything, mock.MatchedBy(func(payload *types.Payload) bool {
+					return payload.Operation == config.OperationDeregister &&
+						*payload.Job.ID == *job.ID &&
+						payload.Deregister.Purge && payload.Deregister.Global
+				})).Return([]error{}, nil)
+				return validator
+			},
+			operations:       []string{config.OperationDeregister},
+			jobExists:        true,
+			wantJobFetched:   true,
+			wantDeregistered: true,
+		},
+		{
+			name: "denied deregistration returns 403",
+			validator: func() *testutil.MockValidator {
+				return testutil.MockValidatorRejecting("production jobs must not be purged")
+			},
+			operations:     []string{config.OperationDeregister},
+			jobExists:      true,
+			wantErr:        "Unexpected response code: 403 (rejected by admission controllers mock-validator: admission controllers denied the deregistration",
+			wantJobFetched: true,
+		},
+		{
+			name: "unknown job is forwarded to nomad",
+			validator: func() *testutil.MockValidator {
+				return new(testutil.MockValidator)
+			},
+			operations:       []string{config.OperationDeregister},
+			wantJobFetched:   true,
+			wantDeregistered: true,
+		},
+		{
+			name: "job validators do not run for deregistrations",
+			validator: func() *testutil.MockValidator {
+				return new(testutil.MockValidator)
+			},
+			jobExists:        true,
+			wantDeregistered: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var jobFetched, deregistered bool
+			nomadDummy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				switch req.Method {
+				case http.MethodGet:
+					jobFetched = true
+					if !tc.jobExists {
+						rw.WriteHeader(http.StatusNotFound)
+						return
+					}
+					json.NewEncoder(rw).Encode(job)
+				case http.MethodDelete:
+					deregistered = true
+					json.NewEncoder(rw).Encode(&api.JobDeregisterResponse{EvalID: "eval-1"})
+				}
+			}))
+			defer nomadDummy.Close()
+
+			nomad, err := url.Parse(nomadDummy.URL)
+			require.NoError(t, err)
+
+			validator := tc.validator()
+			jobHandler := admissionctrl.NewJobHandler(
+				[]admissionctrl.JobMutator{},
+				[]admissionctrl.JobValidator{validator},
+				slog.New(slog.DiscardHandler),
+				false,
+				admissionctrl.WithValidatorSettings(validator.Name(), admissionctrl.ValidatorSettings{Operations: tc.operations}),
+			)
+			proxy := NewProxyAsHandlerFunc(nomad, jobHandler, slog.New(slog.DiscardHandler), nil)
+			proxyServer := httptest.NewServer(proxy)
+			defer proxyServer.Close()
+
+			evalID, _, err := buildNomadClient(t, proxyServer).Jobs().DeregisterOpts(*job.ID, &api.DeregisterOptions{Purge: true, Global: true}, nil)
+
+			assert.Equal(t, tc.wantJobFetched, jobFetched, "job lookup")
+			assert.Equal(t, tc.wantDeregistered, deregistered, "deregistration forwarded to Nomad")
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "eval-1", evalID)
+			validator.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeregisterWarnings(t *testing.T) {
+	job := testutil.ReadJob(t, "job.json")
+	nomadDummy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodGet {
+			json.NewEncoder(rw).Encode(job)
+			return
+		}
+		json.NewEncoder(rw).Encode(&api.JobDeregisterResponse{EvalID: "eval-1"})
+	}))
+	defer nomadDummy.Close()
+
+	nomad, err := url.Parse(nomadDummy.URL)
+	require.NoError(t, err)
+
+	validator := testutil.MockValidatorReturningWarnings("job is still referenced")
+	jobHandler := admissionctrl.NewJobHandler(nil, []admissionctrl.JobValidator{validator}, slog.New(slog.DiscardHandler), false,
+		admissionctrl.WithValidatorSettings(validator.Name(), admissionctrl.ValidatorSettings{Operations: []string{config.OperationDeregister}}),
+	)
+	proxyServer := httptest.NewServer(NewProxyAsHandlerFunc(nomad, jobHandler, slog.New(slog.DiscardHandler), nil))
+	defer proxyServer.Close()
+
+	req, err := http.NewRequest(http.MethodDelete, proxyServer.URL+"/v1/job/"+*job.ID, nil)
+	require.NoError(t, err)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, []string{"job is still referenced"}, res.Header.Values(warningsHeader))
+	response := &api.JobDeregisterResponse{}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(response))
+	assert.Equal(t, "eval-1", response.EvalID)
+}
+
+func TestParseMutation(t *testing.T) {
+	tests := []struct {
+		name         string
+		enabled      bool
+		encoding     string
+		mutators     []admissionctrl.JobMutator
+		wantStatus   int
+		wantJob      *api.Job
+		wantWarnings []string
+	}{
+		{
+			name:    "mutator warnings are returned as headers",
+			enabled: true,
+			mutators: []admissionctrl.JobMutator{
+				testutil.MockMutatorReturningWarnings("injected\nsidecar"),
+			},
+			wantStatus:   http.StatusOK,
+			wantJob:      testutil.BaseJob(),
+			wantWarnings: []string{"injected sidecar"},
+		},
+		{
+			name:     "parsed gzip job is mutated",
+			enabled:  true,
+			encoding: "gzip",
+			mutators: []admissionctrl.JobMutator{
+				&testutil.HelloMutator{MutatorName: "hello"},
+			},
+			wantStatus: http.StatusOK,
+			wantJob: &api.Job{
+				ID:   testutil.BaseJob().ID,
+				Meta: map[string]string{"hello": "world"},
+			},
+		},
+		{
+			name: "parsed job is untouched unless enabled",
+			mutators: []admissionctrl.JobMutator{
+				&testutil.HelloMutator{MutatorName: "hello"},
+			},
+			wantStatus: http.StatusOK,
+			wantJob:    testutil.BaseJob(),
+		},
+		{
+			name:    "mutator error fails the parse request",
+			enabled: true,
+			mutators: []admissionctrl.JobMutator{
+				testutil.MockMutatorReturningError("no preview"),
+			},
+			wantStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nomadDummy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, "/v1/jobs/parse", req.URL.Path)
+				writeNomadResponse(rw, proxyTestCase{
+					nomadResponse:         toJson(t, testutil.BaseJob()),
+					nomadResponseEncoding: tc.encoding,
+				})
+			}))
+			defer nomadDummy.Close()
+
+			nomad, err := url.Parse(nomadDummy.URL)
+			require.NoError(t, err)
+
+			jobHandler := admissionctrl.NewJobHandler(tc.mutators, nil, slog.New(slog.DiscardHandler), false)
+			proxy := NewProxyAsHandlerFunc(nomad, jobHandler, slog.New(slog.DiscardHandler), nil, WithParseMutation(tc.enabled))
+			proxyServer := httptest.NewServer(proxy)
+			defer proxyServer.Close()
+
+			req, err := http.NewRequest(http.MethodPut, proxyServer.URL+"/v1/jobs/parse", strings.NewReader(toJson(t, &api.JobsParseRequest{JobHCL: `job "test-job" {}`})))
+			require.NoError(t, err)
+			if tc.encoding != "" {
+				req.Header.Set("Accept-Encoding", tc.encoding)
+			}
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.wantStatus, res.StatusCode)
+			if tc.wantStatus != http.StatusOK {
+				assert.Equal(t, fmt.Sprintf("%d %s", tc.wantStatus, http.StatusText(tc.wantStatus)), res.Status)
+				assert.Equal(t, "text/plain; charset=utf-8", res.Header.Get("Content-Type"))
+				assert.Equal(t, "nosniff", res.Header.Get("X-Content-Type-Options"))
+				body, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				assert.Contains(t, string(body), "no preview")
+				return
+			}
+			assert.Equal(t, tc.wantWarnings, res.Header.Values(warningsHeader))
+
+			reader := io.Reader(res.Body)
+			if tc.encoding == "gzip" {
+				reader, err = gzip.NewReader(res.Body)
+				require.NoError(t, err)
+			}
+			job := &api.Job{}
+			require.NoError(t, json.NewDecoder(reader).Decode(job))
+			assert.Equal(t, tc.wantJob, job)
+		})
+	}
+}
+
+func TestRegisterSubmission(t *testing.T) {
+	hclSubmission := &api.JobSubmission{
+		Source: `job "test-job" {}`,
+		Format: "hcl2",
+	}
+	tests := []struct {
+		name           string
+		submission     *config.Submission
+		mutators       []admissionctrl.JobMutator
+		validators     []admissionctrl.JobValidator
+		wantSubmission *api.JobSubmission
+		wantMeta       map[string]string
+	}{
+		{
+			name:           "submission is kept without a strategy",
+			mutators:       []admissionctrl.JobMutator{&testutil.HelloMutator{MutatorName: "hello"}},
+			wantSubmission: hclSubmission,
+			wantMeta:       map[string]string{"hello": "world"},
+		},
+		{
+			name:       "submission is dropped",
+			submission: &config.Submission{Strategy: config.SubmissionDrop},
+			mutators:   []admissionctrl.JobMutator{&testutil.HelloMutator{MutatorName: "hello"}},
+			wantMeta:   map[string]string{"hello": "world"},
+		},
+		{
+			name:           "submission is kept when nothing was mutated",
+			submission:     &config.Submission{Strategy: config.SubmissionDrop},
+			wantSubmission: hclSubmission,
+		},
+		{
+			name:           "mutations are recorded in job meta",
+			submission:     &config.Submission{Strategy: config.SubmissionAnnotate, MetaKey: "nacp.mutations"},
+			mutators:       []admissionctrl.JobMutator{&testutil.HelloMutator{MutatorName: "hello"}},
+			validators:     []admissionctrl.JobValidator{validator.NewRequiredMetaValidator("annotated", []string{"nacp.mutations"}, "", nil, "")},
+			wantSubmission: hclSubmission,
+			wantMeta: map[string]string{
+				"hello":          "world",
+				"nacp.mutations": "hello: replace /Meta",
+			},
+		},
+		{
+			name:       "submission is regenerated from the mutated job",
+			submission: &config.Submission{Strategy: config.SubmissionRegenerate},
+			mutators:   []admissionctrl.JobMutator{&testutil.HelloMutator{MutatorName: "hello"}},
+			wantSubmission: &api.JobSubmission{
+				Source: func() string {
+					job := testutil.BaseJob()
+					job.Meta = map[string]string{"hello": "world"}
+					data, err := json.MarshalIndent(job, "", "  ")
+					require.NoError(t, err)
+					return string(data)
+				}(),
+				Format: "json",
+			},
+			wantMeta: map[string]string{"hello": "world"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var received *api.JobRegisterRequest
+			nomadDummy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				received = &api.JobRegisterRequest{}
+				require.NoError(t, json.NewDecoder(req.Body).Decode(received))
+				writeNomadResponse(rw, proxyTestCase{nomadResponse: toJson(t, &api.JobRegisterResponse{EvalID: "eval"})})
+			}))
+			defer nomadDummy.Close()
+
+			nomad, err := url.Parse(nomadDummy.URL)
+			require.NoError(t, err)
+
+			jobHandler := admissionctrl.NewJobHandler(tc.mutators, tc.validators, slog.New(slog.DiscardHandler), false)
+			proxy := NewProxyAsHandlerFunc(nomad, jobHandler, slog.New(slog.DiscardHandler), nil, WithSubmission(tc.submission))
+			proxyServer := httptest.NewServer(proxy)
+			defer proxyServer.Close()
+
+			body := toJson(t, &api.JobRegisterRequest{Job: testutil.BaseJob(), Submission: hclSubmission})
+			res, err := sendPut(t, proxyServer.URL+"/v1/jobs", strings.NewReader(body))
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			require.NotNil(t, received)
+			assert.Equal(t, tc.wantSubmission, received.Submission)
+			assert.Equal(t, tc.wantMeta, received.Job.Meta)
+		})
+	}
+}
+
+func TestProvenanceHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		body     any
+		response any
+	}{
+		{
+			name:     "register",
+			path:     "/v1/jobs",
+			body:     &api.JobRegisterRequest{Job: testutil.BaseJob()},
+			response: &api.JobRegisterResponse{EvalID: "eval"},
+		},
+		{
+			name:     "plan",
+			path:     "/v1/job/test-job/plan",
+			body:     &api.JobPlanRequest{Job: testutil.BaseJob()},
+			response: &api.JobPlanResponse{},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var receivedMeta map[string]string
+			nomadDummy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				var received struct{ Job *api.Job }
+				require.NoError(t, json.NewDecoder(req.Body).Decode(&received))
+				receivedMeta = received.Job.Meta
+				writeNomadResponse(rw, proxyTestCase{nomadResponse: toJson(t, tc.response)})
+			}))
+			defer nomadDummy.Close()
+
+			nomad, err := url.Parse(nomadDummy.URL)
+			require.NoError(t, err)
+
+			jobHandler := admissionctrl.NewJobHandler(
+				[]admissionctrl.JobMutator{&testutil.HelloMutator{MutatorName: "hello"}},
+				nil,
+				slog.New(slog.DiscardHandler),
+				false,
+				admissionctrl.WithProvenance(config.DefaultProvenanceMetaKey),
+			)
+			proxyServer := httptest.NewServer(NewProxyAsHandlerFunc(nomad, jobHandler, slog.New(slog.DiscardHandler), nil))
+			defer proxyServer.Close()
+
+			res, err := sendPut(t, proxyServer.URL+tc.path, strings.NewReader(toJson(t, tc.body)))
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			provenance := res.Header.Get("X-NACP-Mutations")
+			assert.Regexp(t, `^hello:[0-9a-f]{12}$`, provenance)
+			assert.Equal(t, provenance, receivedMeta[config.DefaultProvenanceMetaKey])
+		})
+	}
+}
+
+func TestPolicyOverride(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        string
+		bodyOverride  bool
+		wantStatus    int
+		wantForwarded bool
+	}{
+		{name: "soft-mandatory failure rejects without override", wantStatus: http.StatusBadRequest},
+		{name: "override via header", header: "true", wantStatus: http.StatusOK, wantForwarded: true},
+		{name: "override via policy_override flag", bodyOverride: true, wantStatus: http.StatusOK, wantForwarded: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			forwarded := false
+			nomadDummy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if req.URL.Path == "/v1/acl/token/self" {
+					writeTokenSelfResponse(rw, proxyTestCase{token: "test-token", accessorID: "accessor"})
+					return
+				}
+				forwarded = true
+				writeNomadResponse(rw, proxyTestCase{nomadResponse: toJson(t, &api.JobRegisterResponse{EvalID: "eval"})})
+			}))
+			defer nomadDummy.Close()
 
+			nomad, err := url.Parse(nomadDummy.URL)
+			require.NoError(t, err)
+
+			jobHandler := admissionctrl.NewJobHandler(nil,
+				[]admissionctrl.JobValidator{testutil.MockValidatorRejecting("costcenter meta missing")},
+				slog.New(slog.DiscardHandler), true,
+				admissionctrl.WithValidatorSettings("mock-validator", admissionctrl.ValidatorSettings{
+					EnforcementLevel: config.EnforcementSoftMandatory,
+					OverridePolicies: []string{"test-policy"},
+				}),
+			)
+			proxyServer := httptest.NewServer(NewProxyAsHandlerFunc(nomad, jobHandler, slog.New(slog.DiscardHandler), nil))
+			defer proxyServer.Close()
+
+			body := toJson(t, &api.JobRegisterRequest{Job: testutil.BaseJob(), PolicyOverride: tc.bodyOverride})
+			req, err := http.NewRequest(http.MethodPut, proxyServer.URL+"/v1/jobs", strings.NewReader(body))
+			require.NoError(t, err)
+			req.Header.Set("X-Nomad-Token", "test-token")
+			if tc.header != "" {
+				req.Header.Set(policyOverrideHeader, tc.header)
+			}
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.wantStatus, res.StatusCode)
+			assert.Equal(t, tc.wantForwarded, forwarded)
+		})
+	}
+}
+
+// clampCountMutator caps the count of every task group at max.
+type clampCountMutator struct {
+	max int
+}
+
+func (m *clampCountMutator) Name() string { return "clamp-count" }
+
+func (m *clampCountMutator) Mutate(_ context.Context, payload *types.Payload) (*api.Job, bool, []error, error) {
+	mutated := false
+	for _, group := range payload.Job.TaskGroups {
+		if group.Count != nil && *group.Count > m.max {
+			group.Count = &m.max
+			mutated = true
+		}
+	}
+	return payload.Job, mutated, nil, nil
+}
+
+func TestScaleAdmission(t *testing.T) {
+	job := testutil.ReadJob(t, "job.json")
+	groupName := *job.TaskGroups[0].Name
+
+	tests := []struct {
+		name           string
+		count          *int
+		validator      func() *testutil.MockValidator
+		operations     []string
+		wantStatus     int
+		wantJobFetched bool
+		wantCount      *int64
+	}{
+		{
+			name:  "mutator clamps the requested count",
+			count: pointer(10),
+			validator: func() *testutil.MockValidator {
+				validator := new(testutil.MockValidator)
+				validator.On("Validate", mock.Anything, mock.MatchedBy(func(payload *types.Payload) bool {
+					return payload.Operation == config.OperationScale &&
+						payload.Scale.Group == groupName &&
+						payload.Scale.Count == 10 &&
+						*payload.Job.TaskGroups[0].Count == 3
+				})).Return([]error{}, nil)
+				return validator
+			},
+			operations:     []string{config.OperationScale},
+			wantStatus:     http.StatusOK,
+			wantJobFetched: true,
+			wantCount:      pointer(int64(3)),
+		},
+		{
+			name:  "validator error rejects the scaling request",
+			count: pointer(2),
+			validator: func() *testutil.MockValidator {
+				return testutil.MockValidatorRejecting("count exceeds quota")
+			},
+			operations:     []string{config.OperationScale},
+			wantStatus:     http.StatusForbidden,
+			wantJobFetched: true,
+		},
+		{
+			name: "scaling events without a count are forwarded",
+			validator: func() *testutil.MockValidator {
+				return new(testutil.MockValidator)
+			},
+			operations: []string{config.OperationScale},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:  "job controllers do not run for scaling requests",
+			count: pointer(10),
+			validator: func() *testutil.MockValidator {
+				return new(testutil.MockValidator)
+			},
+			wantStatus: http.StatusOK,
+			wantCount:  pointer(int64(10)),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var jobFetched bool
+			nomadDummy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if req.Method == http.MethodGet {
+					jobFetched = true
+					json.NewEncoder(rw).Encode(job)
+					return
+				}
+				scalingRequest := &api.ScalingRequest{}
+				require.NoError(t, json.NewDecoder(req.Body).Decode(scalingRequest))
+				assert.Equal(t, tc.wantCount, scalingRequest.Count, "forwarded count")
+				json.NewEncoder(rw).Encode(&api.JobRegisterResponse{EvalID: "eval-1"})
+			}))
+			defer nomadDummy.Close()
+
+			nomad, err := url.Parse(nomadDummy.URL)
+			require.NoError(t, err)
+
+			mutator := &clampCountMutator{max: 3}
+			validator := tc.validator()
+			jobHandler := admissionctrl.NewJobHandler(
+				[]admissionctrl.JobMutator{mutator},
+				[]admissionctrl.JobValidator{validator},
+				slog.New(slog.DiscardHandler),
+				false,
+				admissionctrl.WithMutatorSettings(mutator.Name(), admissionctrl.MutatorSettings{Operations: tc.operations}),
+				admissionctrl.WithValidatorSettings(validator.Name(), admissionctrl.ValidatorSettings{Operations: tc.operations}),
+			)
+			proxy := NewProxyAsHandlerFunc(nomad, jobHandler, slog.New(slog.DiscardHandler), nil)
+			proxyServer := httptest.NewServer(proxy)
+			defer proxyServer.Close()
+
+			_, _, err = buildNomadClient(t, proxyServer).Jobs().Scale(*job.ID, groupName, tc.count, "scaling", false, nil, nil)
+
+			assert.Equal(t, tc.wantJobFetched, jobFetched, "job lookup")
+			if tc.wantStatus != http.StatusOK {
+				assert.ErrorContains(t, err, fmt.Sprintf("Unexpected response code: %d", tc.wantStatus))
+				return
+			}
+			require.NoError(t, err)
+			validator.AssertExpectations(t)
+		})
+	}
+}
+
+func pointer[T any](v T) *T {
+	return &v
 }
 
 func sendPut(t *testing.T, url string, body io.Reader) (*http.Response, error) {
@@ -727,7 +1445,7 @@ func TestDefaultBuildServer(t *testing.T) {
 	discardFactory, _ := logutil.NewLoggerFactory(nil, nil, false)
 	c, err := buildConfig("")
 	require.NoError(t, err)
-	server, err := buildServer(c, discardFactory, nil)
+	server, _, err := buildServer(c, discardFactory, nil)
 	assert.NoError(t, err)
 
 	assert.NotNil(t, server)
@@ -738,7 +1456,7 @@ func TestBuildServerFailsOnInvalidNomadUrl(t *testing.T) {
 
 	c := config.DefaultConfig()
 	c.Nomad.Address = ":localhost:4646"
-	_, err := buildServer(c, discardFactory, nil)
+	_, _, err := buildServer(c, discardFactory, nil)
 	assert.Error(t, err)
 
 }
@@ -749,7 +1467,7 @@ func TestBuildServerFailsInvalidValidatorTypes(t *testing.T) {
 	c.Validators = append(c.Validators, config.Validator{
 		Type: "doesnotexit",
 	})
-	_, err := buildServer(c, discardFactory, nil)
+	_, _, err := buildServer(c, discardFactory, nil)
 	assert.Error(t, err, "failed to create validators: unknown validator type doesnotexit")
 }
 func TestBuildServerFailsInvalidMutatorTypes(t *testing.T) {
@@ -759,7 +1477,7 @@ func TestBuildServerFailsInvalidMutatorTypes(t *testing.T) {
 	c.Mutators = append(c.Mutators, config.Mutator{
 		Type: "doesnotexit",
 	})
-	_, err := buildServer(c, discardFactory, nil)
+	_, _, err := buildServer(c, discardFactory, nil)
 	assert.Error(t, err, "failed to create mutators: unknown mutator type doesnotexit")
 }
 func TestCreateValidators(t *testing.T) {
@@ -798,6 +1516,19 @@ func TestCreateValidators(t *testing.T) {
 			},
 			want: &validator.WebhookValidator{},
 		},
+		{
+			name: "webhook validator with a missing signing secret file",
+			validators: config.Validator{
+				Type: "webhook",
+				Name: "test",
+				Webhook: &config.Webhook{
+					Endpoint:          "http://example.com",
+					Method:            "PUT",
+					SigningSecretFile: "missing-secret",
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid validator type",
 			validators: config.Validator{
@@ -823,6 +1554,129 @@ func TestCreateValidators(t *testing.T) {
 			want:     &validator.OpaBundleValidator{},
 			needsOPA: true,
 		},
+		{
+			name: "cel validator",
+			validators: config.Validator{
+				Type: "cel",
+				Name: "test",
+				Cel:  &config.CelValidator{Validations: []config.CelValidation{{Expression: `has(job.Meta.owner)`}}},
+			},
+			want: &validator.CelValidator{},
+		},
+		{
+			name: "json schema validator",
+			validators: config.Validator{
+				Type:       "json_schema",
+				Name:       "test",
+				JSONSchema: &config.JSONSchema{Files: []string{testutil.Filepath(t, "jsonschema/conventions.json")}},
+			},
+			want: &validator.JSONSchemaValidator{},
+		},
+		{
+			name: "json schema validator with an invalid schema",
+			validators: config.Validator{
+				Type:       "json_schema",
+				Name:       "test",
+				JSONSchema: &config.JSONSchema{Files: []string{testutil.Filepath(t, "jsonschema/invalid.json")}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "wasm validator",
+			validators: config.Validator{
+				Type: "wasm",
+				Name: "test",
+				Wasm: &config.Wasm{Module: testutil.BuildWasm(t, "wasm/policy"), CallTimeout: "500ms"},
+			},
+			want: &validator.WasmValidator{},
+		},
+		{
+			name: "wasm validator without a wasm block",
+			validators: config.Validator{
+				Type: "wasm",
+				Name: "test",
+			},
+			wantErr: true,
+		},
+		{
+			name: "exec validator",
+			validators: config.Validator{
+				Type: "exec",
+				Name: "test",
+				Exec: &config.Exec{Command: "sh", Args: []string{"-c", "cat"}, RejectExitCodes: []int{1}, CallTimeout: "2s"},
+			},
+			want: &validator.ExecValidator{},
+		},
+		{
+			name: "exec validator with a missing command",
+			validators: config.Validator{
+				Type: "exec",
+				Name: "test",
+				Exec: &config.Exec{Command: "nacp-does-not-exist"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "grpc validator",
+			validators: config.Validator{
+				Type: "grpc_validator",
+				Name: "test",
+				Grpc: &config.Grpc{Address: "localhost:9090", CallTimeout: "2s", TLS: &config.GrpcTLS{ServerName: "policy"}},
+			},
+			want: &validator.GrpcValidator{},
+		},
+		{
+			name: "grpc validator with a missing CA file",
+			validators: config.Validator{
+				Type: "grpc_validator",
+				Name: "test",
+				Grpc: &config.Grpc{Address: "localhost:9090", TLS: &config.GrpcTLS{CaFile: "missing.pem"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "resource limits validator",
+			validators: config.Validator{
+				Type:           "resource_limits",
+				Name:           "test",
+				ResourceLimits: &config.ResourceLimits{Limits: &config.TaskResources{MemoryMB: config.Ptr(1024)}},
+			},
+			want: &validator.NativeValidator{},
+		},
+		{
+			name: "resource limits validator without limits",
+			validators: config.Validator{
+				Type: "resource_limits",
+				Name: "test",
+			},
+			wantErr: true,
+		},
+		{
+			name: "forbid privileged validator",
+			validators: config.Validator{
+				Type:             "forbid_privileged",
+				Name:             "test",
+				ForbidPrivileged: &config.ForbidPrivileged{Scope: &config.Scope{TaskGroups: []string{"web"}}},
+			},
+			want: &validator.NativeValidator{},
+		},
+		{
+			name: "forbid privileged validator without a block",
+			validators: config.Validator{
+				Type: "forbid_privileged",
+				Name: "test",
+			},
+			want: &validator.NativeValidator{},
+		},
+		{
+			name: "max parallel validator",
+			validators: config.Validator{
+				Type:        "max_parallel",
+				Name:        "test",
+				MaxParallel: &config.MaxParallel{Max: 2},
+			},
+			want: &validator.NativeValidator{},
+		},
 		{
 			name: "opa bundle validator without SDK",
 			validators: config.Validator{
@@ -982,6 +1836,130 @@ func TestCreateMutatators(t *testing.T) {
 			},
 			want: &mutator.JsonPatchWebhookMutator{},
 		},
+		{
+			name: "webhook mutator",
+			mutators: config.Mutator{
+				Type: "webhook",
+				Name: "test",
+				Webhook: &config.Webhook{
+					Endpoint: "http://example.com",
+					Method:   "PUT",
+				},
+			},
+			want: &mutator.WebhookMutator{},
+		},
+		{
+			name: "webhook mutator without webhook",
+			mutators: config.Mutator{
+				Type: "webhook",
+				Name: "test",
+			},
+			wantErr: true,
+		},
+		{
+			name: "defaults mutator",
+			mutators: config.Mutator{
+				Type: "defaults",
+				Name: "test",
+				Defaults: &config.JobDefaults{
+					Namespace: "team-a",
+					Resources: &config.TaskResources{CPU: config.Ptr(100)},
+					Scope:     &config.Scope{TaskGroups: []string{"web"}},
+				},
+			},
+			want: &mutator.DefaultsMutator{},
+		},
+		{
+			name: "defaults mutator without defaults",
+			mutators: config.Mutator{
+				Type: "defaults",
+				Name: "test",
+			},
+			wantErr: true,
+		},
+		{
+			name: "cel mutator",
+			mutators: config.Mutator{
+				Type: "cel",
+				Name: "test",
+				Cel:  &config.CelMutator{Mutations: []config.CelMutation{{Path: "/Meta/owner", Expression: `context.accessorID`}}},
+			},
+			want: &mutator.CelMutator{},
+		},
+		{
+			name: "cel mutator with an invalid expression",
+			mutators: config.Mutator{
+				Type: "cel",
+				Name: "test",
+				Cel:  &config.CelMutator{Mutations: []config.CelMutation{{Path: "/Meta/owner", Expression: `context.`}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "wasm mutator",
+			mutators: config.Mutator{
+				Type: "wasm",
+				Name: "test",
+				Wasm: &config.Wasm{Module: testutil.BuildWasm(t, "wasm/policy"), PoolSize: 2},
+			},
+			want: &mutator.WasmMutator{},
+		},
+		{
+			name: "wasm mutator with a missing module",
+			mutators: config.Mutator{
+				Type: "wasm",
+				Name: "test",
+				Wasm: &config.Wasm{Module: testutil.Filepath(t, "wasm/missing.wasm")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "exec mutator",
+			mutators: config.Mutator{
+				Type: "exec",
+				Name: "test",
+				Exec: &config.Exec{Command: "sh", Args: []string{"-c", "cat"}, Persistent: true},
+			},
+			want: &mutator.ExecMutator{},
+		},
+		{
+			name: "grpc mutator",
+			mutators: config.Mutator{
+				Type: "grpc_mutator",
+				Name: "test",
+				Grpc: &config.Grpc{Address: "localhost:9090"},
+			},
+			want: &mutator.GrpcMutator{},
+		},
+		{
+			name: "inject meta mutator",
+			mutators: config.Mutator{
+				Type:       "inject_meta",
+				Name:       "test",
+				InjectMeta: &config.InjectMeta{Meta: map[string]string{"owner": "team-a"}},
+			},
+			want: &mutator.InjectMetaMutator{},
+		},
+		{
+			name: "add constraint mutator",
+			mutators: config.Mutator{
+				Type: "add_constraint",
+				Name: "test",
+				AddConstraint: &config.AddConstraint{
+					Constraint: &config.Constraint{Attribute: "${attr.kernel.name}", Value: "linux"},
+					Target:     config.TargetGroup,
+				},
+			},
+			want: &mutator.ConstraintMutator{},
+		},
+		{
+			name: "add constraint mutator without constraint",
+			mutators: config.Mutator{
+				Type: "add_constraint",
+				Name: "test",
+			},
+			wantErr: true,
+		},
 		{
 			name: "opa bundle JSON patch mutator",
 			mutators: config.Mutator{
@@ -1292,6 +2270,13 @@ func TestAdmissionRouteMatching(t *testing.T) {
 	assert.True(t, isPlan(httptest.NewRequest(http.MethodPost, "/v1/job/123_example.v2/plan", nil)))
 	assert.False(t, isUpdate(httptest.NewRequest(http.MethodPut, "/v1/job/example/allocations", nil)))
 	assert.False(t, isPlan(httptest.NewRequest(http.MethodGet, "/v1/job/example/plan", nil)))
+	assert.True(t, isDispatch(httptest.NewRequest(http.MethodPut, "/v1/job/batch.v2/dispatch", nil)))
+	assert.False(t, isDispatch(httptest.NewRequest(http.MethodGet, "/v1/job/batch/dispatch", nil)))
+	assert.False(t, isUpdate(httptest.NewRequest(http.MethodPut, "/v1/job/batch/dispatch", nil)))
+	assert.True(t, isDeregister(httptest.NewRequest(http.MethodDelete, "/v1/job/example?purge=true", nil)))
+	assert.False(t, isDeregister(httptest.NewRequest(http.MethodDelete, "/v1/job/example/allocations", nil)))
+	assert.True(t, isScale(httptest.NewRequest(http.MethodPost, "/v1/job/example/scale", nil)))
+	assert.False(t, isScale(httptest.NewRequest(http.MethodGet, "/v1/job/example/scale", nil)))
 }
 
 func TestBuildOpaSdk(t *testing.T) {
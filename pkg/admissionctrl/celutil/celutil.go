@@ -0,0 +1,113 @@
+// Package celutil compiles and evaluates the CEL expressions of the cel
+// validator and mutator. Expressions see the admission payload as the
+// variables job, context, operation, dispatch, deregister and scale, in the
+// same JSON shape OPA policies and webhooks receive.
+package celutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/ext"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var variables = []string{"job", "context", "dispatch", "deregister", "scale"}
+
+var env = func() *cel.Env {
+	options := []cel.EnvOption{
+		cel.Variable("operation", cel.StringType),
+		ext.Strings(),
+	}
+	for _, variable := range variables {
+		options = append(options, cel.Variable(variable, cel.DynType))
+	}
+	e, err := cel.NewEnv(options...)
+	if err != nil {
+		panic(fmt.Sprintf("invalid CEL environment: %v", err))
+	}
+	return e
+}()
+
+// Compile parses and type checks an expression.
+func Compile(expression string) (cel.Program, error) {
+	program, _, err := compile(expression)
+	return program, err
+}
+
+// CompileCondition compiles an expression that has to evaluate to a bool.
+func CompileCondition(expression string) (cel.Program, error) {
+	program, outputType, err := compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	if !outputType.IsAssignableType(cel.BoolType) {
+		return nil, fmt.Errorf("expression %q evaluates to %s, not bool", expression, outputType)
+	}
+	return program, nil
+}
+
+func compile(expression string) (cel.Program, *cel.Type, error) {
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, nil, fmt.Errorf("invalid expression %q: %w", expression, issues.Err())
+	}
+	program, err := env.Program(ast, cel.EvalOptions(cel.OptOptimize))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid expression %q: %w", expression, err)
+	}
+	return program, ast.OutputType(), nil
+}
+
+// Input converts the payload into the variables of an evaluation. Parts the
+// payload leaves out are null.
+func Input(payload interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	input := map[string]interface{}{"operation": ""}
+	for _, variable := range variables {
+		input[variable] = nil
+	}
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// EvalCondition evaluates a program compiled with CompileCondition.
+func EvalCondition(ctx context.Context, program cel.Program, input map[string]interface{}) (bool, error) {
+	result, _, err := program.ContextEval(ctx, input)
+	if err != nil {
+		return false, err
+	}
+	value, ok := result.(types.Bool)
+	if !ok {
+		return false, fmt.Errorf("expression evaluated to %s, not bool", result.Type())
+	}
+	return bool(value), nil
+}
+
+// Eval evaluates a program and returns its result as a JSON value, i.e. nil,
+// a bool, a float64, a string, a []interface{} or a map[string]interface{}.
+func Eval(ctx context.Context, program cel.Program, input map[string]interface{}) (interface{}, error) {
+	result, _, err := program.ContextEval(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return toJSON(result)
+}
+
+func toJSON(value ref.Val) (interface{}, error) {
+	native, err := value.ConvertToNative(reflect.TypeOf(&structpb.Value{}))
+	if err != nil {
+		return nil, fmt.Errorf("expression result is not a JSON value: %w", err)
+	}
+	return native.(*structpb.Value).AsInterface(), nil
+}
@@ -5,12 +5,21 @@ package admissionctrl
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/mxab/nacp/pkg/admissionctrl/mutator/jsonpatcher"
 	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/config"
 	"github.com/mxab/nacp/pkg/o11y"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -24,12 +33,26 @@ import (
 // attrNomadJobID is the span attribute key carrying the ID of the job under admission.
 const attrNomadJobID = "nomad.job.id"
 
+// errNilJob is the failure of a mutator returning no job.
+var errNilJob = errors.New("job mutator returned nil job")
+
+// Controller kinds used in audit records.
+const (
+	kindMutator   = "mutator"
+	kindValidator = "validator"
+)
+
 type Metrics struct {
-	validatorWarningCount o11y.NacpValidatorWarningCount
-	validatorErrorCount   o11y.NacpValidatorErrorCount
-	mutatorWarningCount   o11y.NacpMutatorWarningCount
-	mutatorErrorCount     o11y.NacpMutatorErrorCount
-	mutatorMutationCount  o11y.NacpMutatorMutationCount
+	validatorWarningCount  o11y.NacpValidatorWarningCount
+	validatorErrorCount    o11y.NacpValidatorErrorCount
+	mutatorWarningCount    o11y.NacpMutatorWarningCount
+	mutatorErrorCount      o11y.NacpMutatorErrorCount
+	mutatorMutationCount   o11y.NacpMutatorMutationCount
+	validatorOverrideCount o11y.NacpValidatorOverrideCount
+	auditViolationCount    o11y.NacpAdmissionAuditViolationCount
+	controllerSkipCount    o11y.NacpControllerSkipCount
+	controllerFailureCount o11y.NacpControllerFailureCount
+	policyViolationCount   o11y.NacpPolicyViolationCount
 }
 
 func newMetrics() *Metrics {
@@ -55,12 +78,37 @@ func newMetrics() *Metrics {
 	if err != nil {
 		panic(err)
 	}
+	validatorOverrideCount, err := o11y.NewNacpValidatorOverrideCount(meter)
+	if err != nil {
+		panic(err)
+	}
+	auditViolationCount, err := o11y.NewNacpAdmissionAuditViolationCount(meter)
+	if err != nil {
+		panic(err)
+	}
+	controllerSkipCount, err := o11y.NewNacpControllerSkipCount(meter)
+	if err != nil {
+		panic(err)
+	}
+	controllerFailureCount, err := o11y.NewNacpControllerFailureCount(meter)
+	if err != nil {
+		panic(err)
+	}
+	policyViolationCount, err := o11y.NewNacpPolicyViolationCount(meter)
+	if err != nil {
+		panic(err)
+	}
 	return &Metrics{
-		validatorWarningCount: validatorWarningCount,
-		validatorErrorCount:   validatorErrorCount,
-		mutatorWarningCount:   mutatorWarningCount,
-		mutatorErrorCount:     mutatorErrorCount,
-		mutatorMutationCount:  mutatorMutationCount,
+		policyViolationCount:   policyViolationCount,
+		controllerSkipCount:    controllerSkipCount,
+		controllerFailureCount: controllerFailureCount,
+		validatorOverrideCount: validatorOverrideCount,
+		auditViolationCount:    auditViolationCount,
+		validatorWarningCount:  validatorWarningCount,
+		validatorErrorCount:    validatorErrorCount,
+		mutatorWarningCount:    mutatorWarningCount,
+		mutatorErrorCount:      mutatorErrorCount,
+		mutatorMutationCount:   mutatorMutationCount,
 	}
 }
 
@@ -68,41 +116,234 @@ type AdmissionController interface {
 	Name() string
 }
 
+// JobMutator changes jobs under admission. Policy errors are returned as a
+// *multierror.Error; any other error is a failure of the mutator itself and is
+// subject to its failure policy.
 type JobMutator interface {
 	AdmissionController
 	Mutate(context.Context, *types.Payload) (*api.Job, bool, []error, error)
 }
 
+// JobValidator checks jobs under admission. Like JobMutator, rejections are
+// returned as a *multierror.Error and any other error is a failure.
 type JobValidator interface {
 	AdmissionController
 	Validate(context.Context, *types.Payload) (warnings []error, err error)
 }
 
+// MutatorSettings holds the per-mutator configuration the handler needs on
+// top of the mutator itself.
+type MutatorSettings struct {
+	// Operations lists the admission operations the mutator runs for.
+	// Empty means config.JobOperations.
+	Operations []string
+	// Mode is config.ModeEnforce or config.ModeAudit. Empty means the
+	// handler's mode.
+	Mode string
+	// Timeout bounds how long the handler waits for the mutator. Zero
+	// means no limit.
+	Timeout time.Duration
+	// Match restricts the jobs the controller runs for. Nil matches all.
+	Match *Match
+	// FailurePolicy is config.FailurePolicyFail or
+	// config.FailurePolicyIgnore. Empty means fail.
+	FailurePolicy string
+	// ReinvocationPolicy is config.ReinvocationNever or
+	// config.ReinvocationIfNeeded. Empty means never.
+	ReinvocationPolicy string
+}
+
+// ValidatorSettings holds the per-validator configuration the handler needs
+// on top of the validator itself.
+type ValidatorSettings struct {
+	// Operations lists the admission operations the validator runs for.
+	// Empty means config.JobOperations.
+	Operations []string
+	// EnforcementLevel decides how validation errors are treated.
+	// Empty means config.EnforcementHardMandatory.
+	EnforcementLevel string
+	// OverridePolicies and OverrideRoles name the ACL policies and roles
+	// whose tokens may override a soft-mandatory validator.
+	OverridePolicies []string
+	OverrideRoles    []string
+	// Timeout bounds how long the handler waits for the validator. Zero
+	// means no limit.
+	Timeout time.Duration
+	// Mode is config.ModeEnforce or config.ModeAudit. Empty means the
+	// handler's mode.
+	Mode string
+	// Match restricts the jobs the controller runs for. Nil matches all.
+	Match *Match
+	// FailurePolicy is config.FailurePolicyFail or
+	// config.FailurePolicyIgnore. Empty means fail.
+	FailurePolicy string
+}
+
 type JobHandler struct {
-	mutators     []JobMutator
-	validators   []JobValidator
-	resolveToken bool
-	logger       *slog.Logger
-	metrics      *Metrics
-	tracer       trace.Tracer
+	mutators          []JobMutator
+	validators        []JobValidator
+	mutatorSettings   map[string]MutatorSettings
+	validatorSettings map[string]ValidatorSettings
+	mode              string
+	maxConcurrency    int
+	maxMutatorPasses  int
+	provenanceKey     string
+	resolveToken      bool
+	logger            *slog.Logger
+	metrics           *Metrics
+	tracer            trace.Tracer
+}
+
+type Option func(*JobHandler)
+
+// WithMutatorSettings applies settings to the mutator with the given name.
+func WithMutatorSettings(name string, settings MutatorSettings) Option {
+	return func(j *JobHandler) {
+		j.mutatorSettings[name] = settings
+	}
+}
+
+// WithValidatorSettings applies settings to the validator with the given name.
+func WithValidatorSettings(name string, settings ValidatorSettings) Option {
+	return func(j *JobHandler) {
+		j.validatorSettings[name] = settings
+	}
+}
+
+// WithMaxConcurrency sets how many validators run at the same time. Values
+// below one run them one after another.
+func WithMaxConcurrency(n int) Option {
+	return func(j *JobHandler) {
+		j.maxConcurrency = max(n, 1)
+	}
+}
+
+// WithMaxMutatorPasses bounds how often the mutator chain runs when mutators
+// are reinvoked. Values below one keep the default.
+func WithMaxMutatorPasses(n int) Option {
+	return func(j *JobHandler) {
+		if n > 0 {
+			j.maxMutatorPasses = n
+		}
+	}
+}
+
+// WithProvenance records the mutators that changed a job, see Provenance, in
+// the job meta key metaKey.
+func WithProvenance(metaKey string) Option {
+	return func(j *JobHandler) {
+		j.provenanceKey = metaKey
+	}
+}
+
+// WithMode sets the mode of all controllers that do not set their own.
+func WithMode(mode string) Option {
+	return func(j *JobHandler) {
+		j.mode = mode
+	}
+}
+
+func NewJobHandler(mutators []JobMutator, validators []JobValidator, logger *slog.Logger, resolverToken bool, opts ...Option) *JobHandler {
+	j := &JobHandler{
+		mutators:          mutators,
+		validators:        validators,
+		mutatorSettings:   map[string]MutatorSettings{},
+		validatorSettings: map[string]ValidatorSettings{},
+		maxConcurrency:    1,
+		maxMutatorPasses:  config.DefaultMaxMutatorPasses,
+		logger:            logger,
+		resolveToken:      resolverToken,
+		metrics:           newMetrics(),
+		tracer:            otel.Tracer("github.com/mxab/nacp"),
+	}
+	for _, opt := range opts {
+		opt(j)
+	}
+	return j
+}
+
+// Mutation records the change a single mutator made to a job.
+type Mutation struct {
+	Mutator string                  `json:"mutator"`
+	Patch   []jsonpatcher.Operation `json:"patch"`
+}
+
+// Digest identifies the mutation's patch by a shortened SHA-256 of its JSON.
+func (m Mutation) Digest() string {
+	data, err := json.Marshal(m.Patch)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// Admission is the outcome of running the admission controllers on a job.
+type Admission struct {
+	Job      *api.Job
+	Warnings []error
+	// Mutations lists the mutators that changed the job, in order.
+	Mutations []Mutation
+	// Provenance summarises Mutations when the handler records provenance.
+	Provenance string
+}
+
+// Provenance summarises the mutations that changed the job as a
+// comma-separated list of mutator:digest pairs.
+func Provenance(mutations []Mutation) string {
+	entries := make([]string, 0, len(mutations))
+	for _, mutation := range mutations {
+		if len(mutation.Patch) == 0 {
+			continue
+		}
+		entries = append(entries, mutation.Mutator+":"+mutation.Digest())
+	}
+	return strings.Join(entries, ",")
 }
 
-func NewJobHandler(mutators []JobMutator, validators []JobValidator, logger *slog.Logger, resolverToken bool) *JobHandler {
-	return &JobHandler{
-		mutators:     mutators,
-		validators:   validators,
-		logger:       logger,
-		resolveToken: resolverToken,
-		metrics:      newMetrics(),
-		tracer:       otel.Tracer("github.com/mxab/nacp"),
+// maxMutationSummaryBytes bounds MutationSummary so it fits comfortably in a
+// job meta value.
+const maxMutationSummaryBytes = 1024
+
+// MutationSummary describes the mutations that changed the job by mutator and
+// JSON Patch operation, without values, for example
+// "defaults: add /Priority, replace /Meta; inject-otel: add /TaskGroups/0/Tasks/0/Env".
+// Summaries longer than maxMutationSummaryBytes are cut short and end in "...".
+func MutationSummary(mutations []Mutation) string {
+	entries := make([]string, 0, len(mutations))
+	for _, mutation := range mutations {
+		if len(mutation.Patch) == 0 {
+			continue
+		}
+		ops := make([]string, 0, len(mutation.Patch))
+		for _, op := range mutation.Patch {
+			ops = append(ops, op.Op+" "+op.Path)
+		}
+		entries = append(entries, mutation.Mutator+": "+strings.Join(ops, ", "))
+	}
+	summary := strings.Join(entries, "; ")
+	if len(summary) > maxMutationSummaryBytes {
+		summary = strings.ToValidUTF8(summary[:maxMutationSummaryBytes-3], "") + "..."
 	}
+	return summary
 }
 
 func (j *JobHandler) ApplyAdmissionControllers(ctx context.Context, payload *types.Payload) (out *api.Job, warnings []error, err error) {
+	admission, err := j.Admit(ctx, payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	return admission.Job, admission.Warnings, nil
+}
+
+// Admit runs the mutators and then the validators on the payload's job.
+// beforeValidation is called with the mutated admission before the validators
+// run, so changes it makes to the job are validated as well.
+func (j *JobHandler) Admit(ctx context.Context, payload *types.Payload, beforeValidation ...func(*Admission) error) (*Admission, error) {
 	// Mutators run first before validators, so validators view the final rendered job.
 	// So, mutators must handle invalid jobs.
 	if payload == nil || payload.Job == nil {
-		return nil, nil, errors.New("admission payload must contain a job")
+		return nil, NewError(KindBadRequest, errors.New("admission payload must contain a job"))
 	}
 
 	ctx, span := j.tracer.Start(ctx, "admission.apply")
@@ -110,127 +351,606 @@ func (j *JobHandler) ApplyAdmissionControllers(ctx context.Context, payload *typ
 
 	span.SetAttributes(attribute.String(attrNomadJobID, jobID(payload.Job)))
 
-	out, warnings, err = j.AdmissionMutators(ctx, payload)
+	admission, err := j.mutate(ctx, payload)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+	for _, fn := range beforeValidation {
+		if err := fn(admission); err != nil {
+			return nil, err
+		}
 	}
 
-	validateWarnings, err := j.AdmissionValidators(ctx, &types.Payload{Job: out, Context: payload.Context})
+	validateWarnings, err := j.AdmissionValidators(ctx, withJob(payload, admission.Job))
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	warnings = append(warnings, validateWarnings...)
+	admission.Warnings = append(admission.Warnings, validateWarnings...)
 
-	return out, warnings, nil
+	return admission, nil
 }
 
 // AdmissionMutators returns an updated job as well as warnings or an error.
 func (j *JobHandler) AdmissionMutators(ctx context.Context, payload *types.Payload) (job *api.Job, warnings []error, err error) {
+	admission, err := j.mutate(ctx, payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	return admission.Job, admission.Warnings, nil
+}
+
+func (j *JobHandler) mutate(ctx context.Context, payload *types.Payload) (*Admission, error) {
 	if payload == nil || payload.Job == nil {
-		return nil, nil, errors.New("admission payload must contain a job")
+		return nil, NewError(KindBadRequest, errors.New("admission payload must contain a job"))
 	}
 
 	ctx, span := j.tracer.Start(ctx, "mutators.process")
 	defer span.End()
-	var w []error
-	job = payload.Job
+	admission := &Admission{Job: payload.Job}
 	j.logger.DebugContext(ctx, "applying job mutators", "mutators", len(j.mutators), "job", payload.Job.ID)
+	operation := operationOf(payload)
+
+	// version counts the changes made to the job; seen holds the version each
+	// mutator last returned, so a mutator needs reinvocation when a later
+	// mutator changed the job after it ran
+	version := 0
+	seen := map[string]int{}
+	warnings := map[string][]error{}
+	var changedBy []string
+	run := func(mutator JobMutator) error {
+		settings := j.mutatorSettings[mutator.Name()]
+		if !runsFor(settings.Operations, operation) {
+			return nil
+		}
+		if !settings.Match.Matches(withJob(payload, admission.Job)) {
+			j.skip(ctx, kindMutator, mutator.Name())
+			return nil
+		}
+		result, err := j.runMutator(ctx, withJob(payload, admission.Job), mutator, settings, operation)
+		if err != nil {
+			return err
+		}
+		warnings[mutator.Name()] = result.warnings
+		if result.job != nil {
+			admission.Job = result.job
+		}
+		if result.mutation != nil {
+			admission.Mutations = append(admission.Mutations, *result.mutation)
+			if len(result.mutation.Patch) > 0 {
+				version++
+				changedBy = append(changedBy, mutator.Name())
+			}
+		}
+		seen[mutator.Name()] = version
+		return nil
+	}
+	needsReinvocation := func(mutator JobMutator) bool {
+		settings := j.mutatorSettings[mutator.Name()]
+		last, ran := seen[mutator.Name()]
+		return ran && last < version &&
+			settings.ReinvocationPolicy == config.ReinvocationIfNeeded &&
+			j.modeOf(settings.Mode) != config.ModeAudit
+	}
+
 	for _, mutator := range j.mutators {
+		if err := run(mutator); err != nil {
+			return nil, err
+		}
+	}
+	passes := 1
+	for slices.ContainsFunc(j.mutators, needsReinvocation) {
+		if passes == j.maxMutatorPasses {
+			return nil, NewError(KindInternal, fmt.Errorf("job still changed after %d mutator passes, last changed by %s", passes, strings.Join(changedBy, ", ")), changedBy...)
+		}
+		passes++
+		changedBy = nil
+		span.AddEvent("mutators.reinvoked", trace.WithAttributes(attribute.Int("pass", passes)))
+		for _, mutator := range j.mutators {
+			if !needsReinvocation(mutator) {
+				continue
+			}
+			j.logger.DebugContext(ctx, "reinvoking job mutator", "mutator", mutator.Name(), "pass", passes)
+			if err := run(mutator); err != nil {
+				return nil, err
+			}
+		}
+	}
+	span.SetAttributes(attribute.Int("passes", passes))
 
-		err = func() (err error) {
+	if j.provenanceKey != "" {
+		admission.Provenance = Provenance(admission.Mutations)
+		recordProvenance(admission.Job, j.provenanceKey, admission.Provenance)
+	}
 
-			jobId := jobID(job)
-			ctx, span := j.tracer.Start(ctx, fmt.Sprintf("mutate: %s", mutator.Name()), trace.WithAttributes(
-				attribute.String(attrNomadJobID, jobId),
-				attribute.String("mutator.name", mutator.Name()),
-			))
+	// a reinvoked mutator's warnings replace those of its earlier runs
+	for _, mutator := range j.mutators {
+		admission.Warnings = append(admission.Warnings, warnings[mutator.Name()]...)
+	}
+	return admission, nil
+}
 
-			defer span.End()
+// mutatorResult is the outcome of a single mutator invocation.
+type mutatorResult struct {
+	// job is the job to continue with, nil to keep the current one.
+	job      *api.Job
+	warnings []error
+	// mutation is the change to apply, nil if the job stays as it is.
+	mutation *Mutation
+}
 
-			j.logger.DebugContext(ctx, "applying job mutator", "mutator", mutator.Name(), "job", jobId)
-			var mutated bool
-			job, mutated, w, err = mutator.Mutate(ctx, &types.Payload{
-				Job:     job,
-				Context: payload.Context})
-			if err != nil {
-				span.SetStatus(codes.Error, "error in mutator")
-				span.RecordError(err)
+// runMutator invokes the mutator once on the payload's job and applies its
+// mode and failure policy to the outcome.
+func (j *JobHandler) runMutator(ctx context.Context, payload *types.Payload, mutator JobMutator, settings MutatorSettings, operation string) (result mutatorResult, err error) {
+	job := payload.Job
+	jobId := jobID(job)
+	ctx, span := j.tracer.Start(ctx, fmt.Sprintf("mutate: %s", mutator.Name()), trace.WithAttributes(
+		attribute.String(attrNomadJobID, jobId),
+		attribute.String("mutator.name", mutator.Name()),
+	))
 
-				j.metrics.mutatorErrorCount.Add(ctx, 1, mutator.Name())
-				return fmt.Errorf("error in job mutator %s: %w", mutator.Name(), err)
-			}
-			if job == nil {
-				span.SetStatus(codes.Error, "job mutator returned nil job")
-				j.metrics.mutatorErrorCount.Add(ctx, 1, mutator.Name())
-				return fmt.Errorf("job mutator %s returned nil job", mutator.Name())
-			}
-			if mutated {
-				span.SetAttributes(attribute.Bool("mutated", true))
-				j.metrics.mutatorMutationCount.Add(ctx, 1, mutator.Name())
-			}
-			j.metrics.mutatorWarningCount.Add(ctx, float64(len(w)), mutator.Name())
+	defer span.End()
 
-			j.logger.DebugContext(ctx, "job mutate results", "mutator", mutator.Name(), "warnings", w, "error", err)
+	// mutators may change the job in place, so keep the original around to diff against
+	before, err := json.Marshal(job)
+	if err != nil {
+		return result, fmt.Errorf("failed to marshal job for mutator %s: %w", mutator.Name(), err)
+	}
 
-			warnings = append(warnings, w...)
-			return nil
+	// audited, abandonable and ignorable mutators work on a copy so a
+	// mutator changing the job in place cannot leak into the forwarded job
+	audit := j.modeOf(settings.Mode) == config.ModeAudit
+	input := job
+	if audit || settings.Timeout > 0 || settings.FailurePolicy == config.FailurePolicyIgnore {
+		if input, err = copyJob(job); err != nil {
+			return result, fmt.Errorf("failed to copy job for mutator %s: %w", mutator.Name(), err)
+		}
+	}
 
-		}()
+	j.logger.DebugContext(ctx, "applying job mutator", "mutator", mutator.Name(), "job", jobId)
+	out, mutated, w, err := callMutator(ctx, mutator, withJob(payload, input), settings.Timeout)
+	if err == nil && out == nil {
+		err = errNilJob
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, "error in mutator")
+		span.RecordError(err)
+
+		j.metrics.mutatorErrorCount.Add(ctx, 1, mutator.Name())
+		failure := !isRejection(err)
+		if failure {
+			j.metrics.controllerFailureCount.Add(ctx, 1, kindMutator, mutator.Name(), failurePolicyOf(settings.FailurePolicy))
+		} else {
+			j.recordViolations(ctx, kindMutator, mutator.Name(), err)
+		}
+		switch {
+		case audit:
+			j.auditViolation(ctx, kindMutator, mutator.Name(), jobId, err, nil)
+			return result, nil
+		case failure && settings.FailurePolicy == config.FailurePolicyIgnore:
+			result.warnings = []error{j.ignoreFailure(ctx, kindMutator, mutator.Name(), jobId, err)}
+			return result, nil
+		case errors.Is(err, errNilJob):
+			return result, NewError(KindUpstream, fmt.Errorf("job mutator %s returned nil job", mutator.Name()), mutator.Name())
+		case failure:
+			return result, NewError(failureKind(err), fmt.Errorf("error in job mutator %s: %w", mutator.Name(), err), mutator.Name())
+		default:
+			return result, NewError(rejectionKind(operation), fmt.Errorf("error in job mutator %s: %w", mutator.Name(), err), mutator.Name())
+		}
+	}
+	if mutated {
+		span.SetAttributes(attribute.Bool("mutated", true))
+		j.metrics.mutatorMutationCount.Add(ctx, 1, mutator.Name())
+
+		patch, err := diffJob(before, out)
 		if err != nil {
-			return nil, nil, err
+			return result, fmt.Errorf("failed to diff job for mutator %s: %w", mutator.Name(), err)
+		}
+		if audit {
+			j.auditViolation(ctx, kindMutator, mutator.Name(), jobId, nil, patch)
+		} else {
+			result.mutation = &Mutation{Mutator: mutator.Name(), Patch: patch}
 		}
 	}
-	return job, warnings, err
+	j.metrics.mutatorWarningCount.Add(ctx, float64(len(w)), mutator.Name())
+
+	j.logger.DebugContext(ctx, "job mutate results", "mutator", mutator.Name(), "warnings", w, "audit", audit)
+
+	if !audit {
+		result.job = out
+		result.warnings = w
+	}
+	return result, nil
 }
 
 // AdmissionValidators returns a slice of validation warnings and a multierror
 // of validation failures.
 func (j *JobHandler) AdmissionValidators(ctx context.Context, payload *types.Payload) ([]error, error) {
 	if payload == nil || payload.Job == nil {
-		return nil, errors.New("admission payload must contain a job")
+		return nil, NewError(KindBadRequest, errors.New("admission payload must contain a job"))
 	}
 
 	ctx, span := j.tracer.Start(ctx, "validators.process")
 	defer span.End()
 	j.logger.DebugContext(ctx, "applying job validators", "validators", len(j.validators), "job", payload.Job.ID)
 
+	operation := operationOf(payload)
+	selected := make([]JobValidator, 0, len(j.validators))
+	for _, validator := range j.validators {
+		settings := j.validatorSettings[validator.Name()]
+		if !runsFor(settings.Operations, operation) {
+			continue
+		}
+		if !settings.Match.Matches(payload) {
+			j.skip(ctx, kindValidator, validator.Name())
+			continue
+		}
+		selected = append(selected, validator)
+	}
+
+	// validators run on their own job copies, so they can run concurrently;
+	// results are merged in configuration order to keep the output stable
+	results := make([]validatorResult, len(selected))
+	sem := make(chan struct{}, j.maxConcurrency)
+	var wg sync.WaitGroup
+	for i, validator := range selected {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = j.runValidator(ctx, payload, validator)
+		}()
+	}
+	wg.Wait()
+
 	var warnings []error
 	var errs error
+	var failed []string
+	kind := KindUpstream
+	for i, result := range results {
+		warnings = append(warnings, result.warnings...)
+		if result.err == nil {
+			continue
+		}
+		errs = multierror.Append(errs, result.err)
+		failed = append(failed, selected[i].Name())
+		// a rejection decides the request even if other validators failed
+		switch {
+		case isRejection(result.err):
+			kind = rejectionKind(operation)
+		case kind == KindUpstream && isTimeout(result.err):
+			kind = KindUpstreamTimeout
+		}
+	}
+	if errs != nil {
+		return warnings, NewError(kind, errs, failed...)
+	}
+	return warnings, nil
+
+}
+
+// callMutator calls the mutator like callValidator calls a validator.
+func callMutator(ctx context.Context, mutator JobMutator, payload *types.Payload, timeout time.Duration) (*api.Job, bool, []error, error) {
+	if timeout <= 0 {
+		return mutator.Mutate(ctx, payload)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type mutatorResult struct {
+		job      *api.Job
+		mutated  bool
+		warnings []error
+		err      error
+	}
+	done := make(chan mutatorResult, 1)
+	go func() {
+		job, mutated, w, err := mutator.Mutate(ctx, payload)
+		done <- mutatorResult{job: job, mutated: mutated, warnings: w, err: err}
+	}()
+	select {
+	case result := <-done:
+		return result.job, result.mutated, result.warnings, result.err
+	case <-ctx.Done():
+		return nil, false, nil, fmt.Errorf("mutator %s %w after %s", mutator.Name(), ErrTimeout, timeout)
+	}
+}
+
+type validatorResult struct {
+	warnings []error
+	err      error
+}
+
+// runValidator runs a single validator and applies its mode and enforcement
+// level to the outcome.
+func (j *JobHandler) runValidator(ctx context.Context, payload *types.Payload, validator JobValidator) (result validatorResult) {
+	settings := j.validatorSettings[validator.Name()]
+	job, copyErr := copyJob(payload.Job)
+	if copyErr != nil {
+		result.err = fmt.Errorf("failed to copy job for validator %s: %w", validator.Name(), copyErr)
+		return result
+	}
+	jobId := jobID(job)
+	ctx, span := j.tracer.Start(ctx, fmt.Sprintf("validate: %s", validator.Name()), trace.WithAttributes(
+		attribute.String(attrNomadJobID, jobId),
+		attribute.String("validator.name", validator.Name()),
+	))
+	defer span.End()
+	j.logger.DebugContext(ctx, "applying job validator", "validator", validator.Name(), "job", jobId)
+	w, err := callValidator(ctx, validator, withJob(payload, job), settings.Timeout)
+	j.metrics.validatorWarningCount.Add(ctx, float64(len(w)), validator.Name())
+	j.logger.DebugContext(ctx, "job validate results", "job", jobId, "validator", validator.Name(), "warnings", w, "error", err)
+
+	result.warnings = append(result.warnings, w...)
+	if err == nil {
+		return result
+	}
+	span.RecordError(err)
+	j.metrics.validatorErrorCount.Add(ctx, 1, validator.Name())
+	failure := !isRejection(err)
+	var violations []*types.Violation
+	if failure {
+		j.metrics.controllerFailureCount.Add(ctx, 1, kindValidator, validator.Name(), failurePolicyOf(settings.FailurePolicy))
+	} else {
+		violations = j.recordViolations(ctx, kindValidator, validator.Name(), err)
+	}
+
+	switch {
+	case j.modeOf(settings.Mode) == config.ModeAudit:
+		j.auditViolation(ctx, kindValidator, validator.Name(), jobId, err, nil)
+	case failure && settings.FailurePolicy == config.FailurePolicyIgnore:
+		result.warnings = append(result.warnings, j.ignoreFailure(ctx, kindValidator, validator.Name(), jobId, err))
+	case settings.EnforcementLevel == config.EnforcementAdvisory:
+		span.AddEvent("advisory validation failed")
+		j.logger.InfoContext(ctx, "advisory validator failed", "validator", validator.Name(), "job", jobId, "error", err, "violations", violations)
+		result.warnings = append(result.warnings, unwrapErrors(err)...)
+	case settings.EnforcementLevel == config.EnforcementSoftMandatory && canOverride(payload.Context, settings):
+		span.AddEvent("policy override")
+		j.logger.WarnContext(ctx, "soft-mandatory validator overridden", "validator", validator.Name(), "job", jobId, "accessorID", payload.Context.AccessorID, "error", err, "violations", violations)
+		j.metrics.validatorOverrideCount.Add(ctx, 1, validator.Name())
+		result.warnings = append(result.warnings, unwrapErrors(err)...)
+	default:
+		span.SetStatus(codes.Error, "error in validator")
+		j.logger.InfoContext(ctx, "validator rejected job", "validator", validator.Name(), "job", jobId, "error", err, "violations", violations)
+		result.err = err
+	}
+	return result
+}
+
+// callValidator calls the validator, giving up once timeout has passed even if
+// the validator does not honour the cancelled context. A zero timeout waits for
+// the validator to return.
+func callValidator(ctx context.Context, validator JobValidator, payload *types.Payload, timeout time.Duration) ([]error, error) {
+	if timeout <= 0 {
+		return validator.Validate(ctx, payload)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan validatorResult, 1)
+	go func() {
+		w, err := validator.Validate(ctx, payload)
+		done <- validatorResult{warnings: w, err: err}
+	}()
+	select {
+	case result := <-done:
+		return result.warnings, result.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("validator %s %w after %s", validator.Name(), ErrTimeout, timeout)
+	}
+}
 
+func (j *JobHandler) ResolveToken() bool {
+	return j.resolveToken
+}
+
+// HandlesOperation reports whether any mutator or validator runs for the
+// given operation.
+func (j *JobHandler) HandlesOperation(operation string) bool {
+	for _, mutator := range j.mutators {
+		settings := j.mutatorSettings[mutator.Name()]
+		if runsFor(settings.Operations, operation) {
+			return true
+		}
+	}
 	for _, validator := range j.validators {
+		settings := j.validatorSettings[validator.Name()]
+		if runsFor(settings.Operations, operation) {
+			return true
+		}
+	}
+	return false
+}
 
-		func() {
-			job, copyErr := copyJob(payload.Job)
-			if copyErr != nil {
-				errs = multierror.Append(errs, fmt.Errorf("failed to copy job for validator %s: %w", validator.Name(), copyErr))
-				return
+// Close releases what the mutators and validators hold, like plugin
+// processes or wasm runtimes.
+func (j *JobHandler) Close() error {
+	return CloseControllers(j.mutators, j.validators)
+}
+
+// CloseControllers closes the mutators and validators implementing
+// io.Closer.
+func CloseControllers(mutators []JobMutator, validators []JobValidator) error {
+	var errs []error
+	for _, mutator := range mutators {
+		if closer, ok := mutator.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to close mutator %s: %w", mutator.Name(), err))
 			}
-			jobId := jobID(job)
-			ctx, span := j.tracer.Start(ctx, fmt.Sprintf("validate: %s", validator.Name()), trace.WithAttributes(
-				attribute.String(attrNomadJobID, jobId),
-				attribute.String("validator.name", validator.Name()),
-			))
-			defer span.End()
-			j.logger.DebugContext(ctx, "applying job validator", "validator", validator.Name(), "job", jobId)
-			w, err := validator.Validate(ctx, &types.Payload{Job: job, Context: payload.Context})
-			j.metrics.validatorWarningCount.Add(ctx, float64(len(w)), validator.Name())
-			j.logger.DebugContext(ctx, "job validate results", "job", jobId, "validator", validator.Name(), "warnings", w, "error", err)
-			if err != nil {
-				span.SetStatus(codes.Error, "error in validator")
-				span.RecordError(err)
-				j.metrics.validatorErrorCount.Add(ctx, 1, validator.Name())
-				errs = multierror.Append(errs, err)
+		}
+	}
+	for _, validator := range validators {
+		if closer, ok := validator.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to close validator %s: %w", validator.Name(), err))
 			}
-			warnings = append(warnings, w...)
-		}()
+		}
 	}
+	return errors.Join(errs...)
+}
 
-	return warnings, errs
+// skip records that a controller's match block did not select the job.
+func (j *JobHandler) skip(ctx context.Context, kind, name string) {
+	j.metrics.controllerSkipCount.Add(ctx, 1, kind, name)
+	trace.SpanFromContext(ctx).AddEvent("controller.skipped", trace.WithAttributes(
+		attribute.String("controller.kind", kind),
+		attribute.String("controller.name", name),
+	))
+	j.logger.DebugContext(ctx, "skipping controller, match did not select the job", "kind", kind, "controller", name)
+}
 
+// modeOf returns the effective mode for a controller configured with mode.
+func (j *JobHandler) modeOf(mode string) string {
+	if mode == "" {
+		return j.mode
+	}
+	return mode
 }
 
-func (j *JobHandler) ResolveToken() bool {
-	return j.resolveToken
+// auditViolation records a rejection or patch a controller in audit mode
+// would have applied, without applying it.
+func (j *JobHandler) auditViolation(ctx context.Context, kind, name, jobId string, err error, patch []jsonpatcher.Operation) {
+	j.metrics.auditViolationCount.Add(ctx, 1, kind, name)
+
+	attrs := []attribute.KeyValue{
+		attribute.String("controller.kind", kind),
+		attribute.String("controller.name", name),
+		attribute.String(attrNomadJobID, jobId),
+	}
+	logAttrs := []any{"kind", kind, "controller", name, "job", jobId}
+	if err != nil {
+		attrs = append(attrs, attribute.String("audit.error", err.Error()))
+		logAttrs = append(logAttrs, "error", err.Error())
+		if violations := types.Violations(unwrapErrors(err)); len(violations) > 0 {
+			logAttrs = append(logAttrs, "violations", violations)
+		}
+	}
+	if patch != nil {
+		data, marshalErr := json.Marshal(patch)
+		if marshalErr != nil {
+			j.logger.ErrorContext(ctx, "failed to marshal audit patch", "controller", name, "error", marshalErr)
+		}
+		attrs = append(attrs, attribute.String("audit.patch", string(data)))
+		logAttrs = append(logAttrs, "patch", patch)
+	}
+	trace.SpanFromContext(ctx).AddEvent("audit.violation", trace.WithAttributes(attrs...))
+	j.logger.InfoContext(ctx, "admission audit violation", logAttrs...)
+}
+
+// recordViolations counts the violations in a controller's rejection and adds
+// them to the span as events, so the offending fields show up in traces.
+func (j *JobHandler) recordViolations(ctx context.Context, kind, name string, err error) []*types.Violation {
+	violations := types.Violations(unwrapErrors(err))
+	span := trace.SpanFromContext(ctx)
+	for _, violation := range violations {
+		j.metrics.policyViolationCount.Add(ctx, 1, kind, name, severityOf(violation.Severity))
+		span.AddEvent("policy.violation", trace.WithAttributes(
+			attribute.String("controller.kind", kind),
+			attribute.String("controller.name", name),
+			attribute.String("violation.message", violation.Message),
+			attribute.String("violation.code", violation.Code),
+			attribute.String("violation.path", violation.Path),
+			attribute.String("violation.severity", violation.Severity),
+		))
+	}
+	return violations
+}
+
+// ignoreFailure records a controller failure let through by the ignore
+// failure policy and returns the warning reported in its place.
+func (j *JobHandler) ignoreFailure(ctx context.Context, kind, name, jobId string, err error) error {
+	trace.SpanFromContext(ctx).AddEvent("failure ignored")
+	j.logger.WarnContext(ctx, "ignoring controller failure", "kind", kind, "controller", name, "job", jobId, "error", err)
+	return fmt.Errorf("%s %s failed and was ignored: %w", kind, name, err)
+}
+
+// isRejection reports whether err is a policy decision rather than a failure
+// of the controller itself. Controllers report policy errors as a
+// *multierror.Error; anything else means the controller could not decide.
+func isRejection(err error) bool {
+	var merr *multierror.Error
+	return errors.As(err, &merr)
+}
+
+// metricSeverities are the violation severities counted as they are; any
+// other value a policy sets is counted as other, so policies cannot grow the
+// number of metric series without bound.
+var metricSeverities = []string{"low", "medium", "high", "critical"}
+
+// severityOf maps a violation's severity to one of metricSeverities, other,
+// or empty when the violation has none.
+func severityOf(severity string) string {
+	severity = strings.ToLower(severity)
+	if severity == "" || slices.Contains(metricSeverities, severity) {
+		return severity
+	}
+	return "other"
+}
+
+func failurePolicyOf(policy string) string {
+	if policy == "" {
+		return config.FailurePolicyFail
+	}
+	return policy
+}
+
+// canOverride reports whether the request asked for a policy override and its
+// token carries one of the policies or roles allowed to override.
+func canOverride(reqCtx *config.RequestContext, settings ValidatorSettings) bool {
+	if reqCtx == nil || !reqCtx.PolicyOverride || reqCtx.TokenInfo == nil {
+		return false
+	}
+	for _, policy := range reqCtx.TokenInfo.Policies {
+		if slices.Contains(settings.OverridePolicies, policy) {
+			return true
+		}
+	}
+	for _, role := range reqCtx.TokenInfo.Roles {
+		if role != nil && slices.Contains(settings.OverrideRoles, role.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// unwrapErrors returns the errors collected in a multierror, or err itself.
+func unwrapErrors(err error) []error {
+	var merr *multierror.Error
+	if errors.As(err, &merr) {
+		return merr.WrappedErrors()
+	}
+	return []error{err}
+}
+
+func runsFor(operations []string, operation string) bool {
+	if len(operations) == 0 {
+		operations = config.JobOperations
+	}
+	return slices.Contains(operations, operation)
+}
+
+// operationOf returns the payload's operation, treating payloads without one
+// as registrations.
+func operationOf(payload *types.Payload) string {
+	if payload.Operation == "" {
+		return config.OperationRegister
+	}
+	return payload.Operation
+}
+
+// withJob returns a shallow copy of payload carrying job instead.
+func withJob(payload *types.Payload, job *api.Job) *types.Payload {
+	p := *payload
+	p.Job = job
+	return &p
+}
+
+func diffJob(before []byte, job *api.Job) ([]jsonpatcher.Operation, error) {
+	after, err := json.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+	return jsonpatcher.CreatePatch(before, after)
 }
 
 func copyJob(job *api.Job) (*api.Job, error) {
@@ -248,6 +968,19 @@ func copyJob(job *api.Job) (*api.Job, error) {
 	return jobCopy, nil
 }
 
+// recordProvenance writes the provenance to the job meta key, removing a stale
+// record of an earlier admission when no mutator changed the job.
+func recordProvenance(job *api.Job, key, provenance string) {
+	if provenance == "" {
+		delete(job.Meta, key)
+		return
+	}
+	if job.Meta == nil {
+		job.Meta = map[string]string{}
+	}
+	job.Meta[key] = provenance
+}
+
 func jobID(job *api.Job) string {
 	if job == nil || job.ID == nil {
 		return ""
@@ -1,14 +1,23 @@
 package admissionctrl
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/mitchellh/copystructure"
+	"github.com/mxab/nacp/pkg/admissionctrl/mutator/jsonpatcher"
 	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/config"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/api"
 	"github.com/mxab/nacp/testutil"
 	"github.com/stretchr/testify/assert"
@@ -48,6 +57,18 @@ func (v validatorFunc) Validate(_ context.Context, payload *types.Payload) ([]er
 	return v.validate(payload)
 }
 
+// closingValidator records whether it was closed.
+type closingValidator struct {
+	validatorFunc
+	closed bool
+	err    error
+}
+
+func (v *closingValidator) Close() error {
+	v.closed = true
+	return v.err
+}
+
 func TestJobHandler_ApplyAdmissionControllers(t *testing.T) {
 	type fields struct {
 		mutators  func() []JobMutator
@@ -303,3 +324,519 @@ func TestJobHandler_RejectsMissingJob(t *testing.T) {
 	_, _, err := handler.ApplyAdmissionControllers(t.Context(), &types.Payload{})
 	assert.ErrorContains(t, err, "must contain a job")
 }
+
+func TestJobHandler_ValidatorOperations(t *testing.T) {
+	var called []string
+	recording := func(name string) validatorFunc {
+		return validatorFunc{name: name, validate: func(*types.Payload) ([]error, error) {
+			called = append(called, name)
+			return nil, nil
+		}}
+	}
+
+	handler := NewJobHandler(
+		nil,
+		[]JobValidator{recording("job"), recording("dispatch"), recording("both")},
+		slog.New(slog.DiscardHandler),
+		false,
+		WithValidatorSettings("dispatch", ValidatorSettings{Operations: []string{config.OperationDispatch}}),
+		WithValidatorSettings("both", ValidatorSettings{Operations: []string{config.OperationRegister, config.OperationDispatch}}),
+	)
+
+	tests := []struct {
+		operation string
+		want      []string
+	}{
+		{operation: "", want: []string{"job", "both"}},
+		{operation: config.OperationPlan, want: []string{"job"}},
+		{operation: config.OperationDispatch, want: []string{"dispatch", "both"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.operation, func(t *testing.T) {
+			called = nil
+			_, err := handler.AdmissionValidators(t.Context(), &types.Payload{Job: testutil.BaseJob(), Operation: tc.operation})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, called)
+			assert.True(t, handler.HandlesOperation(operationOf(&types.Payload{Operation: tc.operation})))
+		})
+	}
+}
+
+func TestJobHandler_MutatorOperations(t *testing.T) {
+	handler := NewJobHandler(
+		[]JobMutator{&AddMetaMutator{Field: "job"}, &AddMetaMutator{Field: "scale"}},
+		nil,
+		slog.New(slog.DiscardHandler),
+		false,
+		WithMutatorSettings("scale", MutatorSettings{Operations: []string{config.OperationScale}}),
+	)
+
+	job, _, err := handler.AdmissionMutators(t.Context(), &types.Payload{Job: testutil.BaseJob(), Operation: config.OperationScale})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"scale": "applied"}, job.Meta)
+
+	job, _, err = handler.AdmissionMutators(t.Context(), &types.Payload{Job: testutil.BaseJob(), Operation: config.OperationRegister})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"job": "applied"}, job.Meta)
+
+	assert.True(t, handler.HandlesOperation(config.OperationScale))
+	assert.False(t, handler.HandlesOperation(config.OperationDeregister))
+}
+
+func TestJobHandler_AdmitRecordsMutations(t *testing.T) {
+	handler := NewJobHandler(
+		[]JobMutator{&AddMetaMutator{Field: "first"}, &AddMetaMutator{Field: "second"}},
+		nil,
+		slog.New(slog.DiscardHandler),
+		false,
+	)
+
+	admission, err := handler.Admit(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"first": "applied", "second": "applied"}, admission.Job.Meta)
+	assert.Equal(t, []Mutation{
+		{Mutator: "first", Patch: []jsonpatcher.Operation{{Op: "replace", Path: "/Meta", Value: map[string]interface{}{"first": "applied"}}}},
+		{Mutator: "second", Patch: []jsonpatcher.Operation{{Op: "add", Path: "/Meta/second", Value: "applied"}}},
+	}, admission.Mutations)
+}
+
+func TestJobHandler_EnforcementLevels(t *testing.T) {
+	failing := func(name string) validatorFunc {
+		return validatorFunc{name: name, validate: func(*types.Payload) ([]error, error) {
+			return nil, multierror.Append(nil, fmt.Errorf("%s failed", name))
+		}}
+	}
+	handler := NewJobHandler(
+		nil,
+		[]JobValidator{failing("advisory"), failing("soft"), failing("hard")},
+		slog.New(slog.DiscardHandler),
+		false,
+		WithValidatorSettings("advisory", ValidatorSettings{EnforcementLevel: config.EnforcementAdvisory}),
+		WithValidatorSettings("soft", ValidatorSettings{
+			EnforcementLevel: config.EnforcementSoftMandatory,
+			OverridePolicies: []string{"ops"},
+			OverrideRoles:    []string{"release-manager"},
+		}),
+		WithValidatorSettings("hard", ValidatorSettings{EnforcementLevel: config.EnforcementHardMandatory}),
+	)
+
+	tests := []struct {
+		name         string
+		context      *config.RequestContext
+		wantWarnings []string
+		wantErrors   []string
+	}{
+		{
+			name:         "no override requested",
+			context:      &config.RequestContext{TokenInfo: &config.ACLTokenContext{Policies: []string{"ops"}}},
+			wantWarnings: []string{"advisory failed"},
+			wantErrors:   []string{"soft failed", "hard failed"},
+		},
+		{
+			name:         "override without an allowed policy",
+			context:      &config.RequestContext{PolicyOverride: true, TokenInfo: &config.ACLTokenContext{Policies: []string{"dev"}}},
+			wantWarnings: []string{"advisory failed"},
+			wantErrors:   []string{"soft failed", "hard failed"},
+		},
+		{
+			name:         "override by policy",
+			context:      &config.RequestContext{PolicyOverride: true, TokenInfo: &config.ACLTokenContext{Policies: []string{"ops"}}},
+			wantWarnings: []string{"advisory failed", "soft failed"},
+			wantErrors:   []string{"hard failed"},
+		},
+		{
+			name: "override by role",
+			context: &config.RequestContext{PolicyOverride: true, TokenInfo: &config.ACLTokenContext{
+				Roles: []*api.ACLTokenRoleLink{{Name: "release-manager"}},
+			}},
+			wantWarnings: []string{"advisory failed", "soft failed"},
+			wantErrors:   []string{"hard failed"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings, err := handler.AdmissionValidators(t.Context(), &types.Payload{Job: testutil.BaseJob(), Context: tc.context})
+			var gotWarnings, gotErrors []string
+			for _, w := range warnings {
+				gotWarnings = append(gotWarnings, w.Error())
+			}
+			for _, e := range unwrapErrors(err) {
+				gotErrors = append(gotErrors, e.Error())
+			}
+			assert.Equal(t, tc.wantWarnings, gotWarnings)
+			assert.Equal(t, tc.wantErrors, gotErrors)
+		})
+	}
+}
+
+func TestJobHandler_AuditMode(t *testing.T) {
+	var logs bytes.Buffer
+	rejecting := validatorFunc{name: "reject", validate: func(*types.Payload) ([]error, error) {
+		return []error{fmt.Errorf("audit warning")}, fmt.Errorf("job rejected")
+	}}
+	handler := NewJobHandler(
+		[]JobMutator{&AddMetaMutator{Field: "audited"}, &AddMetaMutator{Field: "enforced"}},
+		[]JobValidator{rejecting},
+		slog.New(slog.NewJSONHandler(&logs, nil)),
+		false,
+		WithMode(config.ModeAudit),
+		WithMutatorSettings("enforced", MutatorSettings{Mode: config.ModeEnforce}),
+	)
+
+	admission, err := handler.Admit(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"enforced": "applied"}, admission.Job.Meta)
+	assert.Equal(t, []error{fmt.Errorf("audit warning")}, admission.Warnings)
+	assert.Equal(t, []string{"enforced"}, func() (names []string) {
+		for _, m := range admission.Mutations {
+			names = append(names, m.Mutator)
+		}
+		return names
+	}())
+
+	var violations []map[string]any
+	for line := range strings.Lines(logs.String()) {
+		entry := map[string]any{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+		if entry["msg"] == "admission audit violation" {
+			violations = append(violations, entry)
+		}
+	}
+	if assert.Len(t, violations, 2) {
+		assert.Equal(t, "audited", violations[0]["controller"])
+		assert.Equal(t, []any{map[string]any{"op": "replace", "path": "/Meta", "value": map[string]any{"audited": "applied"}}}, violations[0]["patch"])
+		assert.Equal(t, "reject", violations[1]["controller"])
+		assert.Equal(t, "job rejected", violations[1]["error"])
+	}
+}
+
+func TestJobHandler_Violations(t *testing.T) {
+	var logs bytes.Buffer
+	violation := &types.Violation{Message: "memory too high", Code: "MEM001", Path: "/TaskGroups/0/Tasks/0/Resources/MemoryMB", Severity: "high"}
+	rejecting := validatorFunc{name: "memory", validate: func(*types.Payload) ([]error, error) {
+		return nil, multierror.Append(nil, violation)
+	}}
+	handler := NewJobHandler(nil, []JobValidator{rejecting}, slog.New(slog.NewJSONHandler(&logs, nil)), false)
+
+	_, err := handler.AdmissionValidators(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	assert.Error(t, err)
+	assert.Equal(t, []*types.Violation{violation}, types.Violations(unwrapErrors(err)))
+
+	var rejections []map[string]any
+	for line := range strings.Lines(logs.String()) {
+		entry := map[string]any{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+		if entry["msg"] == "validator rejected job" {
+			rejections = append(rejections, entry)
+		}
+	}
+	if assert.Len(t, rejections, 1) {
+		assert.Equal(t, []any{map[string]any{
+			"message":  "memory too high",
+			"code":     "MEM001",
+			"path":     "/TaskGroups/0/Tasks/0/Resources/MemoryMB",
+			"severity": "high",
+		}}, rejections[0]["violations"])
+	}
+}
+
+func TestJobHandler_Match(t *testing.T) {
+	var called []string
+	recording := func(name string) validatorFunc {
+		return validatorFunc{name: name, validate: func(*types.Payload) ([]error, error) {
+			called = append(called, name)
+			return nil, nil
+		}}
+	}
+	handler := NewJobHandler(
+		[]JobMutator{&AddMetaMutator{Field: "batch-only"}, &AddMetaMutator{Field: "all"}},
+		[]JobValidator{recording("plan-only"), recording("all")},
+		slog.New(slog.DiscardHandler),
+		false,
+		WithMutatorSettings("batch-only", MutatorSettings{Match: &Match{JobTypes: []string{"batch"}}}),
+		WithValidatorSettings("plan-only", ValidatorSettings{Operations: []string{config.OperationPlan}}),
+	)
+
+	job, _, err := handler.ApplyAdmissionControllers(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"all": "applied"}, job.Meta)
+	assert.Equal(t, []string{"all"}, called)
+
+	called = nil
+	batchJob := testutil.BaseJob()
+	batchJob.Type = pointer("batch")
+	job, _, err = handler.ApplyAdmissionControllers(t.Context(), &types.Payload{Job: batchJob, Operation: config.OperationPlan})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"batch-only": "applied", "all": "applied"}, job.Meta)
+	assert.Equal(t, []string{"plan-only", "all"}, called)
+}
+
+func TestJobHandler_Close(t *testing.T) {
+	first := &closingValidator{validatorFunc: validatorFunc{name: "first"}}
+	failing := &closingValidator{validatorFunc: validatorFunc{name: "failing"}, err: errors.New("process already gone")}
+	handler := NewJobHandler(
+		[]JobMutator{&AddMetaMutator{Field: "not a closer"}},
+		[]JobValidator{first, failing},
+		slog.New(slog.DiscardHandler),
+		false,
+	)
+
+	err := handler.Close()
+	assert.EqualError(t, err, "failed to close validator failing: process already gone")
+	assert.True(t, first.closed)
+	assert.True(t, failing.closed)
+}
+
+func TestJobHandler_ConcurrentValidators(t *testing.T) {
+	// every validator waits until all three are running, so this only
+	// finishes when they run concurrently
+	var started sync.WaitGroup
+	started.Add(3)
+	waiting := func(name string, delay time.Duration) validatorFunc {
+		return validatorFunc{name: name, validate: func(*types.Payload) ([]error, error) {
+			started.Done()
+			started.Wait()
+			time.Sleep(delay)
+			return []error{fmt.Errorf("%s warning", name)}, fmt.Errorf("%s error", name)
+		}}
+	}
+	handler := NewJobHandler(
+		nil,
+		[]JobValidator{waiting("first", 30*time.Millisecond), waiting("second", 0), waiting("third", 10*time.Millisecond)},
+		slog.New(slog.DiscardHandler),
+		false,
+		WithMaxConcurrency(3),
+	)
+
+	warnings, err := handler.AdmissionValidators(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	assert.Equal(t, []error{fmt.Errorf("first warning"), fmt.Errorf("second warning"), fmt.Errorf("third warning")}, warnings)
+	assert.Equal(t, []error{fmt.Errorf("first error"), fmt.Errorf("second error"), fmt.Errorf("third error")}, unwrapErrors(err))
+}
+
+func TestJobHandler_ValidatorTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	stuck := validatorFunc{name: "stuck", validate: func(*types.Payload) ([]error, error) {
+		<-release // ignores the context on purpose
+		return nil, nil
+	}}
+	fast := validatorFunc{name: "fast", validate: func(*types.Payload) ([]error, error) {
+		return []error{fmt.Errorf("fast warning")}, nil
+	}}
+	handler := NewJobHandler(
+		nil,
+		[]JobValidator{stuck, fast},
+		slog.New(slog.DiscardHandler),
+		false,
+		WithMaxConcurrency(2),
+		WithValidatorSettings("stuck", ValidatorSettings{Timeout: 20 * time.Millisecond}),
+	)
+
+	warnings, err := handler.AdmissionValidators(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	assert.Equal(t, []error{fmt.Errorf("fast warning")}, warnings)
+	assert.EqualError(t, unwrapErrors(err)[0], "validator stuck timed out after 20ms")
+}
+
+// failingMutator changes the job in place and then fails.
+type failingMutator struct {
+	name string
+	err  error
+}
+
+func (m *failingMutator) Name() string { return m.name }
+
+func (m *failingMutator) Mutate(_ context.Context, payload *types.Payload) (*api.Job, bool, []error, error) {
+	payload.Job.Meta = map[string]string{"partial": "change"}
+	return nil, false, nil, m.err
+}
+
+func TestJobHandler_FailurePolicy(t *testing.T) {
+	unreachable := validatorFunc{name: "unreachable", validate: func(*types.Payload) ([]error, error) {
+		return nil, fmt.Errorf("connection refused")
+	}}
+	rejecting := validatorFunc{name: "rejecting", validate: func(*types.Payload) ([]error, error) {
+		return nil, multierror.Append(nil, fmt.Errorf("missing costcenter"))
+	}}
+	handler := NewJobHandler(
+		[]JobMutator{&failingMutator{name: "broken", err: fmt.Errorf("webhook down")}, &AddMetaMutator{Field: "next"}},
+		[]JobValidator{unreachable, rejecting},
+		slog.New(slog.DiscardHandler),
+		false,
+		WithMutatorSettings("broken", MutatorSettings{FailurePolicy: config.FailurePolicyIgnore}),
+		WithValidatorSettings("unreachable", ValidatorSettings{FailurePolicy: config.FailurePolicyIgnore}),
+		WithValidatorSettings("rejecting", ValidatorSettings{FailurePolicy: config.FailurePolicyIgnore}),
+	)
+
+	job, warnings, err := handler.AdmissionMutators(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"next": "applied"}, job.Meta, "partial changes of an ignored mutator are discarded")
+	if assert.Len(t, warnings, 1) {
+		assert.EqualError(t, warnings[0], "mutator broken failed and was ignored: webhook down")
+	}
+
+	warnings, err = handler.AdmissionValidators(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	if assert.Len(t, warnings, 1) {
+		assert.EqualError(t, warnings[0], "validator unreachable failed and was ignored: connection refused")
+	}
+	assert.EqualError(t, unwrapErrors(err)[0], "missing costcenter", "rejections are not failures")
+}
+
+func TestJobHandler_FailurePolicyFail(t *testing.T) {
+	handler := NewJobHandler(
+		[]JobMutator{&failingMutator{name: "broken", err: fmt.Errorf("webhook down")}},
+		nil,
+		slog.New(slog.DiscardHandler),
+		false,
+	)
+	_, _, err := handler.AdmissionMutators(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	assert.EqualError(t, err, "error in job mutator broken: webhook down")
+}
+
+type mutatorFunc struct {
+	name   string
+	mutate func(*api.Job) bool
+}
+
+func (m mutatorFunc) Name() string { return m.name }
+
+func (m mutatorFunc) Mutate(_ context.Context, payload *types.Payload) (*api.Job, bool, []error, error) {
+	job, err := copyJob(payload.Job)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	return job, m.mutate(job), nil, nil
+}
+
+func TestJobHandler_Reinvocation(t *testing.T) {
+	newJob := func() *api.Job {
+		job := testutil.BaseJob()
+		job.TaskGroups = []*api.TaskGroup{{Name: pointer("web"), Tasks: []*api.Task{{Name: "app"}}}}
+		return job
+	}
+	var defaultsCalls int
+	defaults := mutatorFunc{name: "defaults", mutate: func(job *api.Job) bool {
+		defaultsCalls++
+		mutated := false
+		for _, task := range job.TaskGroups[0].Tasks {
+			if task.Resources == nil {
+				task.Resources = &api.Resources{MemoryMB: pointer(128)}
+				mutated = true
+			}
+		}
+		return mutated
+	}}
+	sidecar := mutatorFunc{name: "sidecar", mutate: func(job *api.Job) bool {
+		for _, task := range job.TaskGroups[0].Tasks {
+			if task.Name == "envoy" {
+				return false
+			}
+		}
+		job.TaskGroups[0].Tasks = append(job.TaskGroups[0].Tasks, &api.Task{Name: "envoy"})
+		return true
+	}}
+
+	t.Run("if_needed reruns earlier mutators", func(t *testing.T) {
+		defaultsCalls = 0
+		handler := NewJobHandler([]JobMutator{defaults, sidecar}, nil, slog.New(slog.DiscardHandler), false,
+			WithMutatorSettings("defaults", MutatorSettings{ReinvocationPolicy: config.ReinvocationIfNeeded}),
+		)
+		admission, err := handler.Admit(t.Context(), &types.Payload{Job: newJob()})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, defaultsCalls)
+		tasks := admission.Job.TaskGroups[0].Tasks
+		if assert.Len(t, tasks, 2) {
+			assert.Equal(t, "envoy", tasks[1].Name)
+			assert.Equal(t, pointer(128), tasks[1].Resources.MemoryMB)
+		}
+		assert.Equal(t, []string{"defaults", "sidecar", "defaults"}, func() (names []string) {
+			for _, m := range admission.Mutations {
+				names = append(names, m.Mutator)
+			}
+			return names
+		}())
+	})
+
+	t.Run("never runs mutators once", func(t *testing.T) {
+		defaultsCalls = 0
+		handler := NewJobHandler([]JobMutator{defaults, sidecar}, nil, slog.New(slog.DiscardHandler), false)
+		admission, err := handler.Admit(t.Context(), &types.Payload{Job: newJob()})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, defaultsCalls)
+		assert.Nil(t, admission.Job.TaskGroups[0].Tasks[1].Resources)
+	})
+
+	t.Run("jobs that keep changing fail", func(t *testing.T) {
+		counter := func(name string) mutatorFunc {
+			return mutatorFunc{name: name, mutate: func(job *api.Job) bool {
+				if job.Meta == nil {
+					job.Meta = map[string]string{}
+				}
+				job.Meta[name] += "+"
+				return true
+			}}
+		}
+		handler := NewJobHandler([]JobMutator{counter("ping"), counter("pong")}, nil, slog.New(slog.DiscardHandler), false,
+			WithMaxMutatorPasses(4),
+			WithMutatorSettings("ping", MutatorSettings{ReinvocationPolicy: config.ReinvocationIfNeeded}),
+			WithMutatorSettings("pong", MutatorSettings{ReinvocationPolicy: config.ReinvocationIfNeeded}),
+		)
+		_, err := handler.Admit(t.Context(), &types.Payload{Job: newJob()})
+		assert.EqualError(t, err, "job still changed after 4 mutator passes, last changed by ping, pong")
+		admissionErr := AsError(err)
+		assert.Equal(t, KindInternal, admissionErr.Kind)
+		assert.Equal(t, []string{"ping", "pong"}, admissionErr.Controllers)
+	})
+}
+
+func TestJobHandler_Provenance(t *testing.T) {
+	handler := NewJobHandler(
+		[]JobMutator{&AddMetaMutator{Field: "first"}, mutatorFunc{name: "noop", mutate: func(*api.Job) bool { return false }}, &AddMetaMutator{Field: "second"}},
+		nil,
+		slog.New(slog.DiscardHandler),
+		false,
+		WithProvenance("nacp.mutated-by"),
+	)
+
+	admission, err := handler.Admit(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	assert.NoError(t, err)
+	assert.Len(t, admission.Mutations, 2)
+	want := "first:" + admission.Mutations[0].Digest() + ",second:" + admission.Mutations[1].Digest()
+	assert.Equal(t, want, admission.Provenance)
+	assert.Equal(t, want, admission.Job.Meta["nacp.mutated-by"])
+	assert.NotEqual(t, admission.Mutations[0].Digest(), admission.Mutations[1].Digest())
+
+	// a stale record is dropped when nothing changes the job
+	unchanged := NewJobHandler(nil, nil, slog.New(slog.DiscardHandler), false, WithProvenance("nacp.mutated-by"))
+	job := testutil.BaseJob()
+	job.Meta = map[string]string{"nacp.mutated-by": "old:0123456789ab", "team": "a"}
+	admission, err = unchanged.Admit(t.Context(), &types.Payload{Job: job})
+	assert.NoError(t, err)
+	assert.Empty(t, admission.Provenance)
+	assert.Equal(t, map[string]string{"team": "a"}, admission.Job.Meta)
+}
+
+func TestSeverityOf(t *testing.T) {
+	for severity, want := range map[string]string{"": "", "high": "high", "Critical": "critical", "sev-1": "other"} {
+		assert.Equal(t, want, severityOf(severity), severity)
+	}
+}
+
+func TestMutationSummary(t *testing.T) {
+	mutations := []Mutation{
+		{Mutator: "defaults", Patch: []jsonpatcher.Operation{
+			{Op: "add", Path: "/Priority", Value: 50},
+			{Op: "replace", Path: "/Meta", Value: map[string]string{"secret": "value"}},
+		}},
+		{Mutator: "noop"},
+		{Mutator: "otel", Patch: []jsonpatcher.Operation{{Op: "remove", Path: "/Meta/old"}}},
+	}
+	assert.Equal(t, "defaults: add /Priority, replace /Meta; otel: remove /Meta/old", MutationSummary(mutations))
+
+	large := Mutation{Mutator: "large"}
+	for i := range 200 {
+		large.Patch = append(large.Patch, jsonpatcher.Operation{Op: "add", Path: fmt.Sprintf("/Meta/key%d", i), Value: "x"})
+	}
+	summary := MutationSummary([]Mutation{large})
+	assert.Len(t, summary, maxMutationSummaryBytes)
+	assert.True(t, strings.HasSuffix(summary, "..."))
+}
@@ -0,0 +1,119 @@
+package admissionctrl
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/mxab/nacp/pkg/config"
+)
+
+// ErrorKind classifies an admission error by what went wrong, which decides
+// the HTTP status reported to the client.
+type ErrorKind int
+
+const (
+	// KindInternal is a failure of NACP itself.
+	KindInternal ErrorKind = iota
+	// KindBadRequest is a request NACP cannot make sense of.
+	KindBadRequest
+	// KindInvalidJob is a job specification rejected by policy.
+	KindInvalidJob
+	// KindDenied is an operation on an existing job rejected by policy.
+	KindDenied
+	// KindUpstream is a failure of Nomad or of an admission controller's
+	// integration, e.g. an unreachable webhook.
+	KindUpstream
+	// KindUpstreamTimeout is an upstream failure caused by a timeout.
+	KindUpstreamTimeout
+)
+
+// ErrTimeout is wrapped by the errors of controllers the handler gave up on.
+var ErrTimeout = errors.New("timed out")
+
+// Error is an admission error carrying its kind and the controllers that
+// rejected the request or failed.
+type Error struct {
+	Kind        ErrorKind
+	Controllers []string
+	Err         error
+}
+
+// NewError wraps err as an admission error of the given kind.
+func NewError(kind ErrorKind, err error, controllers ...string) *Error {
+	return &Error{Kind: kind, Controllers: controllers, Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns the HTTP status Nomad would use for a comparable error.
+func (e *Error) StatusCode() int {
+	switch e.Kind {
+	case KindBadRequest, KindInvalidJob:
+		return http.StatusBadRequest
+	case KindDenied:
+		return http.StatusForbidden
+	case KindUpstream:
+		return http.StatusBadGateway
+	case KindUpstreamTimeout:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Rejected reports whether the error is a policy decision.
+func (e *Error) Rejected() bool {
+	return e.Kind == KindInvalidJob || e.Kind == KindDenied
+}
+
+// AsError returns the admission error in err's chain, treating errors without
+// one as internal errors.
+func AsError(err error) *Error {
+	var admissionErr *Error
+	if errors.As(err, &admissionErr) {
+		return admissionErr
+	}
+	return &Error{Kind: KindInternal, Err: err}
+}
+
+// rejectionKind returns the kind of a policy rejection for the operation.
+// Rejected job specifications are bad requests, like Nomad's own job
+// validation errors; rejected operations on existing jobs are denials.
+func rejectionKind(operation string) ErrorKind {
+	switch operation {
+	case config.OperationDispatch, config.OperationDeregister, config.OperationScale:
+		return KindDenied
+	default:
+		return KindInvalidJob
+	}
+}
+
+// failureKind returns the kind of a controller failure.
+func failureKind(err error) ErrorKind {
+	if isTimeout(err) {
+		return KindUpstreamTimeout
+	}
+	return KindUpstream
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// UpstreamError wraps a failed call to Nomad or an integration, telling
+// timeouts apart from other failures.
+func UpstreamError(err error) *Error {
+	return NewError(failureKind(err), err)
+}
@@ -0,0 +1,92 @@
+package admissionctrl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/config"
+	"github.com/mxab/nacp/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError_StatusCode(t *testing.T) {
+	tests := []struct {
+		kind ErrorKind
+		want int
+	}{
+		{kind: KindInternal, want: http.StatusInternalServerError},
+		{kind: KindBadRequest, want: http.StatusBadRequest},
+		{kind: KindInvalidJob, want: http.StatusBadRequest},
+		{kind: KindDenied, want: http.StatusForbidden},
+		{kind: KindUpstream, want: http.StatusBadGateway},
+		{kind: KindUpstreamTimeout, want: http.StatusGatewayTimeout},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, NewError(tc.kind, fmt.Errorf("boom")).StatusCode())
+	}
+}
+
+func TestAsError(t *testing.T) {
+	wrapped := fmt.Errorf("context: %w", NewError(KindDenied, fmt.Errorf("no"), "guard"))
+	admissionErr := AsError(wrapped)
+	assert.Equal(t, KindDenied, admissionErr.Kind)
+	assert.Equal(t, []string{"guard"}, admissionErr.Controllers)
+	assert.True(t, admissionErr.Rejected())
+
+	plain := AsError(fmt.Errorf("boom"))
+	assert.Equal(t, KindInternal, plain.Kind)
+	assert.EqualError(t, plain, "boom")
+
+	assert.Equal(t, KindUpstreamTimeout, UpstreamError(context.DeadlineExceeded).Kind)
+	assert.Equal(t, KindUpstream, UpstreamError(fmt.Errorf("connection refused")).Kind)
+}
+
+func TestJobHandler_ErrorKinds(t *testing.T) {
+	rejecting := validatorFunc{name: "rejecting", validate: func(*types.Payload) ([]error, error) {
+		return nil, multierror.Append(nil, fmt.Errorf("not allowed"))
+	}}
+	unreachable := validatorFunc{name: "unreachable", validate: func(*types.Payload) ([]error, error) {
+		return nil, fmt.Errorf("connection refused")
+	}}
+	release := make(chan struct{})
+	defer close(release)
+	stuck := validatorFunc{name: "stuck", validate: func(*types.Payload) ([]error, error) {
+		<-release
+		return nil, nil
+	}}
+
+	tests := []struct {
+		name            string
+		validators      []JobValidator
+		operation       string
+		wantKind        ErrorKind
+		wantControllers []string
+	}{
+		{name: "rejected job", validators: []JobValidator{rejecting}, operation: config.OperationRegister, wantKind: KindInvalidJob, wantControllers: []string{"rejecting"}},
+		{name: "denied operation", validators: []JobValidator{rejecting}, operation: config.OperationDeregister, wantKind: KindDenied, wantControllers: []string{"rejecting"}},
+		{name: "failed validator", validators: []JobValidator{unreachable}, operation: config.OperationRegister, wantKind: KindUpstream, wantControllers: []string{"unreachable"}},
+		{name: "timed out validator", validators: []JobValidator{stuck}, operation: config.OperationRegister, wantKind: KindUpstreamTimeout, wantControllers: []string{"stuck"}},
+		{name: "rejection wins over failure", validators: []JobValidator{unreachable, rejecting}, operation: config.OperationRegister, wantKind: KindInvalidJob, wantControllers: []string{"unreachable", "rejecting"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewJobHandler(nil, tc.validators, slog.New(slog.DiscardHandler), false,
+				WithValidatorSettings("stuck", ValidatorSettings{Timeout: 10 * time.Millisecond, Operations: []string{config.OperationRegister}}),
+				WithValidatorSettings("rejecting", ValidatorSettings{Operations: []string{config.OperationRegister, config.OperationDeregister}}),
+			)
+
+			_, err := handler.AdmissionValidators(t.Context(), &types.Payload{Job: testutil.BaseJob(), Operation: tc.operation})
+			require.Error(t, err)
+			admissionErr := AsError(err)
+			assert.Equal(t, tc.wantKind, admissionErr.Kind)
+			assert.Equal(t, tc.wantControllers, admissionErr.Controllers)
+		})
+	}
+}
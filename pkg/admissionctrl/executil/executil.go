@@ -0,0 +1,298 @@
+// Package executil runs the commands of the exec validator and mutator.
+//
+// By default every call starts the command, writes the JSON payload to its
+// stdin and reads the JSON response from its stdout. In persistent mode one
+// long-lived process handles all calls, reading one payload per line from
+// stdin and writing one response per line to stdout.
+package executil
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mxab/nacp/pkg/admissionctrl/remoteutil"
+)
+
+const (
+	DefaultCallTimeout = 5 * time.Second
+
+	// maxStderrBytes is how much of stderr is kept for error messages.
+	maxStderrBytes = 4 << 10
+	// waitDelay is how long a killed command may keep its pipes open.
+	waitDelay = time.Second
+)
+
+// Options configure a command.
+type Options struct {
+	Command string
+	Args    []string
+	// Env is added to the environment of NACP.
+	Env map[string]string
+	// CallTimeout aborts calls running longer. Zero means 5s.
+	CallTimeout time.Duration
+	// RejectExitCodes are the exit codes rejecting the job. Other non-zero
+	// exit codes are failures.
+	RejectExitCodes []int
+	// Persistent keeps one process running for all calls.
+	Persistent bool
+}
+
+// Rejection is returned when the command exits with one of the reject exit
+// codes. Its message is the command's stderr.
+type Rejection struct {
+	ExitCode int
+	Message  string
+}
+
+func (r *Rejection) Error() string {
+	return r.Message
+}
+
+// Command runs a command for each call, or in persistent mode a single
+// process shared by all calls.
+type Command struct {
+	options Options
+	env     []string
+
+	// lock guards process. It is a channel rather than a mutex so calls
+	// queued behind a slow process give up when their context is done.
+	lock    chan struct{}
+	process *process
+}
+
+func New(options Options) (*Command, error) {
+	if strings.TrimSpace(options.Command) == "" {
+		return nil, fmt.Errorf("exec command is required")
+	}
+	if _, err := exec.LookPath(options.Command); err != nil {
+		return nil, fmt.Errorf("exec command %q not found: %w", options.Command, err)
+	}
+	if options.CallTimeout == 0 {
+		options.CallTimeout = DefaultCallTimeout
+	}
+	env := os.Environ()
+	for key, value := range options.Env {
+		env = append(env, key+"="+value)
+	}
+	return &Command{options: options, env: env, lock: make(chan struct{}, 1)}, nil
+}
+
+// Call writes input to the command and returns what it answered.
+func (c *Command) Call(ctx context.Context, input []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.options.CallTimeout)
+	defer cancel()
+
+	var output []byte
+	var err error
+	if c.options.Persistent {
+		output, err = c.callProcess(ctx, input)
+	} else {
+		output, err = c.run(ctx, input)
+	}
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("exec command %s exceeded its call timeout of %s: %w", c.options.Command, c.options.CallTimeout, context.DeadlineExceeded)
+	}
+	return output, err
+}
+
+// Close stops the persistent process, if there is one.
+func (c *Command) Close() error {
+	c.lock <- struct{}{}
+	defer func() { <-c.lock }()
+	if c.process == nil {
+		return nil
+	}
+	err := c.process.stop()
+	c.process = nil
+	return err
+}
+
+func (c *Command) command(ctx context.Context) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, c.options.Command, c.options.Args...)
+	cmd.Env = c.env
+	cmd.WaitDelay = waitDelay
+	return cmd
+}
+
+func (c *Command) run(ctx context.Context, input []byte) ([]byte, error) {
+	cmd := c.command(ctx)
+	stdout := &limitedBuffer{limit: remoteutil.MaxResponseBodyBytes}
+	stderr := &tailBuffer{limit: maxStderrBytes}
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	if stdout.exceeded {
+		return nil, fmt.Errorf("exec command %s output exceeds %d bytes", c.options.Command, remoteutil.MaxResponseBodyBytes)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.Exited() && slices.Contains(c.options.RejectExitCodes, exitErr.ExitCode()) {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = fmt.Sprintf("%s exited with code %d", c.options.Command, exitErr.ExitCode())
+		}
+		return nil, &Rejection{ExitCode: exitErr.ExitCode(), Message: message}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("exec command %s failed: %w%s", c.options.Command, err, describeStderr(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+func (c *Command) callProcess(ctx context.Context, input []byte) ([]byte, error) {
+	select {
+	case c.lock <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-c.lock }()
+	if err := ctx.Err(); err != nil {
+		// the call waited its whole time for the process
+		return nil, err
+	}
+	if c.process == nil {
+		process, err := c.start()
+		if err != nil {
+			return nil, err
+		}
+		c.process = process
+	}
+
+	type result struct {
+		output []byte
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		output, err := c.process.exchange(input)
+		done <- result{output, err}
+	}()
+	select {
+	case r := <-done:
+		if r.err != nil {
+			c.process.stop()
+			c.process = nil
+			return nil, fmt.Errorf("exec command %s failed: %w", c.options.Command, r.err)
+		}
+		return r.output, nil
+	case <-ctx.Done():
+		// stopping the process unblocks the exchange
+		c.process.stop()
+		<-done
+		c.process = nil
+		return nil, ctx.Err()
+	}
+}
+
+// process is a long-lived command exchanging newline-delimited JSON.
+type process struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+}
+
+func (c *Command) start() (*process, error) {
+	// the process outlives the call starting it
+	cmd := c.command(context.Background())
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start exec command %s: %w", c.options.Command, err)
+	}
+	return &process{cmd: cmd, stdin: stdin, stdout: bufio.NewReader(stdout)}, nil
+}
+
+func (p *process) exchange(input []byte) ([]byte, error) {
+	line := make([]byte, 0, len(input)+1)
+	line = append(append(line, bytes.TrimSpace(input)...), '\n')
+	if _, err := p.stdin.Write(line); err != nil {
+		return nil, fmt.Errorf("failed to write payload: %w", err)
+	}
+	var response []byte
+	for {
+		chunk, err := p.stdout.ReadSlice('\n')
+		response = append(response, chunk...)
+		if len(response) > remoteutil.MaxResponseBodyBytes+1 {
+			return nil, fmt.Errorf("output exceeds %d bytes", remoteutil.MaxResponseBodyBytes)
+		}
+		if err == nil {
+			return bytes.TrimSpace(response), nil
+		}
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+	}
+}
+
+func (p *process) stop() error {
+	p.stdin.Close()
+	p.cmd.Process.Kill()
+	err := p.cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return nil
+	}
+	return err
+}
+
+// limitedBuffer fails writes beyond its limit, which stops the command.
+type limitedBuffer struct {
+	buffer   bytes.Buffer
+	limit    int
+	exceeded bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.buffer.Len()+len(p) > b.limit {
+		b.exceeded = true
+		return 0, fmt.Errorf("output exceeds %d bytes", b.limit)
+	}
+	return b.buffer.Write(p)
+}
+
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buffer.Bytes()
+}
+
+// tailBuffer keeps the last limit bytes written to it.
+type tailBuffer struct {
+	data  []byte
+	limit int
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.data = append(b.data, p...)
+	if len(b.data) > b.limit {
+		b.data = b.data[len(b.data)-b.limit:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	return string(b.data)
+}
+
+func describeStderr(stderr string) string {
+	stderr = strings.TrimSpace(stderr)
+	if stderr == "" {
+		return ""
+	}
+	return ": " + stderr
+}
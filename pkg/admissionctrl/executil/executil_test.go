@@ -0,0 +1,154 @@
+package executil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func shell(t *testing.T, script string, options Options) *Command {
+	t.Helper()
+	options.Command = "sh"
+	options.Args = []string{"-c", script}
+	command, err := New(options)
+	require.NoError(t, err)
+	t.Cleanup(func() { command.Close() })
+	return command
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Options{})
+	assert.ErrorContains(t, err, "exec command is required")
+
+	_, err = New(Options{Command: "nacp-does-not-exist"})
+	assert.ErrorContains(t, err, `exec command "nacp-does-not-exist" not found`)
+}
+
+func TestCall(t *testing.T) {
+	tt := []struct {
+		name       string
+		script     string
+		options    Options
+		wantOutput string
+		wantErr    string
+		wantReject *Rejection
+	}{
+		{
+			name:       "reads stdin and writes stdout",
+			script:     `cat`,
+			wantOutput: `{"job":{"ID":"test-job"}}`,
+		},
+		{
+			name:       "passes args and env",
+			script:     `cat >/dev/null; printf '{"warnings":["%s %s"]}' "$GREETING" "$0"`,
+			options:    Options{Env: map[string]string{"GREETING": "hello"}},
+			wantOutput: `{"warnings":["hello sh"]}`,
+		},
+		{
+			name:    "non-zero exit code is a failure",
+			script:  `echo boom >&2; exit 4`,
+			options: Options{RejectExitCodes: []int{3}},
+			wantErr: "exec command sh failed: exit status 4: boom",
+		},
+		{
+			name:       "reject exit code rejects with stderr",
+			script:     `echo "job has no owner" >&2; exit 3`,
+			options:    Options{RejectExitCodes: []int{3}},
+			wantReject: &Rejection{ExitCode: 3, Message: "job has no owner"},
+		},
+		{
+			name:       "reject exit code without stderr",
+			script:     `exit 3`,
+			options:    Options{RejectExitCodes: []int{3}},
+			wantReject: &Rejection{ExitCode: 3, Message: "sh exited with code 3"},
+		},
+		{
+			name:    "output size cap",
+			script:  `head -c 11000000 /dev/zero`,
+			wantErr: "output exceeds 10485760 bytes",
+		},
+		{
+			name:    "timeout",
+			script:  `sleep 5`,
+			options: Options{CallTimeout: 100 * time.Millisecond},
+			wantErr: "exceeded its call timeout of 100ms",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			command := shell(t, tc.script, tc.options)
+			output, err := command.Call(t.Context(), []byte(`{"job":{"ID":"test-job"}}`))
+
+			switch {
+			case tc.wantReject != nil:
+				var rejection *Rejection
+				require.ErrorAs(t, err, &rejection)
+				assert.Equal(t, tc.wantReject, rejection)
+			case tc.wantErr != "":
+				assert.ErrorContains(t, err, tc.wantErr)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantOutput, string(output))
+			}
+		})
+	}
+}
+
+func TestCallPersistent(t *testing.T) {
+	command := shell(t, `while IFS= read -r line; do echo "{\"warnings\":[\"$$\"]}"; done`, Options{Persistent: true})
+
+	first, err := command.Call(t.Context(), []byte(`{"job":{}}`))
+	require.NoError(t, err)
+	second, err := command.Call(t.Context(), []byte(`{"job":{}}`))
+	require.NoError(t, err)
+	assert.Equal(t, string(first), string(second), "Calls are handled by the same process")
+
+	require.NoError(t, command.Close())
+	third, err := command.Call(t.Context(), []byte(`{"job":{}}`))
+	require.NoError(t, err)
+	assert.NotEqual(t, string(first), string(third), "A closed process is restarted")
+}
+
+func TestCallPersistentTimeout(t *testing.T) {
+	command := shell(t, `while IFS= read -r line; do case "$line" in *spin*) sleep 5;; esac; echo '{}'; done`, Options{Persistent: true, CallTimeout: 100 * time.Millisecond})
+
+	_, err := command.Call(t.Context(), []byte(`{"job":{"Meta":{"spin":"true"}}}`))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	output, err := command.Call(t.Context(), []byte(`{"job":{}}`))
+	require.NoError(t, err, "The stuck process is replaced")
+	assert.Equal(t, `{}`, string(output))
+}
+
+func TestCallPersistentQueued(t *testing.T) {
+	command := shell(t, `while IFS= read -r line; do case "$line" in *spin*) sleep 5;; esac; echo '{}'; done`, Options{Persistent: true, CallTimeout: 2 * time.Second})
+
+	go command.Call(context.Background(), []byte(`{"job":{"Meta":{"spin":"true"}}}`))
+	time.Sleep(100 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	begin := time.Now()
+	_, err := command.Call(ctx, []byte(`{"job":{}}`))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, time.Since(begin), time.Second, "A call queued behind a stuck process gives up at its own deadline")
+}
+
+func TestCallPersistentExit(t *testing.T) {
+	command := shell(t, `read -r line; echo '{}'`, Options{Persistent: true})
+
+	output, err := command.Call(t.Context(), []byte(`{"job":{}}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{}`, string(output))
+
+	_, err = command.Call(t.Context(), []byte(`{"job":{}}`))
+	assert.ErrorContains(t, err, "exec command sh failed")
+
+	output, err = command.Call(t.Context(), []byte(`{"job":{}}`))
+	require.NoError(t, err, "The exited process is restarted")
+	assert.Equal(t, `{}`, string(output))
+}
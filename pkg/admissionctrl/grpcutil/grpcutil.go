@@ -0,0 +1,110 @@
+// Package grpcutil connects the grpc validator and mutator to servers
+// implementing the Admission service of package admissionpb.
+package grpcutil
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"github.com/mxab/nacp/pkg/admissionctrl/remoteutil"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/grpc/admissionpb"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const DefaultCallTimeout = remoteutil.DefaultRequestTimeout
+
+// Options configure a client.
+type Options struct {
+	// Address is a gRPC target, e.g. dns:///policy.example.com:9090.
+	Address string
+	// TLS enables TLS. Nil means a plaintext connection.
+	TLS *tls.Config
+	// CallTimeout is the deadline of each call. Zero means 30s.
+	CallTimeout time.Duration
+}
+
+// Client calls an Admission server. It keeps one connection, which all
+// calls share.
+type Client struct {
+	address string
+	conn    *grpc.ClientConn
+	client  admissionpb.AdmissionClient
+	timeout time.Duration
+}
+
+// NewClient creates a client. It connects lazily, on the first call.
+func NewClient(options Options) (*Client, error) {
+	if options.Address == "" {
+		return nil, fmt.Errorf("grpc address is required")
+	}
+	if options.CallTimeout == 0 {
+		options.CallTimeout = DefaultCallTimeout
+	}
+	creds := insecure.NewCredentials()
+	if options.TLS != nil {
+		creds = credentials.NewTLS(options.TLS)
+	}
+	conn, err := grpc.NewClient(options.Address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(remoteutil.MaxResponseBodyBytes)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create grpc client for %s: %w", options.Address, err)
+	}
+	return &Client{
+		address: options.Address,
+		conn:    conn,
+		client:  admissionpb.NewAdmissionClient(conn),
+		timeout: options.CallTimeout,
+	}, nil
+}
+
+func (c *Client) Validate(ctx context.Context, payload *types.Payload) (*admissionpb.ValidateResponse, error) {
+	request, err := admissionpb.NewAdmissionRequest(payload)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	response, err := c.client.Validate(ctx, request)
+	if err != nil {
+		return nil, c.callError("Validate", err)
+	}
+	return response, nil
+}
+
+func (c *Client) Mutate(ctx context.Context, payload *types.Payload) (*admissionpb.MutateResponse, error) {
+	request, err := admissionpb.NewAdmissionRequest(payload)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	response, err := c.client.Mutate(ctx, request)
+	if err != nil {
+		return nil, c.callError("Mutate", err)
+	}
+	return response, nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// callError keeps deadline errors recognizable as timeouts.
+func (c *Client) callError(method string, err error) error {
+	if status.Code(err) == codes.DeadlineExceeded {
+		return fmt.Errorf("grpc %s call to %s: %w", method, c.address, context.DeadlineExceeded)
+	}
+	return fmt.Errorf("grpc %s call to %s failed: %w", method, c.address, err)
+}
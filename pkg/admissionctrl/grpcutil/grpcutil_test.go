@@ -0,0 +1,99 @@
+package grpcutil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/grpc/admissionpb"
+	"github.com/mxab/nacp/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type admissionServer struct {
+	admissionpb.UnimplementedAdmissionServer
+	delay time.Duration
+}
+
+func (s *admissionServer) Validate(ctx context.Context, _ *admissionpb.AdmissionRequest) (*admissionpb.ValidateResponse, error) {
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &admissionpb.ValidateResponse{Warnings: []*admissionpb.Violation{{Message: "ok"}}}, nil
+}
+
+func TestNewClient(t *testing.T) {
+	_, err := NewClient(Options{})
+	assert.ErrorContains(t, err, "grpc address is required")
+}
+
+func TestCallTimeout(t *testing.T) {
+	address := testutil.StartAdmissionServer(t, &admissionServer{delay: time.Second}, nil)
+	client, err := NewClient(Options{Address: address, CallTimeout: 50 * time.Millisecond})
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Validate(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestTLS(t *testing.T) {
+	cert, pool := selfSignedCert(t)
+	address := testutil.StartAdmissionServer(t, &admissionServer{}, &tls.Config{Certificates: []tls.Certificate{cert}})
+
+	client, err := NewClient(Options{Address: address, TLS: &tls.Config{RootCAs: pool}})
+	require.NoError(t, err)
+	defer client.Close()
+	response, err := client.Validate(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", response.GetWarnings()[0].GetMessage())
+
+	untrusting, err := NewClient(Options{Address: address, TLS: &tls.Config{RootCAs: x509.NewCertPool()}})
+	require.NoError(t, err)
+	defer untrusting.Close()
+	_, err = untrusting.Validate(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	assert.ErrorContains(t, err, "certificate")
+
+	plaintext, err := NewClient(Options{Address: address})
+	require.NoError(t, err)
+	defer plaintext.Close()
+	_, err = plaintext.Validate(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	assert.Error(t, err)
+}
+
+func selfSignedCert(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "admission"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	parsed, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(parsed)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
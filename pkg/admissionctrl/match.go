@@ -0,0 +1,69 @@
+package admissionctrl
+
+import (
+	"path"
+	"regexp"
+	"slices"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+)
+
+// Match selects the jobs a controller runs for. Empty fields match
+// everything; a job has to satisfy every non-empty field.
+type Match struct {
+	// Namespaces are glob patterns as understood by path.Match.
+	Namespaces []string
+	JobTypes   []string
+	Regions    []string
+	NodePools  []string
+	JobID      *regexp.Regexp
+	// Meta lists job meta keys and the values they must have.
+	Meta map[string]string
+}
+
+// Matches reports whether the payload's job is selected. Unset job fields are
+// compared with the defaults Nomad would apply.
+func (m *Match) Matches(payload *types.Payload) bool {
+	if m == nil {
+		return true
+	}
+	job := payload.Job
+	if len(m.Namespaces) > 0 && !matchesGlob(m.Namespaces, valueOr(job.Namespace, api.DefaultNamespace)) {
+		return false
+	}
+	if len(m.JobTypes) > 0 && !slices.Contains(m.JobTypes, valueOr(job.Type, api.JobTypeService)) {
+		return false
+	}
+	if len(m.Regions) > 0 && !slices.Contains(m.Regions, valueOr(job.Region, api.GlobalRegion)) {
+		return false
+	}
+	if len(m.NodePools) > 0 && !slices.Contains(m.NodePools, valueOr(job.NodePool, api.NodePoolDefault)) {
+		return false
+	}
+	if m.JobID != nil && !m.JobID.MatchString(jobID(job)) {
+		return false
+	}
+	for key, value := range m.Meta {
+		if actual, ok := job.Meta[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesGlob(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func valueOr(value *string, fallback string) string {
+	if value == nil || *value == "" {
+		return fallback
+	}
+	return *value
+}
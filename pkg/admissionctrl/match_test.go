@@ -0,0 +1,58 @@
+package admissionctrl
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch_Matches(t *testing.T) {
+	job := func(mutate func(*api.Job)) *api.Job {
+		id := "web-frontend"
+		j := &api.Job{ID: &id, Meta: map[string]string{"team": "checkout"}}
+		if mutate != nil {
+			mutate(j)
+		}
+		return j
+	}
+	tests := []struct {
+		name  string
+		match *Match
+		job   *api.Job
+		want  bool
+	}{
+		{name: "nil match", match: nil, job: job(nil), want: true},
+		{name: "empty match", match: &Match{}, job: job(nil), want: true},
+		{name: "namespace glob", match: &Match{Namespaces: []string{"team-*"}}, job: job(func(j *api.Job) { j.Namespace = pointer("team-a") }), want: true},
+		{name: "namespace glob mismatch", match: &Match{Namespaces: []string{"team-*"}}, job: job(func(j *api.Job) { j.Namespace = pointer("infra") }), want: false},
+		{name: "unset namespace is default", match: &Match{Namespaces: []string{"default"}}, job: job(nil), want: true},
+		{name: "unset type is service", match: &Match{JobTypes: []string{"service"}}, job: job(nil), want: true},
+		{name: "job type mismatch", match: &Match{JobTypes: []string{"batch", "sysbatch"}}, job: job(func(j *api.Job) { j.Type = pointer("system") }), want: false},
+		{name: "unset region is global", match: &Match{Regions: []string{"global"}}, job: job(nil), want: true},
+		{name: "node pool", match: &Match{NodePools: []string{"gpu"}}, job: job(func(j *api.Job) { j.NodePool = pointer("gpu") }), want: true},
+		{name: "unset node pool is default", match: &Match{NodePools: []string{"gpu"}}, job: job(nil), want: false},
+		{name: "job id regex", match: &Match{JobID: regexp.MustCompile(`^web-`)}, job: job(nil), want: true},
+		{name: "job id regex mismatch", match: &Match{JobID: regexp.MustCompile(`^api-`)}, job: job(nil), want: false},
+		{name: "meta", match: &Match{Meta: map[string]string{"team": "checkout"}}, job: job(nil), want: true},
+		{name: "meta value mismatch", match: &Match{Meta: map[string]string{"team": "search"}}, job: job(nil), want: false},
+		{name: "meta key missing", match: &Match{Meta: map[string]string{"tier": "1"}}, job: job(nil), want: false},
+		{
+			name:  "all fields must match",
+			match: &Match{JobTypes: []string{"service"}, Meta: map[string]string{"team": "search"}},
+			job:   job(nil),
+			want:  false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.match.Matches(&types.Payload{Job: tc.job}))
+		})
+	}
+}
+
+func pointer[T any](v T) *T {
+	return &v
+}
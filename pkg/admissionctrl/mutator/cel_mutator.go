@@ -0,0 +1,71 @@
+package mutator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/cel-go/cel"
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl"
+	"github.com/mxab/nacp/pkg/admissionctrl/celutil"
+	"github.com/mxab/nacp/pkg/admissionctrl/mutator/jsonpatcher"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/config"
+)
+
+// CelMutator sets job fields to the results of CEL expressions. All
+// expressions see the job as it was passed to the mutator.
+type CelMutator struct {
+	name      string
+	logger    *slog.Logger
+	mutations []celMutation
+}
+
+type celMutation struct {
+	path    string
+	program cel.Program
+}
+
+var _ admissionctrl.JobMutator = (*CelMutator)(nil)
+
+func NewCelMutator(name string, mutations []config.CelMutation, logger *slog.Logger) (*CelMutator, error) {
+	compiled := make([]celMutation, 0, len(mutations))
+	for _, mutation := range mutations {
+		program, err := celutil.Compile(mutation.Expression)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, celMutation{path: mutation.Path, program: program})
+	}
+	return &CelMutator{name: name, logger: logger, mutations: compiled}, nil
+}
+
+func (m *CelMutator) Mutate(ctx context.Context, payload *types.Payload) (*api.Job, bool, []error, error) {
+	input, err := celutil.Input(payload)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	job, mutated := payload.Job, false
+	for _, mutation := range m.mutations {
+		value, err := celutil.Eval(ctx, mutation.program, input)
+		if err != nil {
+			return nil, false, nil, fmt.Errorf("failed to evaluate expression for %s: %w", mutation.path, err)
+		}
+		if value == nil {
+			continue
+		}
+		var changed bool
+		job, changed, err = jsonpatcher.SetJobValue(job, mutation.path, value)
+		if err != nil {
+			return nil, false, nil, fmt.Errorf("failed to set %s: %w", mutation.path, err)
+		}
+		mutated = mutated || changed
+	}
+	m.logger.DebugContext(ctx, "CEL mutation", "rule", m.name, "mutated", mutated)
+	return job, mutated, nil, nil
+}
+
+func (m *CelMutator) Name() string {
+	return m.name
+}
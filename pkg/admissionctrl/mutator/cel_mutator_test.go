@@ -0,0 +1,84 @@
+package mutator
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/config"
+	"github.com/mxab/nacp/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCelMutator(t *testing.T) {
+	job := testutil.BaseJob()
+	job.TaskGroups = []*api.TaskGroup{{Name: config.Ptr("web"), Count: config.Ptr(20)}}
+
+	tests := []struct {
+		name        string
+		mutations   []config.CelMutation
+		wantMutated bool
+		check       func(*testing.T, *api.Job)
+		wantErr     string
+	}{
+		{
+			name: "set fields",
+			mutations: []config.CelMutation{
+				{Path: "/Meta/owner", Expression: `context.accessorID`},
+				{Path: "/TaskGroups[name=web]/Count", Expression: `job.TaskGroups[0].Count > 10 ? 10 : job.TaskGroups[0].Count`},
+				{Path: "/Datacenters", Expression: `["dc1", "dc2"]`},
+			},
+			wantMutated: true,
+			check: func(t *testing.T, job *api.Job) {
+				assert.Equal(t, map[string]string{"owner": "1234"}, job.Meta)
+				assert.Equal(t, 10, *job.TaskGroups[0].Count)
+				assert.Equal(t, []string{"dc1", "dc2"}, job.Datacenters)
+			},
+		},
+		{
+			name:      "null leaves the field alone",
+			mutations: []config.CelMutation{{Path: "/Meta/owner", Expression: `job.Region`}},
+			check: func(t *testing.T, job *api.Job) {
+				assert.Nil(t, job.Meta)
+			},
+		},
+		{
+			name:      "same value",
+			mutations: []config.CelMutation{{Path: "/ID", Expression: `job.ID`}},
+			check: func(t *testing.T, job *api.Job) {
+				assert.Equal(t, "test-job", *job.ID)
+			},
+		},
+		{
+			name:      "evaluation error",
+			mutations: []config.CelMutation{{Path: "/Meta/owner", Expression: `job.Meta.owner`}},
+			wantErr:   "failed to evaluate expression for /Meta/owner",
+		},
+		{
+			name:      "value of the wrong type",
+			mutations: []config.CelMutation{{Path: "/TaskGroups/0/Count", Expression: `"many"`}},
+			wantErr:   "failed to set /TaskGroups/0/Count",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mutator, err := NewCelMutator("cel", tc.mutations, slog.New(slog.DiscardHandler))
+			require.NoError(t, err)
+
+			result, mutated, warnings, err := mutator.Mutate(t.Context(), &types.Payload{
+				Job:     job,
+				Context: &config.RequestContext{AccessorID: "1234"},
+			})
+			assert.Empty(t, warnings)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantMutated, mutated)
+			tc.check(t, result)
+		})
+	}
+}
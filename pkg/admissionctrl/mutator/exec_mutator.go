@@ -0,0 +1,75 @@
+package mutator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl"
+	"github.com/mxab/nacp/pkg/admissionctrl/executil"
+	"github.com/mxab/nacp/pkg/admissionctrl/mutator/jsonpatcher"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+)
+
+// ExecMutator mutates jobs with a command. The command gets the payload
+// webhooks get on stdin and answers like a json_patch_webhook on stdout, or
+// rejects the job by exiting with a reject exit code.
+type ExecMutator struct {
+	name    string
+	logger  *slog.Logger
+	command *executil.Command
+}
+
+var _ admissionctrl.JobMutator = (*ExecMutator)(nil)
+
+func NewExecMutator(name string, options executil.Options, logger *slog.Logger) (*ExecMutator, error) {
+	command, err := executil.New(options)
+	if err != nil {
+		return nil, err
+	}
+	return &ExecMutator{name: name, logger: logger, command: command}, nil
+}
+
+func (m *ExecMutator) Mutate(ctx context.Context, payload *types.Payload) (*api.Job, bool, []error, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	output, err := m.command.Call(ctx, data)
+	var rejection *executil.Rejection
+	if errors.As(err, &rejection) {
+		return nil, false, nil, multierror.Append(nil, &types.Violation{Message: rejection.Message})
+	}
+	if err != nil {
+		return nil, false, nil, err
+	}
+	result := &jsonPatchWebhookResponse{}
+	if err := json.Unmarshal(output, result); err != nil {
+		return nil, false, nil, fmt.Errorf("failed to decode exec command response: %w", err)
+	}
+
+	warnings, policyErr := types.DecodeResult(result.Warnings, result.Errors)
+	if policyErr != nil {
+		m.logger.Debug("mutation errors", "errors", result.Errors, "rule", m.name)
+		return nil, false, warnings, policyErr
+	}
+
+	job, mutated, err := jsonpatcher.ApplyPatches(payload.Job, result.Patch, result.MergePatch)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	return job, mutated, warnings, nil
+}
+
+func (m *ExecMutator) Name() string {
+	return m.name
+}
+
+// Close stops the persistent process, if there is one.
+func (m *ExecMutator) Close() error {
+	return m.command.Close()
+}
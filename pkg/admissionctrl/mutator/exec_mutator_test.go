@@ -0,0 +1,84 @@
+package mutator
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl/executil"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecMutator(t *testing.T) {
+	tt := []struct {
+		name        string
+		script      string
+		persistent  bool
+		wantJob     *api.Job
+		wantMutated bool
+		wantWarns   []error
+		wantErr     string
+	}{
+		{
+			name:    "no changes",
+			script:  `cat >/dev/null; echo '{}'`,
+			wantJob: testutil.BaseJob(),
+		},
+		{
+			name:        "patch and merge patch",
+			script:      `cat >/dev/null; echo '{"merge_patch": {"Meta": {"owner": "team-a"}}, "patch": [{"op": "add", "path": "/Meta/team", "value": "a"}]}'`,
+			wantJob:     &api.Job{ID: testutil.BaseJob().ID, Meta: map[string]string{"owner": "team-a", "team": "a"}},
+			wantMutated: true,
+		},
+		{
+			name:      "warnings",
+			script:    `cat >/dev/null; echo '{"warnings": ["careful"]}'`,
+			wantJob:   testutil.BaseJob(),
+			wantWarns: []error{&types.Violation{Message: "careful"}},
+		},
+		{
+			name:    "errors",
+			script:  `cat >/dev/null; echo '{"errors": ["denied"]}'`,
+			wantErr: "denied",
+		},
+		{
+			name:    "reject exit code",
+			script:  `cat >/dev/null; echo denied >&2; exit 1`,
+			wantErr: "denied",
+		},
+		{
+			name:        "persistent",
+			script:      `while IFS= read -r line; do echo '{"merge_patch": {"Meta": {"owner": "team-a"}}}'; done`,
+			persistent:  true,
+			wantJob:     &api.Job{ID: testutil.BaseJob().ID, Meta: map[string]string{"owner": "team-a"}},
+			wantMutated: true,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			mutator, err := NewExecMutator(tc.name, executil.Options{
+				Command:         "sh",
+				Args:            []string{"-c", tc.script},
+				RejectExitCodes: []int{1},
+				Persistent:      tc.persistent,
+			}, slog.New(slog.DiscardHandler))
+			require.NoError(t, err)
+			t.Cleanup(func() { mutator.Close() })
+			assert.Equal(t, tc.name, mutator.Name())
+
+			job, mutated, warnings, err := mutator.Mutate(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.wantJob, job)
+			assert.Equal(t, tc.wantMutated, mutated)
+			assert.Equal(t, tc.wantWarns, warnings)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package mutator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl"
+	"github.com/mxab/nacp/pkg/admissionctrl/grpcutil"
+	"github.com/mxab/nacp/pkg/admissionctrl/mutator/jsonpatcher"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/grpc/admissionpb"
+)
+
+// GrpcMutator mutates jobs with the Mutate call of an Admission gRPC server,
+// the gRPC counterpart of the JsonPatchWebhookMutator.
+type GrpcMutator struct {
+	name   string
+	logger *slog.Logger
+	client *grpcutil.Client
+}
+
+var _ admissionctrl.JobMutator = (*GrpcMutator)(nil)
+
+func NewGrpcMutator(name string, options grpcutil.Options, logger *slog.Logger) (*GrpcMutator, error) {
+	client, err := grpcutil.NewClient(options)
+	if err != nil {
+		return nil, err
+	}
+	return &GrpcMutator{name: name, logger: logger, client: client}, nil
+}
+
+func (m *GrpcMutator) Mutate(ctx context.Context, payload *types.Payload) (*api.Job, bool, []error, error) {
+	response, err := m.client.Mutate(ctx, payload)
+	if err != nil {
+		return nil, false, nil, err
+	}
+
+	warnings, policyErr := types.DecodeResult(admissionpb.ToViolations(response.GetWarnings()), admissionpb.ToViolations(response.GetErrors()))
+	if policyErr != nil {
+		m.logger.Debug("mutation errors", "errors", response.GetErrors(), "rule", m.name)
+		return nil, false, warnings, policyErr
+	}
+
+	var patch []interface{}
+	if len(response.GetPatch()) > 0 {
+		if err := json.Unmarshal(response.GetPatch(), &patch); err != nil {
+			return nil, false, nil, fmt.Errorf("failed to decode patch: %w", err)
+		}
+	}
+	var mergePatch interface{}
+	if len(response.GetMergePatch()) > 0 {
+		if err := json.Unmarshal(response.GetMergePatch(), &mergePatch); err != nil {
+			return nil, false, nil, fmt.Errorf("failed to decode merge patch: %w", err)
+		}
+	}
+	job, mutated, err := jsonpatcher.ApplyPatches(payload.Job, patch, mergePatch)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	return job, mutated, warnings, nil
+}
+
+func (m *GrpcMutator) Name() string {
+	return m.name
+}
+
+// Close closes the connection to the server.
+func (m *GrpcMutator) Close() error {
+	return m.client.Close()
+}
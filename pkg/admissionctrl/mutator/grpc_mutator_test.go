@@ -0,0 +1,107 @@
+package mutator
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl/grpcutil"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/grpc/admissionpb"
+	"github.com/mxab/nacp/pkg/grpc/server"
+	"github.com/mxab/nacp/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticMutateServer struct {
+	admissionpb.UnimplementedAdmissionServer
+	response *admissionpb.MutateResponse
+}
+
+func (s *staticMutateServer) Mutate(context.Context, *admissionpb.AdmissionRequest) (*admissionpb.MutateResponse, error) {
+	return s.response, nil
+}
+
+func TestGrpcMutator(t *testing.T) {
+	tt := []struct {
+		name        string
+		response    *admissionpb.MutateResponse
+		wantJob     *api.Job
+		wantMutated bool
+		wantWarns   []error
+		wantErr     string
+	}{
+		{
+			name:     "no changes",
+			response: &admissionpb.MutateResponse{},
+			wantJob:  testutil.BaseJob(),
+		},
+		{
+			name: "merge patch and patch",
+			response: &admissionpb.MutateResponse{
+				MergePatch: []byte(`{"Meta": {"owner": "team-a"}}`),
+				Patch:      []byte(`[{"op": "add", "path": "/Meta/team", "value": "a"}]`),
+				Warnings:   []*admissionpb.Violation{{Message: "careful"}},
+			},
+			wantJob:     &api.Job{ID: testutil.BaseJob().ID, Meta: map[string]string{"owner": "team-a", "team": "a"}},
+			wantMutated: true,
+			wantWarns:   []error{&types.Violation{Message: "careful"}},
+		},
+		{
+			name:     "errors",
+			response: &admissionpb.MutateResponse{Errors: []*admissionpb.Violation{{Message: "denied"}}},
+			wantErr:  "denied",
+		},
+		{
+			name:     "invalid patch",
+			response: &admissionpb.MutateResponse{Patch: []byte(`{"op": "add"}`)},
+			wantErr:  "failed to decode patch",
+		},
+		{
+			name:     "invalid merge patch",
+			response: &admissionpb.MutateResponse{MergePatch: []byte(`["a"]`)},
+			wantErr:  "merge patch must be an object",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			address := testutil.StartAdmissionServer(t, &staticMutateServer{response: tc.response}, nil)
+			mutator, err := NewGrpcMutator(tc.name, grpcutil.Options{Address: address}, slog.New(slog.DiscardHandler))
+			require.NoError(t, err)
+			assert.Equal(t, tc.name, mutator.Name())
+
+			job, mutated, warnings, err := mutator.Mutate(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.wantJob, job)
+			assert.Equal(t, tc.wantMutated, mutated)
+			assert.Equal(t, tc.wantWarns, warnings)
+		})
+	}
+}
+
+func TestGrpcMutatorWithSDKServer(t *testing.T) {
+	address := testutil.StartAdmissionServer(t, &server.Service{
+		Mutator: func(ctx context.Context, payload *types.Payload) (*server.MutateResult, error) {
+			payload.Job.Datacenters = []string{"dc2"}
+			return &server.MutateResult{Job: payload.Job}, nil
+		},
+	}, nil)
+	mutator, err := NewGrpcMutator("sdk", grpcutil.Options{Address: address}, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+
+	input := testutil.ReadJob(t, "job.json")
+	job, mutated, _, err := mutator.Mutate(t.Context(), &types.Payload{Job: input})
+	require.NoError(t, err)
+	assert.True(t, mutated)
+
+	want := testutil.ReadJob(t, "job.json")
+	want.Datacenters = []string{"dc2"}
+	assert.Equal(t, want, job, "Only the changed field is patched")
+}
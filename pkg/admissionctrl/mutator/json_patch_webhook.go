@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"log/slog"
 	"net/http"
 	"net/url"
@@ -13,7 +12,6 @@ import (
 	"github.com/mxab/nacp/pkg/admissionctrl/remoteutil"
 	"github.com/mxab/nacp/pkg/admissionctrl/types"
 
-	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/api"
 )
 
@@ -21,24 +19,28 @@ type JsonPatchWebhookMutator struct {
 	name     string
 	logger   *slog.Logger
 	endpoint *url.URL
-	method   string
+	// signingSecret signs requests when set.
+	signingSecret []byte
+	method        string
 }
 type jsonPatchWebhookResponse struct {
-	Patch    []interface{} `json:"patch"`
-	Warnings []string      `json:"warnings"`
-	Errors   []string      `json:"errors"`
+	Patch      []interface{}       `json:"patch"`
+	MergePatch interface{}         `json:"merge_patch"`
+	Warnings   types.ViolationList `json:"warnings"`
+	Errors     types.ViolationList `json:"errors"`
 }
 
-func NewJsonPatchWebhookMutator(name string, endpoint string, method string, logger *slog.Logger) (*JsonPatchWebhookMutator, error) {
+func NewJsonPatchWebhookMutator(name string, endpoint string, method string, signingSecret []byte, logger *slog.Logger) (*JsonPatchWebhookMutator, error) {
 	u, err := remoteutil.ParseEndpoint(endpoint)
 	if err != nil {
 		return nil, err
 	}
 	return &JsonPatchWebhookMutator{
-		name:     name,
-		logger:   logger,
-		endpoint: u,
-		method:   method,
+		name:          name,
+		logger:        logger,
+		endpoint:      u,
+		method:        method,
+		signingSecret: signingSecret,
 	}, nil
 }
 func (j *JsonPatchWebhookMutator) Mutate(ctx context.Context, payload *types.Payload) (*api.Job, bool, []error, error) {
@@ -53,6 +55,7 @@ func (j *JsonPatchWebhookMutator) Mutate(ctx context.Context, payload *types.Pay
 	}
 
 	remoteutil.ApplyContextHeaders(req, payload)
+	remoteutil.SignRequest(req, jobJson, j.signingSecret)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
@@ -68,23 +71,15 @@ func (j *JsonPatchWebhookMutator) Mutate(ctx context.Context, payload *types.Pay
 		return nil, false, nil, err
 	}
 
-	var warnings []error
 	if len(patchResponse.Warnings) > 0 {
 		j.logger.Debug("Got warnings from rule", "rule", j.name, "warnings", patchResponse.Warnings, "job", payload.Job.ID)
-		for _, warning := range patchResponse.Warnings {
-			warnings = append(warnings, errors.New(warning))
-		}
 	}
-
-	if len(patchResponse.Errors) > 0 {
-		var policyErr error
-		for _, message := range patchResponse.Errors {
-			policyErr = multierror.Append(policyErr, errors.New(message))
-		}
+	warnings, policyErr := types.DecodeResult(patchResponse.Warnings, patchResponse.Errors)
+	if policyErr != nil {
 		return nil, false, warnings, policyErr
 	}
 
-	patchedJob, mutated, err := jsonpatcher.PatchJob(payload.Job, patchResponse.Patch)
+	patchedJob, mutated, err := jsonpatcher.ApplyPatches(payload.Job, patchResponse.Patch, patchResponse.MergePatch)
 	if err != nil {
 		return nil, false, nil, err
 	}
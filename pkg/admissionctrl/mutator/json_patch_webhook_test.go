@@ -66,6 +66,36 @@ func TestJsonPatchMutator(t *testing.T) {
 			wantJob:     &api.Job{ID: testutil.BaseJob().ID, Meta: map[string]string{"foo": "bar"}},
 			wantMutated: true,
 		},
+		{
+			name:         "merge patch",
+			endpointPath: "/mutate",
+			method:       "POST",
+
+			response: []byte(`{
+				"merge_patch": {"Meta": {"foo": "bar", "owner": "team-a"}},
+				"patch": [
+					{"op": "replace", "path": "/Meta/owner", "value": "team-b"}
+				]
+			}`),
+
+			job: testutil.BaseJob(),
+
+			wantJob:     &api.Job{ID: testutil.BaseJob().ID, Meta: map[string]string{"foo": "bar", "owner": "team-b"}},
+			wantMutated: true,
+		},
+		{
+			name:         "faulty merge patch",
+			endpointPath: "/mutate",
+			method:       "POST",
+
+			response: []byte(`{"merge_patch": ["not", "an", "object"]}`),
+
+			job: testutil.BaseJob(),
+
+			wantErr:     fmt.Errorf("merge patch must be an object"),
+			wantJob:     nil,
+			wantMutated: false,
+		},
 		{
 			name:         "faulty patch",
 			endpointPath: "/mutate",
@@ -119,7 +149,7 @@ func TestJsonPatchMutator(t *testing.T) {
 			job: testutil.BaseJob(),
 
 			wantErr:     nil,
-			wantWarns:   []error{fmt.Errorf("Warning 1"), fmt.Errorf("Warning 2")},
+			wantWarns:   []error{&types.Violation{Message: "Warning 1"}, &types.Violation{Message: "Warning 2"}},
 			wantJob:     testutil.BaseJob(),
 			wantMutated: false,
 		},
@@ -187,7 +217,7 @@ func TestJsonPatchMutator(t *testing.T) {
 			}))
 			defer webhookServer.Close()
 
-			mutator, err := NewJsonPatchWebhookMutator(tc.name, webhookServer.URL+tc.endpointPath, tc.method, slog.New(slog.DiscardHandler))
+			mutator, err := NewJsonPatchWebhookMutator(tc.name, webhookServer.URL+tc.endpointPath, tc.method, nil, slog.New(slog.DiscardHandler))
 			require.NoError(t, err)
 
 			payload := &types.Payload{Job: tc.job, Context: tc.context}
@@ -0,0 +1,96 @@
+package jsonpatcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Operation is a single RFC 6902 JSON Patch operation.
+type Operation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON leaves out the value of remove operations only; add and
+// replace operations must carry one even if it is null.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	if o.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+	type plain Operation
+	return json.Marshal(plain(o))
+}
+
+// CreatePatch returns the JSON Patch operations turning the original document
+// into the modified one. Objects are diffed key by key; arrays of differing
+// length are replaced as a whole.
+func CreatePatch(original, modified []byte) ([]Operation, error) {
+	var a, b interface{}
+	if err := json.Unmarshal(original, &a); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal original document: %w", err)
+	}
+	if err := json.Unmarshal(modified, &b); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal modified document: %w", err)
+	}
+	ops := []Operation{}
+	diff("", a, b, &ops)
+	return ops, nil
+}
+
+func diff(path string, a, b interface{}, ops *[]Operation) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			*ops = append(*ops, Operation{Op: "replace", Path: path, Value: b})
+			return
+		}
+		for _, key := range sortedKeys(av) {
+			if _, ok := bv[key]; !ok {
+				*ops = append(*ops, Operation{Op: "remove", Path: path + "/" + escapePointer(key)})
+				continue
+			}
+			diff(path+"/"+escapePointer(key), av[key], bv[key], ops)
+		}
+		for _, key := range sortedKeys(bv) {
+			if _, ok := av[key]; !ok {
+				*ops = append(*ops, Operation{Op: "add", Path: path + "/" + escapePointer(key), Value: bv[key]})
+			}
+		}
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			*ops = append(*ops, Operation{Op: "replace", Path: path, Value: b})
+			return
+		}
+		for i := range av {
+			diff(path+"/"+strconv.Itoa(i), av[i], bv[i], ops)
+		}
+	default:
+		if !reflect.DeepEqual(a, b) {
+			*ops = append(*ops, Operation{Op: "replace", Path: path, Value: b})
+		}
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// escapePointer escapes a key for use as a JSON Pointer reference token.
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,103 @@
+package jsonpatcher
+
+import (
+	"encoding/json"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		modified string
+		want     []Operation
+	}{
+		{
+			name:     "identical",
+			original: `{"ID":"job","Meta":{"a":"b"}}`,
+			modified: `{"ID":"job","Meta":{"a":"b"}}`,
+			want:     []Operation{},
+		},
+		{
+			name:     "replace null with object",
+			original: `{"ID":"job","Meta":null}`,
+			modified: `{"ID":"job","Meta":{"a":"b"}}`,
+			want: []Operation{
+				{Op: "replace", Path: "/Meta", Value: map[string]interface{}{"a": "b"}},
+			},
+		},
+		{
+			name:     "replace object with null",
+			original: `{"ID":"job","Meta":{"a":"b"}}`,
+			modified: `{"ID":"job","Meta":null}`,
+			want: []Operation{
+				{Op: "replace", Path: "/Meta", Value: nil},
+			},
+		},
+		{
+			name:     "add and remove keys",
+			original: `{"Meta":{"a":"b","x/y":"z"}}`,
+			modified: `{"Meta":{"a":"c","new~key":"v"}}`,
+			want: []Operation{
+				{Op: "replace", Path: "/Meta/a", Value: "c"},
+				{Op: "remove", Path: "/Meta/x~1y"},
+				{Op: "add", Path: "/Meta/new~0key", Value: "v"},
+			},
+		},
+		{
+			name:     "nested array element",
+			original: `{"TaskGroups":[{"Count":1},{"Count":2}]}`,
+			modified: `{"TaskGroups":[{"Count":1},{"Count":3}]}`,
+			want: []Operation{
+				{Op: "replace", Path: "/TaskGroups/1/Count", Value: float64(3)},
+			},
+		},
+		{
+			name:     "array length changed",
+			original: `{"Datacenters":["dc1"]}`,
+			modified: `{"Datacenters":["dc1","dc2"]}`,
+			want: []Operation{
+				{Op: "replace", Path: "/Datacenters", Value: []interface{}{"dc1", "dc2"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreatePatch([]byte(tt.original), []byte(tt.modified))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			// applying the patch must yield the modified document
+			data, err := json.Marshal(got)
+			require.NoError(t, err)
+			patch, err := jsonpatch.DecodePatch(data)
+			require.NoError(t, err)
+			patched, err := patch.Apply([]byte(tt.original))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.modified, string(patched))
+		})
+	}
+}
+
+func TestOperationMarshalJSON(t *testing.T) {
+	data, err := json.Marshal([]Operation{
+		{Op: "replace", Path: "/Meta", Value: nil},
+		{Op: "add", Path: "/Meta/a", Value: "b"},
+		{Op: "remove", Path: "/Meta/c"},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op": "replace", "path": "/Meta", "value": null},
+		{"op": "add", "path": "/Meta/a", "value": "b"},
+		{"op": "remove", "path": "/Meta/c"}
+	]`, string(data))
+}
+
+func TestCreatePatchInvalidJSON(t *testing.T) {
+	_, err := CreatePatch([]byte(`{`), []byte(`{}`))
+	assert.Error(t, err)
+}
@@ -8,6 +8,10 @@ import (
 	"github.com/hashicorp/nomad/api"
 )
 
+// PatchJob applies JSON Patch operations to the job. Besides plain JSON
+// pointers, paths may address task groups, tasks and other arrays of objects
+// by name, e.g. /TaskGroups[name=web]/Tasks[name=app]/Env/FOO; see
+// ResolvePath.
 func PatchJob(job *api.Job, patchOps []interface{}) (*api.Job, bool, error) {
 
 	jobJson, err := json.Marshal(job)
@@ -23,7 +27,7 @@ func PatchJob(job *api.Job, patchOps []interface{}) (*api.Job, bool, error) {
 	if err != nil {
 		return nil, false, err
 	}
-	patchedJobJson, err := patch.Apply(jobJson)
+	patchedJobJson, err := applyPatch(jobJson, patch)
 
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to apply patch: %w", err)
@@ -36,3 +40,22 @@ func PatchJob(job *api.Job, patchOps []interface{}) (*api.Job, bool, error) {
 	mutated := len(patch) > 0
 	return &patchedJob, mutated, nil
 }
+
+// applyPatch applies the patch to doc. Operations using name selectors are
+// applied one at a time, so each is resolved against the document left by
+// the operations before it.
+func applyPatch(doc []byte, patch jsonpatch.Patch) ([]byte, error) {
+	if !usesSelectors(patch) {
+		return patch.Apply(doc)
+	}
+	for _, operation := range patch {
+		if err := resolveOperation(doc, operation); err != nil {
+			return nil, err
+		}
+		var err error
+		if doc, err = (jsonpatch.Patch{operation}).Apply(doc); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
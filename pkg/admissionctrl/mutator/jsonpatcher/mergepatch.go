@@ -0,0 +1,58 @@
+package jsonpatcher
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/hashicorp/nomad/api"
+)
+
+// MergePatchJob applies an RFC 7386 JSON Merge Patch to the job. The merge
+// patch has to be an object; null values remove fields and arrays replace
+// the job's arrays as a whole.
+func MergePatchJob(job *api.Job, mergePatch interface{}) (*api.Job, bool, error) {
+	if _, ok := mergePatch.(map[string]interface{}); !ok {
+		return nil, false, fmt.Errorf("merge patch must be an object, got %T", mergePatch)
+	}
+
+	jobJson, err := json.Marshal(job)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal job: %w", err)
+	}
+	mergePatchData, err := json.Marshal(mergePatch)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal merge patch: %w", err)
+	}
+	patchedJobJson, err := jsonpatch.MergePatch(jobJson, mergePatchData)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to apply merge patch: %w", err)
+	}
+	var patchedJob api.Job
+	if err := json.Unmarshal(patchedJobJson, &patchedJob); err != nil {
+		return nil, false, err
+	}
+	return &patchedJob, !jsonpatch.Equal(jobJson, patchedJobJson), nil
+}
+
+// ApplyPatches applies a merge patch and then JSON Patch operations to the
+// job, either of which may be nil. The merge patch comes first, so it can set
+// coarse defaults that the operations then refine.
+func ApplyPatches(job *api.Job, patchOps []interface{}, mergePatch interface{}) (*api.Job, bool, error) {
+	mutated := false
+	if mergePatch != nil {
+		merged, mergeMutated, err := MergePatchJob(job, mergePatch)
+		if err != nil {
+			return nil, false, err
+		}
+		job, mutated = merged, mergeMutated
+	}
+	if len(patchOps) == 0 {
+		return job, mutated, nil
+	}
+	patched, patchMutated, err := PatchJob(job, patchOps)
+	if err != nil {
+		return nil, false, err
+	}
+	return patched, mutated || patchMutated, nil
+}
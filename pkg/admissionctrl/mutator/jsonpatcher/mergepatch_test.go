@@ -0,0 +1,86 @@
+package jsonpatcher
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePatchJob(t *testing.T) {
+	tests := []struct {
+		name        string
+		job         *api.Job
+		mergePatch  interface{}
+		want        *api.Job
+		wantMutated bool
+		wantErr     bool
+	}{
+		{
+			name:        "add meta",
+			job:         &api.Job{},
+			mergePatch:  map[string]interface{}{"Meta": map[string]interface{}{"owner": "team-a"}},
+			want:        &api.Job{Meta: map[string]string{"owner": "team-a"}},
+			wantMutated: true,
+		},
+		{
+			name:        "null removes a key",
+			job:         &api.Job{Meta: map[string]string{"owner": "team-a", "tmp": "x"}},
+			mergePatch:  map[string]interface{}{"Meta": map[string]interface{}{"tmp": nil}},
+			want:        &api.Job{Meta: map[string]string{"owner": "team-a"}},
+			wantMutated: true,
+		},
+		{
+			name:        "arrays are replaced",
+			job:         &api.Job{Datacenters: []string{"dc1", "dc2"}},
+			mergePatch:  map[string]interface{}{"Datacenters": []interface{}{"dc3"}},
+			want:        &api.Job{Datacenters: []string{"dc3"}},
+			wantMutated: true,
+		},
+		{
+			name:        "no change",
+			job:         &api.Job{Meta: map[string]string{"owner": "team-a"}},
+			mergePatch:  map[string]interface{}{"Meta": map[string]interface{}{"owner": "team-a"}},
+			want:        &api.Job{Meta: map[string]string{"owner": "team-a"}},
+			wantMutated: false,
+		},
+		{
+			name:       "not an object",
+			job:        &api.Job{},
+			mergePatch: []interface{}{"Meta"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, mutated, err := MergePatchJob(tt.job, tt.mergePatch)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantMutated, mutated)
+		})
+	}
+}
+
+func TestApplyPatches(t *testing.T) {
+	job := &api.Job{Meta: map[string]string{"owner": "team-a"}}
+	mergePatch := map[string]interface{}{"Meta": map[string]interface{}{"owner": "team-b", "tier": "gold"}}
+	patchOps := []interface{}{
+		map[string]interface{}{"op": "replace", "path": "/Meta/owner", "value": "team-c"},
+	}
+
+	got, mutated, err := ApplyPatches(job, patchOps, mergePatch)
+	require.NoError(t, err)
+	assert.True(t, mutated)
+	// the operations run after the merge patch and win on conflicts
+	assert.Equal(t, map[string]string{"owner": "team-c", "tier": "gold"}, got.Meta)
+
+	got, mutated, err = ApplyPatches(job, nil, nil)
+	require.NoError(t, err)
+	assert.False(t, mutated)
+	assert.Equal(t, job, got)
+}
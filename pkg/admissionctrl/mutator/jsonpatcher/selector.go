@@ -0,0 +1,180 @@
+package jsonpatcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+)
+
+// selectorPattern matches a path segment that addresses an array element by
+// one of its fields, e.g. TaskGroups[name=web].
+var selectorPattern = regexp.MustCompile(`^([^\[\]]+)\[([^=\[\]]+)=(.*)\]$`)
+
+// hasSelector reports whether a path may use the extended syntax.
+func hasSelector(path string) bool {
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasSuffix(segment, "]") && strings.Contains(segment, "[") {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolvePath turns an extended JSON pointer such as
+// /TaskGroups[name=web]/Tasks[name=app]/Env/FOO into a plain JSON pointer by
+// replacing each selector with the index of the element it selects in doc.
+// Fields are matched case-insensitively, so name selects Name. A selector
+// has to match exactly one element. Segments naming an existing key, like a
+// meta key "a[b]", are taken literally.
+func ResolvePath(doc interface{}, path string) (string, error) {
+	if !strings.HasPrefix(path, "/") {
+		return path, nil
+	}
+	segments := strings.Split(path, "/")[1:]
+	resolved := make([]string, 0, len(segments)+1)
+	current := doc
+	for _, segment := range segments {
+		if !strings.HasSuffix(segment, "]") || !strings.Contains(segment, "[") || hasKey(current, unescape(segment)) {
+			resolved = append(resolved, segment)
+			current = child(current, unescape(segment))
+			continue
+		}
+		match := selectorPattern.FindStringSubmatch(segment)
+		if match == nil {
+			return "", fmt.Errorf("path %q: invalid selector %q, expected key[field=value]", path, segment)
+		}
+		key, field, value := unescape(match[1]), match[2], unescape(match[3])
+
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("path %q: cannot select %q, parent is not an object", path, segment)
+		}
+		elements, ok := object[key].([]interface{})
+		if !ok {
+			return "", fmt.Errorf("path %q: %q is not an array", path, key)
+		}
+		index := -1
+		for i, element := range elements {
+			if !fieldEquals(element, field, value) {
+				continue
+			}
+			if index >= 0 {
+				return "", fmt.Errorf("path %q: more than one element of %q has %s=%q", path, key, field, value)
+			}
+			index = i
+		}
+		if index < 0 {
+			return "", fmt.Errorf("path %q: no element of %q has %s=%q", path, key, field, value)
+		}
+		resolved = append(resolved, match[1], strconv.Itoa(index))
+		current = elements[index]
+	}
+	return "/" + strings.Join(resolved, "/"), nil
+}
+
+// resolveOperation resolves the path and from members of a patch operation
+// against the JSON document it is about to be applied to.
+func resolveOperation(doc []byte, operation jsonpatch.Operation) error {
+	var parsed interface{}
+	if err := json.Unmarshal(doc, &parsed); err != nil {
+		return err
+	}
+	for _, member := range []string{"path", "from"} {
+		raw, ok := operation[member]
+		if !ok || raw == nil {
+			continue
+		}
+		var path string
+		if err := json.Unmarshal(*raw, &path); err != nil {
+			return fmt.Errorf("operation %s must be a string: %w", member, err)
+		}
+		if !hasSelector(path) {
+			continue
+		}
+		resolved, err := ResolvePath(parsed, path)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(resolved)
+		if err != nil {
+			return err
+		}
+		message := json.RawMessage(data)
+		operation[member] = &message
+	}
+	return nil
+}
+
+// usesSelectors reports whether any operation of the patch uses the
+// extended path syntax.
+func usesSelectors(patch jsonpatch.Patch) bool {
+	for _, operation := range patch {
+		for _, member := range []string{"path", "from"} {
+			raw, ok := operation[member]
+			if !ok || raw == nil {
+				continue
+			}
+			var path string
+			if json.Unmarshal(*raw, &path) == nil && hasSelector(path) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func child(node interface{}, key string) interface{} {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		return value[key]
+	case []interface{}:
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(value) {
+			return nil
+		}
+		return value[index]
+	default:
+		return nil
+	}
+}
+
+func hasKey(node interface{}, key string) bool {
+	object, ok := node.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, found := object[key]
+	return found
+}
+
+func fieldEquals(element interface{}, field string, want string) bool {
+	object, ok := element.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	value, found := object[field]
+	if !found {
+		for key, candidate := range object {
+			if strings.EqualFold(key, field) {
+				value, found = candidate, true
+				break
+			}
+		}
+	}
+	if !found || value == nil {
+		return false
+	}
+	if s, ok := value.(string); ok {
+		return s == want
+	}
+	return fmt.Sprint(value) == want
+}
+
+// unescape decodes a JSON pointer reference token.
+func unescape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
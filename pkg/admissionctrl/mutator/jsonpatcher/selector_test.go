@@ -0,0 +1,82 @@
+package jsonpatcher
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvePath(t *testing.T) {
+	var doc interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"Meta": {"a[b]": "x"},
+		"TaskGroups": [
+			{"Name": "api", "Count": 1, "Tasks": [{"Name": "app"}]},
+			{"Name": "web", "Count": 2, "Tasks": [{"Name": "sidecar"}, {"Name": "app"}]},
+			{"Name": "dup"},
+			{"Name": "dup"}
+		]
+	}`), &doc))
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr string
+	}{
+		{name: "plain pointer", path: "/Meta/a[b]", want: "/Meta/a[b]"},
+		{name: "group by name", path: "/TaskGroups[name=web]/Count", want: "/TaskGroups/1/Count"},
+		{name: "nested selectors", path: "/TaskGroups[name=web]/Tasks[name=app]/Env/FOO", want: "/TaskGroups/1/Tasks/1/Env/FOO"},
+		{name: "after plain index", path: "/TaskGroups/0/Tasks[Name=app]", want: "/TaskGroups/0/Tasks/0"},
+		{name: "non-string field", path: "/TaskGroups[count=2]", want: "/TaskGroups/1"},
+		{name: "no match", path: "/TaskGroups[name=db]/Count", wantErr: `no element of "TaskGroups" has name="db"`},
+		{name: "ambiguous", path: "/TaskGroups[name=dup]", wantErr: `more than one element of "TaskGroups" has name="dup"`},
+		{name: "not an array", path: "/Meta[name=web]", wantErr: `"Meta" is not an array`},
+		{name: "missing field", path: "/TaskGroups[name]", wantErr: "invalid selector"},
+		{name: "missing parent", path: "/Missing/TaskGroups[name=web]", wantErr: "parent is not an object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolvePath(doc, tt.path)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPatchJob_Selectors(t *testing.T) {
+	job := &api.Job{
+		TaskGroups: []*api.TaskGroup{
+			{Name: strPtr("api"), Tasks: []*api.Task{{Name: "app"}}},
+			{Name: strPtr("web"), Tasks: []*api.Task{{Name: "sidecar"}, {Name: "app", Env: map[string]string{}}}},
+		},
+	}
+
+	patched, mutated, err := PatchJob(job, []interface{}{
+		map[string]interface{}{"op": "add", "path": "/TaskGroups[name=web]/Tasks[name=app]/Env/FOO", "value": "bar"},
+		// resolved after the group above was moved to the front
+		map[string]interface{}{"op": "move", "from": "/TaskGroups[name=web]", "path": "/TaskGroups/0"},
+		map[string]interface{}{"op": "add", "path": "/TaskGroups[name=web]/Meta", "value": map[string]string{"tier": "frontend"}},
+	})
+	require.NoError(t, err)
+	assert.True(t, mutated)
+	assert.Equal(t, "web", *patched.TaskGroups[0].Name)
+	assert.Equal(t, map[string]string{"FOO": "bar"}, patched.TaskGroups[0].Tasks[1].Env)
+	assert.Equal(t, map[string]string{"tier": "frontend"}, patched.TaskGroups[0].Meta)
+
+	_, _, err = PatchJob(job, []interface{}{
+		map[string]interface{}{"op": "add", "path": "/TaskGroups[name=db]/Count", "value": 1},
+	})
+	assert.ErrorContains(t, err, `no element of "TaskGroups" has name="db"`)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
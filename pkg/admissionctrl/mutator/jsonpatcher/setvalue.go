@@ -0,0 +1,89 @@
+package jsonpatcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/hashicorp/nomad/api"
+)
+
+// SetJobValue sets the field at path to value, creating missing or null
+// objects along the way, unlike a JSON Patch add operation. The path may use
+// name selectors; "-" appends to an array, creating it if it is null.
+func SetJobValue(job *api.Job, path string, value interface{}) (*api.Job, bool, error) {
+	if path == "" || !strings.HasPrefix(path, "/") {
+		return nil, false, fmt.Errorf("path %q must point to a field of the job", path)
+	}
+	jobJson, err := json.Marshal(job)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal job: %w", err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(jobJson, &doc); err != nil {
+		return nil, false, err
+	}
+	resolved, err := ResolvePath(doc, path)
+	if err != nil {
+		return nil, false, err
+	}
+	if doc, err = setIn(doc, strings.Split(resolved, "/")[1:], value); err != nil {
+		return nil, false, fmt.Errorf("path %q: %w", path, err)
+	}
+	patchedJobJson, err := json.Marshal(doc)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal value: %w", err)
+	}
+	var patchedJob api.Job
+	if err := json.Unmarshal(patchedJobJson, &patchedJob); err != nil {
+		return nil, false, fmt.Errorf("path %q: %w", path, err)
+	}
+	return &patchedJob, !jsonpatch.Equal(jobJson, patchedJobJson), nil
+}
+
+func setIn(node interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	key, rest := unescape(tokens[0]), tokens[1:]
+	switch current := node.(type) {
+	case nil:
+		child, err := setIn(nil, rest, value)
+		if err != nil {
+			return nil, err
+		}
+		if key == "-" {
+			return []interface{}{child}, nil
+		}
+		return map[string]interface{}{key: child}, nil
+	case map[string]interface{}:
+		child, err := setIn(current[key], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		current[key] = child
+		return current, nil
+	case []interface{}:
+		if key == "-" {
+			child, err := setIn(nil, rest, value)
+			if err != nil {
+				return nil, err
+			}
+			return append(current, child), nil
+		}
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(current) {
+			return nil, fmt.Errorf("index %q is out of range", key)
+		}
+		child, err := setIn(current[index], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		current[index] = child
+		return current, nil
+	default:
+		return nil, fmt.Errorf("cannot set %q in a %T", key, node)
+	}
+}
@@ -0,0 +1,40 @@
+package jsonpatcher
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetJobValue(t *testing.T) {
+	job := &api.Job{
+		TaskGroups: []*api.TaskGroup{
+			{Name: strPtr("web"), Tasks: []*api.Task{{Name: "app"}}},
+		},
+	}
+
+	patched, mutated, err := SetJobValue(job, "/TaskGroups[name=web]/Tasks[name=app]/Env/FOO", "bar")
+	require.NoError(t, err)
+	assert.True(t, mutated)
+	assert.Equal(t, map[string]string{"FOO": "bar"}, patched.TaskGroups[0].Tasks[0].Env)
+	assert.Nil(t, job.TaskGroups[0].Tasks[0].Env, "the job is left untouched")
+
+	_, mutated, err = SetJobValue(patched, "/TaskGroups/0/Tasks/0/Env/FOO", "bar")
+	require.NoError(t, err)
+	assert.False(t, mutated)
+
+	patched, _, err = SetJobValue(job, "/Datacenters/-", "dc1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dc1"}, patched.Datacenters)
+
+	_, _, err = SetJobValue(job, "/TaskGroups/3/Count", 1)
+	assert.ErrorContains(t, err, `index "3" is out of range`)
+
+	_, _, err = SetJobValue(job, "/TaskGroups/0/Count", "many")
+	assert.Error(t, err)
+
+	_, _, err = SetJobValue(job, "", 1)
+	assert.ErrorContains(t, err, "must point to a field of the job")
+}
@@ -0,0 +1,227 @@
+package mutator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/config"
+)
+
+// JobDefaults are the values a DefaultsMutator sets. Empty values and nil
+// resource fields are left alone.
+type JobDefaults struct {
+	Namespace   string
+	NodePool    string
+	Datacenters []string
+	Resources   *api.Resources
+}
+
+// DefaultsMutator sets job fields and task resources the job leaves unset, or
+// replaces them if it overwrites.
+type DefaultsMutator struct {
+	name      string
+	defaults  JobDefaults
+	scope     *types.Scope
+	overwrite bool
+}
+
+var _ admissionctrl.JobMutator = (*DefaultsMutator)(nil)
+
+func NewDefaultsMutator(name string, defaults JobDefaults, scope *types.Scope, overwrite bool) *DefaultsMutator {
+	return &DefaultsMutator{name: name, defaults: defaults, scope: scope, overwrite: overwrite}
+}
+
+func (m *DefaultsMutator) Mutate(_ context.Context, payload *types.Payload) (*api.Job, bool, []error, error) {
+	job, err := copyJob(payload.Job)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	mutated := setString(&job.Namespace, m.defaults.Namespace, m.overwrite)
+	mutated = setString(&job.NodePool, m.defaults.NodePool, m.overwrite) || mutated
+	if len(m.defaults.Datacenters) > 0 && (len(job.Datacenters) == 0 || m.overwrite) && !slices.Equal(job.Datacenters, m.defaults.Datacenters) {
+		job.Datacenters = slices.Clone(m.defaults.Datacenters)
+		mutated = true
+	}
+	if m.defaults.Resources != nil {
+		forEachTask(job, m.scope, func(task *api.Task) {
+			if task.Resources == nil {
+				task.Resources = &api.Resources{}
+			}
+			mutated = setInt(&task.Resources.CPU, m.defaults.Resources.CPU, m.overwrite) || mutated
+			mutated = setInt(&task.Resources.Cores, m.defaults.Resources.Cores, m.overwrite) || mutated
+			mutated = setInt(&task.Resources.MemoryMB, m.defaults.Resources.MemoryMB, m.overwrite) || mutated
+			mutated = setInt(&task.Resources.MemoryMaxMB, m.defaults.Resources.MemoryMaxMB, m.overwrite) || mutated
+		})
+	}
+	if !mutated {
+		return payload.Job, false, nil, nil
+	}
+	return job, true, nil, nil
+}
+
+func (m *DefaultsMutator) Name() string {
+	return m.name
+}
+
+// InjectMetaMutator adds meta to the job, its task groups or its tasks. Keys
+// that are already set keep their value unless it overwrites.
+type InjectMetaMutator struct {
+	name      string
+	meta      map[string]string
+	target    string
+	scope     *types.Scope
+	overwrite bool
+}
+
+var _ admissionctrl.JobMutator = (*InjectMetaMutator)(nil)
+
+func NewInjectMetaMutator(name string, meta map[string]string, target string, scope *types.Scope, overwrite bool) *InjectMetaMutator {
+	return &InjectMetaMutator{name: name, meta: meta, target: target, scope: scope, overwrite: overwrite}
+}
+
+func (m *InjectMetaMutator) Mutate(_ context.Context, payload *types.Payload) (*api.Job, bool, []error, error) {
+	job, err := copyJob(payload.Job)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	mutated := false
+	inject := func(meta *map[string]string) {
+		for key, value := range m.meta {
+			current, found := (*meta)[key]
+			if found && (current == value || !m.overwrite) {
+				continue
+			}
+			if *meta == nil {
+				*meta = map[string]string{}
+			}
+			(*meta)[key] = value
+			mutated = true
+		}
+	}
+	switch m.target {
+	case config.TargetGroup:
+		forEachGroup(job, m.scope, func(group *api.TaskGroup) { inject(&group.Meta) })
+	case config.TargetTask:
+		forEachTask(job, m.scope, func(task *api.Task) { inject(&task.Meta) })
+	default:
+		inject(&job.Meta)
+	}
+	if !mutated {
+		return payload.Job, false, nil, nil
+	}
+	return job, true, nil, nil
+}
+
+func (m *InjectMetaMutator) Name() string {
+	return m.name
+}
+
+// ConstraintMutator adds a constraint to the job, its task groups or its
+// tasks, unless an identical constraint is already there.
+type ConstraintMutator struct {
+	name       string
+	constraint *api.Constraint
+	target     string
+	scope      *types.Scope
+}
+
+var _ admissionctrl.JobMutator = (*ConstraintMutator)(nil)
+
+func NewConstraintMutator(name string, constraint *api.Constraint, target string, scope *types.Scope) *ConstraintMutator {
+	return &ConstraintMutator{name: name, constraint: constraint, target: target, scope: scope}
+}
+
+func (m *ConstraintMutator) Mutate(_ context.Context, payload *types.Payload) (*api.Job, bool, []error, error) {
+	job, err := copyJob(payload.Job)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	mutated := false
+	add := func(constraints *[]*api.Constraint) {
+		for _, constraint := range *constraints {
+			if constraint != nil && *constraint == *m.constraint {
+				return
+			}
+		}
+		constraint := *m.constraint
+		*constraints = append(*constraints, &constraint)
+		mutated = true
+	}
+	switch m.target {
+	case config.TargetGroup:
+		forEachGroup(job, m.scope, func(group *api.TaskGroup) { add(&group.Constraints) })
+	case config.TargetTask:
+		forEachTask(job, m.scope, func(task *api.Task) { add(&task.Constraints) })
+	default:
+		add(&job.Constraints)
+	}
+	if !mutated {
+		return payload.Job, false, nil, nil
+	}
+	return job, true, nil, nil
+}
+
+func (m *ConstraintMutator) Name() string {
+	return m.name
+}
+
+// copyJob deep copies the job, so native mutators leave the payload's job
+// untouched.
+func copyJob(job *api.Job) (*api.Job, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job: %w", err)
+	}
+	var copied api.Job
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job: %w", err)
+	}
+	return &copied, nil
+}
+
+func forEachGroup(job *api.Job, scope *types.Scope, fn func(*api.TaskGroup)) {
+	for _, group := range job.TaskGroups {
+		if group != nil && scope.MatchesGroup(valueOf(group.Name)) {
+			fn(group)
+		}
+	}
+}
+
+func forEachTask(job *api.Job, scope *types.Scope, fn func(*api.Task)) {
+	forEachGroup(job, scope, func(group *api.TaskGroup) {
+		for _, task := range group.Tasks {
+			if task != nil && scope.MatchesTask(task.Name) {
+				fn(task)
+			}
+		}
+	})
+}
+
+func setString(field **string, value string, overwrite bool) bool {
+	if value == "" || (*field != nil && **field != "" && !overwrite) || (*field != nil && **field == value) {
+		return false
+	}
+	*field = &value
+	return true
+}
+
+func setInt(field **int, value *int, overwrite bool) bool {
+	if value == nil || (*field != nil && !overwrite) || (*field != nil && **field == *value) {
+		return false
+	}
+	v := *value
+	*field = &v
+	return true
+}
+
+func valueOf(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
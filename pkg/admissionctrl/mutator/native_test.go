@@ -0,0 +1,183 @@
+package mutator
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/config"
+	"github.com/mxab/nacp/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nativeTestJob() *api.Job {
+	job := testutil.BaseJob()
+	job.TaskGroups = []*api.TaskGroup{
+		{
+			Name: config.Ptr("web"),
+			Tasks: []*api.Task{
+				{Name: "app", Resources: &api.Resources{CPU: config.Ptr(500)}},
+				{Name: "sidecar"},
+			},
+		},
+		{
+			Name:  config.Ptr("worker"),
+			Tasks: []*api.Task{{Name: "app"}},
+		},
+	}
+	return job
+}
+
+func TestDefaultsMutator(t *testing.T) {
+	defaults := JobDefaults{
+		Namespace:   "team-a",
+		NodePool:    "general",
+		Datacenters: []string{"dc1"},
+		Resources:   &api.Resources{CPU: config.Ptr(100), MemoryMB: config.Ptr(128)},
+	}
+
+	t.Run("fills unset values", func(t *testing.T) {
+		job := nativeTestJob()
+		job.Namespace = config.Ptr("existing")
+
+		result, mutated, warnings, err := NewDefaultsMutator("defaults", defaults, nil, false).Mutate(t.Context(), &types.Payload{Job: job})
+		require.NoError(t, err)
+		assert.True(t, mutated)
+		assert.Empty(t, warnings)
+		assert.Equal(t, "existing", *result.Namespace)
+		assert.Equal(t, "general", *result.NodePool)
+		assert.Equal(t, []string{"dc1"}, result.Datacenters)
+		assert.Equal(t, &api.Resources{CPU: config.Ptr(500), MemoryMB: config.Ptr(128)}, result.TaskGroups[0].Tasks[0].Resources)
+		assert.Equal(t, &api.Resources{CPU: config.Ptr(100), MemoryMB: config.Ptr(128)}, result.TaskGroups[1].Tasks[0].Resources)
+		assert.Equal(t, nativeTestJob().TaskGroups, job.TaskGroups, "the payload's job is left untouched")
+	})
+
+	t.Run("overwrites set values", func(t *testing.T) {
+		job := nativeTestJob()
+		job.Namespace = config.Ptr("existing")
+
+		result, mutated, _, err := NewDefaultsMutator("defaults", defaults, nil, true).Mutate(t.Context(), &types.Payload{Job: job})
+		require.NoError(t, err)
+		assert.True(t, mutated)
+		assert.Equal(t, "team-a", *result.Namespace)
+		assert.Equal(t, config.Ptr(100), result.TaskGroups[0].Tasks[0].Resources.CPU)
+	})
+
+	t.Run("scoped resources", func(t *testing.T) {
+		scope := &types.Scope{TaskGroups: []string{"web"}, Tasks: []string{"side*"}}
+		result, mutated, _, err := NewDefaultsMutator("defaults", JobDefaults{Resources: defaults.Resources}, scope, false).Mutate(t.Context(), &types.Payload{Job: nativeTestJob()})
+		require.NoError(t, err)
+		assert.True(t, mutated)
+		assert.Equal(t, &api.Resources{CPU: config.Ptr(500)}, result.TaskGroups[0].Tasks[0].Resources)
+		assert.Equal(t, defaults.Resources, result.TaskGroups[0].Tasks[1].Resources)
+		assert.Nil(t, result.TaskGroups[1].Tasks[0].Resources)
+	})
+
+	t.Run("unchanged job", func(t *testing.T) {
+		job := nativeTestJob()
+		job.Namespace = config.Ptr("team-a")
+
+		result, mutated, _, err := NewDefaultsMutator("defaults", JobDefaults{Namespace: "team-a"}, nil, true).Mutate(t.Context(), &types.Payload{Job: job})
+		require.NoError(t, err)
+		assert.False(t, mutated)
+		assert.Same(t, job, result)
+	})
+}
+
+func TestInjectMetaMutator(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		scope       *types.Scope
+		overwrite   bool
+		prepare     func(*api.Job)
+		wantMutated bool
+		check       func(*testing.T, *api.Job)
+	}{
+		{
+			name:        "job meta",
+			wantMutated: true,
+			check: func(t *testing.T, job *api.Job) {
+				assert.Equal(t, map[string]string{"owner": "team-a"}, job.Meta)
+			},
+		},
+		{
+			name:    "existing key is kept",
+			prepare: func(job *api.Job) { job.Meta = map[string]string{"owner": "team-b"} },
+			check: func(t *testing.T, job *api.Job) {
+				assert.Equal(t, map[string]string{"owner": "team-b"}, job.Meta)
+			},
+		},
+		{
+			name:        "existing key is overwritten",
+			overwrite:   true,
+			prepare:     func(job *api.Job) { job.Meta = map[string]string{"owner": "team-b"} },
+			wantMutated: true,
+			check: func(t *testing.T, job *api.Job) {
+				assert.Equal(t, map[string]string{"owner": "team-a"}, job.Meta)
+			},
+		},
+		{
+			name:        "scoped groups",
+			target:      config.TargetGroup,
+			scope:       &types.Scope{TaskGroups: []string{"work*"}},
+			wantMutated: true,
+			check: func(t *testing.T, job *api.Job) {
+				assert.Nil(t, job.TaskGroups[0].Meta)
+				assert.Equal(t, map[string]string{"owner": "team-a"}, job.TaskGroups[1].Meta)
+			},
+		},
+		{
+			name:        "scoped tasks",
+			target:      config.TargetTask,
+			scope:       &types.Scope{Tasks: []string{"app"}},
+			wantMutated: true,
+			check: func(t *testing.T, job *api.Job) {
+				assert.Equal(t, map[string]string{"owner": "team-a"}, job.TaskGroups[0].Tasks[0].Meta)
+				assert.Nil(t, job.TaskGroups[0].Tasks[1].Meta)
+				assert.Equal(t, map[string]string{"owner": "team-a"}, job.TaskGroups[1].Tasks[0].Meta)
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			job := nativeTestJob()
+			if tc.prepare != nil {
+				tc.prepare(job)
+			}
+			mutator := NewInjectMetaMutator("meta", map[string]string{"owner": "team-a"}, tc.target, tc.scope, tc.overwrite)
+
+			result, mutated, _, err := mutator.Mutate(t.Context(), &types.Payload{Job: job})
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantMutated, mutated)
+			tc.check(t, result)
+		})
+	}
+}
+
+func TestConstraintMutator(t *testing.T) {
+	constraint := &api.Constraint{LTarget: "${attr.kernel.name}", RTarget: "linux", Operand: "="}
+
+	result, mutated, _, err := NewConstraintMutator("linux", constraint, config.TargetGroup, &types.Scope{TaskGroups: []string{"web"}}).Mutate(t.Context(), &types.Payload{Job: nativeTestJob()})
+	require.NoError(t, err)
+	assert.True(t, mutated)
+	assert.Equal(t, []*api.Constraint{constraint}, result.TaskGroups[0].Constraints)
+	assert.Nil(t, result.TaskGroups[1].Constraints)
+
+	// adding the same constraint again leaves the job alone
+	again, mutated, _, err := NewConstraintMutator("linux", constraint, config.TargetGroup, nil).Mutate(t.Context(), &types.Payload{Job: result})
+	require.NoError(t, err)
+	assert.True(t, mutated, "the worker group still lacks the constraint")
+	assert.Equal(t, []*api.Constraint{constraint}, again.TaskGroups[0].Constraints)
+	assert.Equal(t, []*api.Constraint{constraint}, again.TaskGroups[1].Constraints)
+
+	_, mutated, _, err = NewConstraintMutator("linux", constraint, config.TargetGroup, nil).Mutate(t.Context(), &types.Payload{Job: again})
+	require.NoError(t, err)
+	assert.False(t, mutated)
+
+	job, mutated, _, err := NewConstraintMutator("linux", constraint, "", nil).Mutate(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	require.NoError(t, err)
+	assert.True(t, mutated)
+	assert.Equal(t, []*api.Constraint{constraint}, job.Constraints)
+}
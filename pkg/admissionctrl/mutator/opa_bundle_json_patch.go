@@ -47,12 +47,12 @@ func (m *OpaBundleMutator) Mutate(ctx context.Context, payload *types.Payload) (
 		return nil, false, warnings, err
 	}
 
-	patch, found := result["patch"]
-	if !found || patch == nil {
+	patch, mergePatch := result["patch"], result["merge_patch"]
+	if patch == nil && mergePatch == nil {
 		return payload.Job, false, warnings, nil
 	}
 
-	job, mutated, err := applyDecisionPatch(payload.Job, patch)
+	job, mutated, err := applyDecisionPatch(payload.Job, patch, mergePatch)
 	return job, mutated, warnings, err
 }
 
@@ -71,11 +71,7 @@ func parseDecisionErrors(raw interface{}) error {
 		if entry == nil {
 			continue
 		}
-		message, ok := entry.(string)
-		if !ok {
-			return fmt.Errorf("policy yielded an invalid error entry value: %v", entry)
-		}
-		result = multierror.Append(result, errors.New(message))
+		result = multierror.Append(result, types.PolicyViolation(entry))
 	}
 	return result
 }
@@ -95,22 +91,21 @@ func parseDecisionWarnings(raw interface{}) ([]error, error) {
 		if entry == nil {
 			continue
 		}
-		message, ok := entry.(string)
-		if !ok {
-			return warnings, fmt.Errorf("policy yielded an invalid warning entry value: %v", entry)
-		}
-		warnings = append(warnings, errors.New(message))
+		warnings = append(warnings, types.PolicyViolation(entry))
 	}
 	return warnings, nil
 }
 
-func applyDecisionPatch(job *api.Job, raw interface{}) (*api.Job, bool, error) {
-	operations, ok := raw.([]interface{})
-	if !ok {
-		return nil, false, fmt.Errorf("policy yielded an invalid patch value: %v", raw)
+func applyDecisionPatch(job *api.Job, raw interface{}, mergePatch interface{}) (*api.Job, bool, error) {
+	var operations []interface{}
+	if raw != nil {
+		var ok bool
+		if operations, ok = raw.([]interface{}); !ok {
+			return nil, false, fmt.Errorf("policy yielded an invalid patch value: %v", raw)
+		}
 	}
 
-	result, mutated, err := jsonpatcher.PatchJob(job, operations)
+	result, mutated, err := jsonpatcher.ApplyPatches(job, operations, mergePatch)
 	if err != nil {
 		return nil, false, fmt.Errorf("policy yielded patch failed: %w", err)
 	}
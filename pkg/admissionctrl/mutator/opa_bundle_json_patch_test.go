@@ -109,6 +109,42 @@ func TestOpaBundleMutator(t *testing.T) {
 			expectedWarns:   []string{},
 			expectedErrs:    []string{},
 		},
+		{
+			name: "merge patch",
+			policy: `package mypolicy
+			merge_patch = {"Meta": {"hello": "world"}}
+			`,
+			path:     "/mypolicy",
+			inputJob: &api.Job{},
+			expectedJob: &api.Job{
+				Meta: map[string]string{
+					"hello": "world",
+				},
+			},
+			expectedMutated: true,
+			expectedWarns:   []string{},
+			expectedErrs:    []string{},
+		},
+		{
+			name: "merge patch is applied before patch",
+			policy: `package mypolicy
+			merge_patch = {"Meta": {"hello": "world", "owner": "team-a"}}
+			patch = [
+			{"op": "replace", "path": "/Meta/owner", "value": "team-b"}
+			]
+			`,
+			path:     "/mypolicy",
+			inputJob: &api.Job{},
+			expectedJob: &api.Job{
+				Meta: map[string]string{
+					"hello": "world",
+					"owner": "team-b",
+				},
+			},
+			expectedMutated: true,
+			expectedWarns:   []string{},
+			expectedErrs:    []string{},
+		},
 		{
 			name: "handle errors",
 			policy: `package mypolicy
@@ -178,7 +214,7 @@ func TestOpaBundleMutator(t *testing.T) {
 			expectedErrs:    []string{"policy yielded an invalid warnings value"},
 		},
 		{
-			name: "handle invalid error entry type as error",
+			name: "keep invalid error entry as rejection",
 			policy: `package mypolicy
 			errors = ["this is fine", 5]
 			`,
@@ -187,19 +223,19 @@ func TestOpaBundleMutator(t *testing.T) {
 			expectedJob:     nil,
 			expectedMutated: false,
 			expectedWarns:   []string{},
-			expectedErrs:    []string{"policy yielded an invalid error entry value"},
+			expectedErrs:    []string{"this is fine", "5"},
 		},
 		{
-			name: "handle invalid warning entry type as warning",
+			name: "keep invalid warning entry as warning",
 			policy: `package mypolicy
 			warnings = ["this is fine", 5]
 			`,
 			path:            "/mypolicy",
 			inputJob:        &api.Job{},
-			expectedJob:     nil,
+			expectedJob:     &api.Job{},
 			expectedMutated: false,
-			expectedWarns:   []string{"this is fine"},
-			expectedErrs:    []string{"policy yielded an invalid warning entry value"},
+			expectedWarns:   []string{"this is fine", "5"},
+			expectedErrs:    []string{},
 		},
 		{
 			name: "handle invalid patch type as error",
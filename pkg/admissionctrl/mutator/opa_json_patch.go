@@ -2,7 +2,6 @@ package mutator
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 
 	"github.com/hashicorp/go-multierror"
@@ -32,8 +31,9 @@ func (j *OpaJsonPatchMutator) Mutate(ctx context.Context, payload *types.Payload
 	if len(errors) > 0 {
 		j.logger.Debug("Got errors from rule", "rule", j.Name(), "errors", errors, "job", payload.Job.ID)
 		allErrors := multierror.Append(nil)
-		for _, warn := range errors {
-			allErrors = multierror.Append(allErrors, fmt.Errorf("%s (%s)", warn, j.Name()))
+		for _, entry := range errors {
+			violation := opa.RuleViolation(entry, j.Name())
+			allErrors = multierror.Append(allErrors, violation)
 		}
 		return nil, false, nil, allErrors
 	}
@@ -43,11 +43,12 @@ func (j *OpaJsonPatchMutator) Mutate(ctx context.Context, payload *types.Payload
 	if len(warnings) > 0 {
 		j.logger.Debug("Got warnings from rule", "rule", j.Name(), "warnings", warnings, "job", payload.Job.ID)
 		for _, warn := range warnings {
-			allWarnings = append(allWarnings, fmt.Errorf("%s (%s)", warn, j.Name()))
+			violation := opa.RuleViolation(warn, j.Name())
+			allWarnings = append(allWarnings, violation)
 		}
 	}
 	patchData := results.GetPatch()
-	patchedJob, mutated, err := jsonpatcher.PatchJob(payload.Job, patchData)
+	patchedJob, mutated, err := jsonpatcher.ApplyPatches(payload.Job, patchData, results.GetMergePatch())
 	if err != nil {
 		return nil, false, nil, err
 	}
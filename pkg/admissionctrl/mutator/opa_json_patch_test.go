@@ -1,7 +1,6 @@
 package mutator
 
 import (
-	"fmt"
 	"log/slog"
 	"testing"
 
@@ -45,6 +44,24 @@ func TestJSONPatcher_Mutate(t *testing.T) {
 			wantWarnings: []error{},
 			wantErr:      false,
 		},
+		{
+			name: "merge patch",
+			j: newMutator(t, testutil.Filepath(t, "opa/mutators/opajsonpatchtesting/opajsonpatchtesting.rego"),
+				`merge_patch = data.opajsonpatchtesting.merge_patch`,
+			),
+
+			args: args{
+				job: &api.Job{},
+			},
+			wantOut: &api.Job{
+				Meta: map[string]string{
+					"owner": "platform",
+				},
+			},
+			wantMutated:  true,
+			wantWarnings: []error{},
+			wantErr:      false,
+		},
 		{
 			name: "warning",
 			j: newMutator(t, testutil.Filepath(t, "opa/mutators/opajsonpatchtesting/opajsonpatchtesting.rego"),
@@ -56,7 +73,7 @@ func TestJSONPatcher_Mutate(t *testing.T) {
 			},
 			wantOut:      &api.Job{},
 			wantMutated:  false,
-			wantWarnings: []error{fmt.Errorf("This is a warning message (%s)", "testopavalidator")},
+			wantWarnings: []error{&types.Violation{Message: "This is a warning message (testopavalidator)"}},
 			wantErr:      false,
 		},
 		{
@@ -77,7 +94,7 @@ func TestJSONPatcher_Mutate(t *testing.T) {
 				},
 			},
 			wantMutated:  true,
-			wantWarnings: []error{fmt.Errorf("This is a warning message (%s)", "testopavalidator")},
+			wantWarnings: []error{&types.Violation{Message: "This is a warning message (testopavalidator)"}},
 			wantErr:      false,
 		},
 		{
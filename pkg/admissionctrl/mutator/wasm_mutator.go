@@ -0,0 +1,71 @@
+package mutator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl"
+	"github.com/mxab/nacp/pkg/admissionctrl/mutator/jsonpatcher"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/admissionctrl/wasmutil"
+)
+
+// WasmMutator mutates jobs with a WebAssembly module. The module gets the
+// payload webhooks get and answers like a json_patch_webhook, with a patch,
+// a merge patch, errors and warnings.
+type WasmMutator struct {
+	name   string
+	logger *slog.Logger
+	module *wasmutil.Module
+}
+
+var _ admissionctrl.JobMutator = (*WasmMutator)(nil)
+
+// NewWasmMutator compiles the module in file, which has to export
+// nacp_mutate.
+func NewWasmMutator(name string, file string, options wasmutil.Options, logger *slog.Logger) (*WasmMutator, error) {
+	module, err := wasmutil.Load(context.Background(), file, wasmutil.MutateFunction, options)
+	if err != nil {
+		return nil, err
+	}
+	return &WasmMutator{name: name, logger: logger, module: module}, nil
+}
+
+func (m *WasmMutator) Mutate(ctx context.Context, payload *types.Payload) (*api.Job, bool, []error, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	output, err := m.module.Call(ctx, data)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	result := &jsonPatchWebhookResponse{}
+	if err := json.Unmarshal(output, result); err != nil {
+		return nil, false, nil, fmt.Errorf("failed to decode wasm module response: %w", err)
+	}
+
+	warnings, policyErr := types.DecodeResult(result.Warnings, result.Errors)
+	if policyErr != nil {
+		m.logger.Debug("mutation errors", "errors", result.Errors, "rule", m.name)
+		return nil, false, warnings, policyErr
+	}
+
+	job, mutated, err := jsonpatcher.ApplyPatches(payload.Job, result.Patch, result.MergePatch)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	return job, mutated, warnings, nil
+}
+
+func (m *WasmMutator) Name() string {
+	return m.name
+}
+
+// Close releases the module's runtime and instances.
+func (m *WasmMutator) Close() error {
+	return m.module.Close(context.Background())
+}
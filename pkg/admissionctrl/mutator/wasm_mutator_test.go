@@ -0,0 +1,47 @@
+package mutator
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/admissionctrl/wasmutil"
+	"github.com/mxab/nacp/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWasmMutator(t *testing.T) {
+	mutator, err := NewWasmMutator("wasm", testutil.BuildWasm(t, "wasm/policy"), wasmutil.Options{}, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	assert.Equal(t, "wasm", mutator.Name())
+
+	job, mutated, warnings, err := mutator.Mutate(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.True(t, mutated)
+	assert.Equal(t, &api.Job{ID: testutil.BaseJob().ID, Meta: map[string]string{"mutated-by": "wasm"}}, job)
+
+	job, mutated, _, err = mutator.Mutate(t.Context(), &types.Payload{Job: job})
+	require.NoError(t, err)
+	assert.False(t, mutated, "Applying the same merge patch again changes nothing")
+	assert.Equal(t, "wasm", job.Meta["mutated-by"])
+}
+
+func TestWasmMutatorTimeout(t *testing.T) {
+	mutator, err := NewWasmMutator("wasm", testutil.BuildWasm(t, "wasm/policy"), wasmutil.Options{CallTimeout: 50 * time.Millisecond}, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+
+	job := &api.Job{Meta: map[string]string{"spin": "true"}}
+	out, mutated, _, err := mutator.Mutate(t.Context(), &types.Payload{Job: job})
+	assert.ErrorContains(t, err, "exceeded its call timeout")
+	assert.Nil(t, out)
+	assert.False(t, mutated)
+}
+
+func TestNewWasmMutatorMissingModule(t *testing.T) {
+	_, err := NewWasmMutator("wasm", testutil.Filepath(t, "wasm/missing.wasm"), wasmutil.Options{}, slog.New(slog.DiscardHandler))
+	assert.ErrorContains(t, err, "failed to read wasm module")
+}
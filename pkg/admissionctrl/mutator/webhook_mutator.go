@@ -4,63 +4,105 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
-	"reflect"
 
+	"github.com/mxab/nacp/pkg/admissionctrl/mutator/jsonpatcher"
 	"github.com/mxab/nacp/pkg/admissionctrl/remoteutil"
 	"github.com/mxab/nacp/pkg/admissionctrl/types"
 
 	"github.com/hashicorp/nomad/api"
 )
 
+// WebhookMutator sends the payload to a webhook that answers with the whole
+// modified job rather than a JSON Patch.
 type WebhookMutator struct {
 	name     string
+	logger   *slog.Logger
 	endpoint *url.URL
-	method   string
+	// signingSecret signs requests when set.
+	signingSecret []byte
+	method        string
 }
 
-func (w *WebhookMutator) Mutate(ctx context.Context, payload *types.Payload) (out *api.Job, mutated bool, warnings []error, err error) {
+// webhookMutatorResponse is the webhook's answer. A missing job leaves the
+// job unchanged.
+type webhookMutatorResponse struct {
+	Job      *api.Job            `json:"job"`
+	Warnings types.ViolationList `json:"warnings"`
+	Errors   types.ViolationList `json:"errors"`
+}
+
+func NewWebhookMutator(name string, endpoint string, method string, signingSecret []byte, logger *slog.Logger) (*WebhookMutator, error) {
+	u, err := remoteutil.ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookMutator{
+		name:          name,
+		logger:        logger,
+		endpoint:      u,
+		method:        method,
+		signingSecret: signingSecret,
+	}, nil
+}
+
+func (w *WebhookMutator) Mutate(ctx context.Context, payload *types.Payload) (*api.Job, bool, []error, error) {
 	data, err := json.Marshal(payload)
 	if err != nil {
 		return nil, false, nil, err
 	}
-	req, err := http.NewRequestWithContext(ctx, w.method, w.endpoint.String(), bytes.NewBuffer(data))
+	req, err := http.NewRequestWithContext(ctx, w.method, w.endpoint.String(), bytes.NewReader(data))
 	if err != nil {
 		return nil, false, nil, err
 	}
 
 	remoteutil.ApplyContextHeaders(req, payload)
-
-	req.Body = io.NopCloser(bytes.NewBuffer(data))
-	req.ContentLength = int64(len(data))
+	remoteutil.SignRequest(req, data, w.signingSecret)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
-	client := remoteutil.NewInstrumentedClient()
-	resp, err := client.Do(req)
+	resp, err := remoteutil.NewInstrumentedClient().Do(req)
 	if err != nil {
 		return nil, false, nil, err
 	}
 	defer resp.Body.Close()
 
-	newJob := &api.Job{}
-	if err := remoteutil.DecodeJSONResponse(resp, newJob); err != nil {
+	mutateResponse := &webhookMutatorResponse{}
+	if err := remoteutil.DecodeJSONResponse(resp, mutateResponse); err != nil {
 		return nil, false, nil, err
 	}
-	mutated = !reflect.DeepEqual(newJob, payload.Job)
-	return newJob, mutated, nil, nil
-}
-func (w *WebhookMutator) Name() string {
-	return w.name
-}
 
-func NewWebhookMutator(name string, endpoint *url.URL, method string) *WebhookMutator {
+	if len(mutateResponse.Warnings) > 0 {
+		w.logger.Debug("Got warnings from webhook", "rule", w.name, "warnings", mutateResponse.Warnings, "job", payload.Job.ID)
+	}
+	warnings, policyErr := types.DecodeResult(mutateResponse.Warnings, mutateResponse.Errors)
+	if policyErr != nil {
+		return nil, false, warnings, policyErr
+	}
 
-	return &WebhookMutator{
-		name:     name,
-		endpoint: endpoint,
-		method:   method,
+	if mutateResponse.Job == nil {
+		return payload.Job, false, warnings, nil
 	}
+
+	// diff the JSON documents like the handler does for its mutation
+	// records, so mutated agrees with the recorded patch
+	before, err := json.Marshal(payload.Job)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	after, err := json.Marshal(mutateResponse.Job)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	patch, err := jsonpatcher.CreatePatch(before, after)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	return mutateResponse.Job, len(patch) > 0, warnings, nil
+}
+
+func (w *WebhookMutator) Name() string {
+	return w.name
 }
@@ -2,186 +2,147 @@ package mutator
 
 import (
 	"encoding/json"
-	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
-	"reflect"
 	"testing"
 
 	"github.com/mxab/nacp/pkg/admissionctrl/types"
 	"github.com/mxab/nacp/pkg/config"
+	"github.com/mxab/nacp/testutil"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWebhookMutator_Mutate(t *testing.T) {
-	type fields struct {
-		name         string
-		endpointPath string
-		method       string
-	}
-	type args struct {
-		job     *api.Job
-		context *config.RequestContext
-	}
+	mutatedJob := testutil.BaseJob()
+	mutatedJob.Meta = map[string]string{"test": "test"}
 
 	tests := []struct {
-		name         string
-		fields       fields
-		args         args
-		wantOut      *api.Job
-		wantMutated  bool
-		wantWarnings []error
-		wantErr      bool
+		name    string
+		context *config.RequestContext
+
+		response       string
+		responseStatus int
+		wantJob        *api.Job
+		wantMutated    bool
+		wantWarnings   []error
+		wantErr        error
+		wantErrMessage string
 
 		wantedHeaders map[string]string
 	}{
 		{
-			name: "Test simple mutation",
-			fields: fields{
-				name:         "test",
-				endpointPath: "/test",
-				method:       "POST",
-			},
-			args: args{
-				job: &api.Job{},
-			},
-			wantOut: &api.Job{
-				Meta: map[string]string{
-					"test": "test",
-				},
-			},
+			name:        "modified job",
+			response:    toJSON(t, map[string]any{"job": mutatedJob}),
+			wantJob:     mutatedJob,
 			wantMutated: true,
 		},
 		{
-			name: "with context client ip",
-			fields: fields{
-				name:         "test",
-				endpointPath: "/test",
-				method:       "POST",
-			},
-			args: args{
-				job: &api.Job{},
-				context: &config.RequestContext{
-					ClientIP: "127.0.0.1",
-				},
-			},
-			wantOut: &api.Job{
-				Meta: map[string]string{
-					"test": "test",
-				},
-			},
-			wantMutated: true,
-			wantedHeaders: map[string]string{
-				"X-Forwarded-For": "127.0.0.1",
-				"NACP-Client-IP":  "127.0.0.1",
-			},
+			name:        "unchanged job",
+			response:    toJSON(t, map[string]any{"job": testutil.BaseJob()}),
+			wantJob:     testutil.BaseJob(),
+			wantMutated: false,
 		},
 		{
-			name: "with context accessor id",
-			fields: fields{
-				name:         "test",
-				endpointPath: "/test",
-				method:       "POST",
-			},
-			args: args{
-				job: &api.Job{},
-				context: &config.RequestContext{
-					AccessorID: "1234",
-				},
-			},
-			wantOut: &api.Job{
-				Meta: map[string]string{
-					"test": "test",
-				},
+			name:        "missing job keeps the job",
+			response:    `{}`,
+			wantJob:     testutil.BaseJob(),
+			wantMutated: false,
+		},
+		{
+			name:         "warnings",
+			response:     toJSON(t, map[string]any{"job": mutatedJob, "warnings": []any{"plain", map[string]any{"message": "structured", "code": "W1"}}}),
+			wantJob:      mutatedJob,
+			wantMutated:  true,
+			wantWarnings: []error{&types.Violation{Message: "plain"}, &types.Violation{Message: "structured", Code: "W1"}},
+		},
+		{
+			name:     "errors",
+			response: `{"errors": ["denied"]}`,
+			wantErr:  multierror.Append(nil, &types.Violation{Message: "denied"}),
+		},
+		{
+			name:           "non-success HTTP response",
+			response:       `{}`,
+			responseStatus: http.StatusServiceUnavailable,
+			wantErrMessage: "unexpected HTTP status 503 Service Unavailable",
+		},
+		{
+			name:     "with context headers",
+			response: `{}`,
+			context: &config.RequestContext{
+				ClientIP:   "127.0.0.1",
+				AccessorID: "1234",
 			},
-			wantMutated: true,
+			wantJob: testutil.BaseJob(),
 			wantedHeaders: map[string]string{
+				"X-Forwarded-For":  "127.0.0.1",
+				"NACP-Client-IP":   "127.0.0.1",
 				"NACP-Accessor-ID": "1234",
 			},
 		},
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
 			endpointCalled := false
-			testEndpoint := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-				// Test request parameters
+			endpoint := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 				endpointCalled = true
-				assert.Equal(t, req.Method, tt.fields.method, "Ensure method is set")
-				assert.Equal(t, req.URL.Path, tt.fields.endpointPath, "Ensure path is set")
-				assert.Equal(t, req.Header.Get("Content-Type"), "application/json", "Ensure content type is set")
-				job := &api.Job{}
-				json.NewDecoder(req.Body).Decode(job)
-				assert.Equal(t, job, tt.args.job, "Ensure job is set")
-				for key, value := range tt.wantedHeaders {
+				assert.Equal(t, http.MethodPost, req.Method)
+				assert.Equal(t, "/mutate", req.URL.Path)
+				assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+				assert.Equal(t, "application/json", req.Header.Get("Accept"))
+				payload := &types.Payload{}
+				require.NoError(t, json.NewDecoder(req.Body).Decode(payload))
+				assert.Equal(t, testutil.BaseJob(), payload.Job)
+				for key, value := range tc.wantedHeaders {
 					assert.Equal(t, value, req.Header.Get(key), "Header %s does not match", key)
 				}
-				rw.WriteHeader(http.StatusOK)
-				json.NewEncoder(rw).Encode(tt.wantOut)
-
+				rw.Header().Set("Content-Type", "application/json")
+				status := tc.responseStatus
+				if status == 0 {
+					status = http.StatusOK
+				}
+				rw.WriteHeader(status)
+				rw.Write([]byte(tc.response))
 			}))
-			defer testEndpoint.Close()
+			defer endpoint.Close()
 
-			endpoint, _ := url.Parse(fmt.Sprintf("%s%s", testEndpoint.URL, tt.fields.endpointPath))
+			mutator, err := NewWebhookMutator("test", endpoint.URL+"/mutate", http.MethodPost, nil, slog.New(slog.DiscardHandler))
+			require.NoError(t, err)
+
+			job, mutated, warnings, err := mutator.Mutate(t.Context(), &types.Payload{Job: testutil.BaseJob(), Context: tc.context})
 
-			w := &WebhookMutator{
-				name:     tt.fields.name,
-				endpoint: endpoint,
-				method:   tt.fields.method,
-			}
-			payload := &types.Payload{Job: tt.args.job, Context: tt.args.context}
-			gotOut, gotMutated, gotWarnings, err := w.Mutate(t.Context(), payload)
 			assert.True(t, endpointCalled, "Ensure endpoint was called")
-			if (err != nil) != tt.wantErr {
-				t.Errorf("WebhookMutator.Mutate() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			switch {
+			case tc.wantErr != nil:
+				assert.Equal(t, tc.wantErr, err)
+			case tc.wantErrMessage != "":
+				assert.ErrorContains(t, err, tc.wantErrMessage)
+			default:
+				assert.NoError(t, err)
 			}
-			assert.Equal(t, gotOut, tt.wantOut, "Ensure job is set")
-			assert.Equal(t, tt.wantMutated, gotMutated, "WebhookMutator.Mutate() mutated = %v, want %v", gotMutated, tt.wantMutated)
-			assert.Equal(t, gotWarnings, tt.wantWarnings, "Ensure warnings are set")
-
+			assert.Equal(t, tc.wantJob, job)
+			assert.Equal(t, tc.wantMutated, mutated)
+			assert.Equal(t, tc.wantWarnings, warnings)
 		})
 	}
 }
 
 func TestNewWebhookMutator(t *testing.T) {
-	type args struct {
-		name     string
-		endpoint *url.URL
-		method   string
-	}
-	tests := []struct {
-		name string
-		args args
-		want *WebhookMutator
-	}{
-		{
-			name: "test",
-			args: args{
-				name:     "test",
-				endpoint: mustParse(t, "http://localhost:8080/foo/bar"),
-				method:   "POST",
-			},
-			want: &WebhookMutator{
-				name:     "test",
-				endpoint: mustParse(t, "http://localhost:8080/foo/bar"),
-				method:   "POST",
-			},
-		},
-	}
+	mutator, err := NewWebhookMutator("test", "http://localhost:8080/foo/bar", http.MethodPost, nil, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	assert.Equal(t, "test", mutator.Name())
+	assert.Equal(t, mustParse(t, "http://localhost:8080/foo/bar"), mutator.endpoint)
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := NewWebhookMutator(tt.args.name, tt.args.endpoint, tt.args.method); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NewWebhookMutator() = %v, want %v", got, tt.want)
-				assert.Equal(t, got.Name(), tt.args.name, "Name is not equal")
-			}
-		})
-	}
+	_, err = NewWebhookMutator("test", "not a url", http.MethodPost, nil, slog.New(slog.DiscardHandler))
+	assert.Error(t, err)
 }
+
 func mustParse(t *testing.T, s string) *url.URL {
 	t.Helper()
 	u, err := url.Parse(s)
@@ -190,3 +151,10 @@ func mustParse(t *testing.T, s string) *url.URL {
 	}
 	return u
 }
+
+func toJSON(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
@@ -3,6 +3,7 @@ package opa
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 
 	types2 "github.com/mxab/nacp/pkg/admissionctrl/types"
@@ -91,6 +92,22 @@ func (result *OpaQueryResult) GetErrors() []interface{} {
 	}
 	return errors
 }
+
+// GetMergePatch returns the JSON Merge Patch bound to merge_patch, or nil.
+func (result *OpaQueryResult) GetMergePatch() interface{} {
+
+	rs := *result.resultSet
+	return rs[0].Bindings["merge_patch"]
+}
+
+// RuleViolation converts an entry of a rule's errors or warnings, see
+// types.PolicyViolation, naming the rule in its message.
+func RuleViolation(entry interface{}, rule string) *types2.Violation {
+	violation := types2.PolicyViolation(entry)
+	violation.Message = fmt.Sprintf("%s (%s)", violation.Message, rule)
+	return violation
+}
+
 func (result *OpaQueryResult) GetPatch() []interface{} {
 
 	rs := *result.resultSet
@@ -2,12 +2,16 @@ package remoteutil
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptrace"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/mxab/nacp/pkg/admissionctrl/types"
@@ -15,19 +19,47 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
+const (
+	ClientIPHeader   = "NACP-Client-IP"
+	AccessorIDHeader = "NACP-Accessor-ID"
+	// SignatureHeader carries "t=<unix timestamp>,v1=<hex HMAC-SHA256>" of
+	// signed webhook requests.
+	SignatureHeader = "NACP-Signature"
+)
+
 func ApplyContextHeaders(req *http.Request, payload *types.Payload) {
 	if payload.Context != nil {
 		// Add standard headers for backward compatibility
 		if payload.Context.ClientIP != "" {
 			req.Header.Set("X-Forwarded-For", payload.Context.ClientIP) // Standard proxy header
-			req.Header.Set("NACP-Client-IP", payload.Context.ClientIP)  // NACP specific
+			req.Header.Set(ClientIPHeader, payload.Context.ClientIP)    // NACP specific
 		}
 		if payload.Context.AccessorID != "" {
-			req.Header.Set("NACP-Accessor-ID", payload.Context.AccessorID)
+			req.Header.Set(AccessorIDHeader, payload.Context.AccessorID)
 		}
 	}
 }
 
+// Signature returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with
+// secret.
+func Signature(secret []byte, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignRequest sets the signature header of a request with body. Without a
+// secret the request stays unsigned.
+func SignRequest(req *http.Request, body []byte, secret []byte) {
+	if len(secret) == 0 {
+		return
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set(SignatureHeader, fmt.Sprintf("t=%d,v1=%s", timestamp, Signature(secret, timestamp, body)))
+}
+
 // https://github.com/open-telemetry/opentelemetry-go-contrib/blob/main/instrumentation/net/http/httptrace/otelhttptrace/example/client/client.go
 
 func InstrumentedTransport(transport http.RoundTripper) *otelhttp.Transport {
@@ -1,6 +1,7 @@
 package remoteutil
 
 import (
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -226,6 +227,23 @@ func (errReader) Read([]byte) (int, error) {
 	return 0, io.ErrUnexpectedEOF
 }
 
+func TestSignRequest(t *testing.T) {
+	body := []byte(`{"job":{"ID":"test-job"}}`)
+
+	unsigned := &http.Request{Header: http.Header{}}
+	SignRequest(unsigned, body, nil)
+	assert.Empty(t, unsigned.Header.Get(SignatureHeader))
+
+	signed := &http.Request{Header: http.Header{}}
+	SignRequest(signed, body, []byte("secret"))
+	var timestamp int64
+	var signature string
+	_, err := fmt.Sscanf(signed.Header.Get(SignatureHeader), "t=%d,v1=%s", &timestamp, &signature)
+	require.NoError(t, err)
+	assert.Equal(t, Signature([]byte("secret"), timestamp, body), signature)
+	assert.NotEqual(t, Signature([]byte("other"), timestamp, body), signature)
+}
+
 func TestNewInstrumentedClient(t *testing.T) {
 	client := NewInstrumentedClient()
 	require.NotNil(t, client)
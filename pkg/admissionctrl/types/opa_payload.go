@@ -6,6 +6,38 @@ import (
 )
 
 type Payload struct {
-	Job     *api.Job               `json:"job"`
-	Context *config.RequestContext `json:"context,omitempty"`
+	Job        *api.Job               `json:"job"`
+	Context    *config.RequestContext `json:"context,omitempty"`
+	Operation  string                 `json:"operation,omitempty"`
+	Dispatch   *Dispatch              `json:"dispatch,omitempty"`
+	Deregister *Deregister            `json:"deregister,omitempty"`
+	Scale      *Scale                 `json:"scale,omitempty"`
+}
+
+// Dispatch describes a dispatch of a parameterized job. The payload's Job is
+// the parent job as currently registered in Nomad.
+type Dispatch struct {
+	Meta             map[string]string `json:"meta,omitempty"`
+	PayloadSize      int               `json:"payloadSize"`
+	IdPrefixTemplate string            `json:"idPrefixTemplate,omitempty"`
+	Priority         int               `json:"priority,omitempty"`
+}
+
+// Deregister holds the flags of a job deregistration. The payload's Job is the
+// job about to be stopped.
+type Deregister struct {
+	Purge  bool `json:"purge"`
+	Global bool `json:"global"`
+}
+
+// Scale describes a scaling request for a task group. The payload's Job is the
+// current job with the group's Count already set to the requested count, so
+// mutators can adjust the count by patching that group.
+type Scale struct {
+	Group         string             `json:"group"`
+	Count         int                `json:"count"`
+	PreviousCount int                `json:"previousCount"`
+	Scaling       *api.ScalingPolicy `json:"scaling,omitempty"`
+	Message       string             `json:"message,omitempty"`
+	Meta          map[string]any     `json:"meta,omitempty"`
 }
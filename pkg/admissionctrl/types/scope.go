@@ -0,0 +1,33 @@
+package types
+
+import "path"
+
+// Scope restricts a controller to the task groups and tasks whose names match
+// one of the glob patterns, as understood by path.Match. Empty lists and a
+// nil scope match everything.
+type Scope struct {
+	TaskGroups []string
+	Tasks      []string
+}
+
+// MatchesGroup reports whether the task group is in scope.
+func (s *Scope) MatchesGroup(name string) bool {
+	return s == nil || matchesAny(s.TaskGroups, name)
+}
+
+// MatchesTask reports whether the task is in scope.
+func (s *Scope) MatchesTask(name string) bool {
+	return s == nil || matchesAny(s.Tasks, name)
+}
+
+func matchesAny(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Violation is a policy error or warning. Policies and webhooks may report
+// plain strings, which become violations with just a Message, or objects
+// carrying the fields below.
+type Violation struct {
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+	// Path is a JSON pointer to the offending field of the job, e.g.
+	// /TaskGroups/0/Tasks/1/Resources/MemoryMB.
+	Path           string `json:"path,omitempty"`
+	Severity       string `json:"severity,omitempty"`
+	RemediationURL string `json:"remediation_url,omitempty"`
+}
+
+// Error formats the violation as "[code] path: message", leaving out the parts
+// that are not set, so plain string results read as before.
+func (v *Violation) Error() string {
+	message := v.Message
+	if v.Path != "" {
+		message = fmt.Sprintf("%s: %s", v.Path, message)
+	}
+	if v.Code != "" {
+		message = fmt.Sprintf("[%s] %s", v.Code, message)
+	}
+	return message
+}
+
+// UnmarshalJSON accepts a plain string or a violation object.
+func (v *Violation) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		*v = Violation{}
+		return json.Unmarshal(data, &v.Message)
+	}
+	type plain Violation
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.Message == "" {
+		return errors.New("violation without message")
+	}
+	*v = Violation(decoded)
+	return nil
+}
+
+// ViolationList is a list of violations in a webhook response. A null entry
+// makes the response malformed rather than a nil violation.
+type ViolationList []*Violation
+
+func (l *ViolationList) UnmarshalJSON(data []byte) error {
+	var violations []*Violation
+	if err := json.Unmarshal(data, &violations); err != nil {
+		return err
+	}
+	for i, violation := range violations {
+		if violation == nil {
+			return fmt.Errorf("violation %d is null", i)
+		}
+	}
+	*l = violations
+	return nil
+}
+
+// NewViolation converts a decoded policy result entry, either a string or an
+// object, into a violation.
+func NewViolation(entry interface{}) (*Violation, error) {
+	switch value := entry.(type) {
+	case string:
+		return &Violation{Message: value}, nil
+	case map[string]interface{}:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		violation := &Violation{}
+		if err := json.Unmarshal(data, violation); err != nil {
+			return nil, fmt.Errorf("invalid violation %v: %w", value, err)
+		}
+		return violation, nil
+	default:
+		return nil, fmt.Errorf("invalid violation %v: expected a string or an object", entry)
+	}
+}
+
+// PolicyViolation converts a policy result entry like NewViolation, but an
+// entry that is neither a string nor a violation object still becomes a
+// violation with the entry as message, so a broken deny rule keeps rejecting
+// instead of failing open.
+func PolicyViolation(entry interface{}) *Violation {
+	violation, err := NewViolation(entry)
+	if err != nil {
+		return &Violation{Message: fmt.Sprintf("%v", entry)}
+	}
+	return violation
+}
+
+// DecodeResult turns the warnings and errors a remote controller answered
+// with into the warnings and error controllers return. The errors become a
+// single *multierror.Error, so they count as a rejection.
+func DecodeResult(warnings, errs []*Violation) ([]error, error) {
+	var decoded []error
+	for _, warning := range warnings {
+		decoded = append(decoded, warning)
+	}
+	var rejection error
+	for _, violation := range errs {
+		rejection = multierror.Append(rejection, violation)
+	}
+	return decoded, rejection
+}
+
+// Violations returns the violations among errs, looking through wrapping.
+func Violations(errs []error) []*Violation {
+	var violations []*Violation
+	for _, err := range errs {
+		var violation *Violation
+		if errors.As(err, &violation) {
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
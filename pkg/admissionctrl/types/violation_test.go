@@ -0,0 +1,65 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViolation_UnmarshalJSON(t *testing.T) {
+	var violations []*Violation
+	err := json.Unmarshal([]byte(`["plain", {"message": "too big", "code": "MEM001", "path": "/TaskGroups/0/Count", "severity": "high", "remediation_url": "https://example.com"}]`), &violations)
+	require.NoError(t, err)
+	assert.Equal(t, []*Violation{
+		{Message: "plain"},
+		{Message: "too big", Code: "MEM001", Path: "/TaskGroups/0/Count", Severity: "high", RemediationURL: "https://example.com"},
+	}, violations)
+
+	assert.EqualError(t, json.Unmarshal([]byte(`[{"code": "MEM001"}]`), &violations), "violation without message")
+}
+
+func TestViolationList_UnmarshalJSON(t *testing.T) {
+	var violations ViolationList
+	require.NoError(t, json.Unmarshal([]byte(`["plain"]`), &violations))
+	assert.Equal(t, ViolationList{{Message: "plain"}}, violations)
+
+	assert.EqualError(t, json.Unmarshal([]byte(`["plain", null]`), &violations), "violation 1 is null")
+}
+
+func TestViolation_Error(t *testing.T) {
+	assert.EqualError(t, &Violation{Message: "plain"}, "plain")
+	assert.EqualError(t, &Violation{Message: "too big", Path: "/TaskGroups/0/Count"}, "/TaskGroups/0/Count: too big")
+	assert.EqualError(t, &Violation{Message: "too big", Code: "MEM001", Path: "/TaskGroups/0/Count"}, "[MEM001] /TaskGroups/0/Count: too big")
+}
+
+func TestNewViolation(t *testing.T) {
+	violation, err := NewViolation(map[string]interface{}{"message": "too big", "path": "/Meta"})
+	require.NoError(t, err)
+	assert.Equal(t, &Violation{Message: "too big", Path: "/Meta"}, violation)
+
+	_, err = NewViolation(42)
+	assert.EqualError(t, err, "invalid violation 42: expected a string or an object")
+}
+
+func TestDecodeResult(t *testing.T) {
+	warning := &Violation{Message: "deprecated"}
+	first, second := &Violation{Message: "too big"}, &Violation{Message: "no owner", Code: "OWN"}
+
+	warnings, err := DecodeResult([]*Violation{warning}, []*Violation{first, second})
+	assert.Equal(t, []error{warning}, warnings)
+	var merr *multierror.Error
+	require.ErrorAs(t, err, &merr)
+	assert.Equal(t, []error{first, second}, merr.Errors)
+
+	warnings, err = DecodeResult(nil, nil)
+	assert.Empty(t, warnings)
+	assert.NoError(t, err)
+}
+
+func TestPolicyViolation(t *testing.T) {
+	assert.Equal(t, &Violation{Message: "too big", Code: "X1"}, PolicyViolation(map[string]interface{}{"message": "too big", "code": "X1"}))
+	assert.Equal(t, &Violation{Message: "42"}, PolicyViolation(42))
+}
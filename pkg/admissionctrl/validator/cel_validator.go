@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/cel-go/cel"
+	"github.com/hashicorp/go-multierror"
+	"github.com/mxab/nacp/pkg/admissionctrl"
+	"github.com/mxab/nacp/pkg/admissionctrl/celutil"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/config"
+)
+
+// CelValidator rejects jobs for which one of its CEL expressions is false.
+type CelValidator struct {
+	name        string
+	logger      *slog.Logger
+	validations []celValidation
+}
+
+type celValidation struct {
+	program cel.Program
+	message string
+}
+
+var _ admissionctrl.JobValidator = (*CelValidator)(nil)
+
+func NewCelValidator(name string, validations []config.CelValidation, logger *slog.Logger) (*CelValidator, error) {
+	compiled := make([]celValidation, 0, len(validations))
+	for _, validation := range validations {
+		program, err := celutil.CompileCondition(validation.Expression)
+		if err != nil {
+			return nil, err
+		}
+		message := validation.Message
+		if message == "" {
+			message = fmt.Sprintf("failed expression: %s", validation.Expression)
+		}
+		compiled = append(compiled, celValidation{program: program, message: message})
+	}
+	return &CelValidator{name: name, logger: logger, validations: compiled}, nil
+}
+
+func (v *CelValidator) Validate(ctx context.Context, payload *types.Payload) ([]error, error) {
+	input, err := celutil.Input(payload)
+	if err != nil {
+		return nil, err
+	}
+	var result error
+	for _, validation := range v.validations {
+		ok, err := celutil.EvalCondition(ctx, validation.program, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate expression: %w", err)
+		}
+		if !ok {
+			result = multierror.Append(result, &types.Violation{Message: validation.message})
+		}
+	}
+	if result != nil {
+		v.logger.DebugContext(ctx, "CEL validation failed", "rule", v.name, "errors", result)
+	}
+	return nil, result
+}
+
+func (v *CelValidator) Name() string {
+	return v.name
+}
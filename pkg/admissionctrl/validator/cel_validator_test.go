@@ -0,0 +1,94 @@
+package validator
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/config"
+	"github.com/mxab/nacp/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCelValidator(t *testing.T) {
+	job := testutil.BaseJob()
+	job.Meta = map[string]string{"owner": "team-a"}
+	job.TaskGroups = []*api.TaskGroup{{Name: config.Ptr("web"), Count: config.Ptr(12)}}
+
+	tests := []struct {
+		name        string
+		validations []config.CelValidation
+		payload     *types.Payload
+		wantErrs    []string
+		wantFailure string
+	}{
+		{
+			name:        "true expressions",
+			validations: []config.CelValidation{{Expression: `has(job.Meta.owner)`}, {Expression: `job.ID.startsWith("test-")`}},
+		},
+		{
+			name: "false expressions",
+			validations: []config.CelValidation{
+				{Expression: `job.TaskGroups.all(g, g.Count <= 10)`, Message: "groups must not run more than 10 allocations"},
+				{Expression: `"cost-center" in job.Meta`},
+			},
+			wantErrs: []string{"groups must not run more than 10 allocations", `failed expression: "cost-center" in job.Meta`},
+		},
+		{
+			name:        "context and operation",
+			validations: []config.CelValidation{{Expression: `operation == "register" && context.clientIP == "10.0.0.1"`}},
+			payload: &types.Payload{
+				Job:       job,
+				Operation: config.OperationRegister,
+				Context:   &config.RequestContext{ClientIP: "10.0.0.1"},
+			},
+		},
+		{
+			name:        "missing context is null",
+			validations: []config.CelValidation{{Expression: `context == null`}},
+		},
+		{
+			name:        "evaluation error",
+			validations: []config.CelValidation{{Expression: `job.Meta.missing == "x"`}},
+			wantFailure: "failed to evaluate expression",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			validator, err := NewCelValidator("cel", tc.validations, slog.New(slog.DiscardHandler))
+			require.NoError(t, err)
+			payload := tc.payload
+			if payload == nil {
+				payload = &types.Payload{Job: job}
+			}
+
+			warnings, err := validator.Validate(t.Context(), payload)
+			assert.Empty(t, warnings)
+			switch {
+			case tc.wantFailure != "":
+				assert.ErrorContains(t, err, tc.wantFailure)
+			case len(tc.wantErrs) > 0:
+				var rejection *multierror.Error
+				require.ErrorAs(t, err, &rejection)
+				var messages []string
+				for _, violation := range types.Violations(rejection.Errors) {
+					messages = append(messages, violation.Message)
+				}
+				assert.Equal(t, tc.wantErrs, messages)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewCelValidator(t *testing.T) {
+	_, err := NewCelValidator("cel", []config.CelValidation{{Expression: `job.ID +`}}, slog.New(slog.DiscardHandler))
+	assert.ErrorContains(t, err, "invalid expression")
+
+	_, err = NewCelValidator("cel", []config.CelValidation{{Expression: `1 + 1`}}, slog.New(slog.DiscardHandler))
+	assert.ErrorContains(t, err, "not bool")
+}
@@ -0,0 +1,67 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/mxab/nacp/pkg/admissionctrl"
+	"github.com/mxab/nacp/pkg/admissionctrl/executil"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+)
+
+// ExecValidator validates jobs with a command. The command gets the payload
+// webhooks get on stdin and answers with the same errors and warnings on
+// stdout, or rejects the job by exiting with a reject exit code.
+type ExecValidator struct {
+	name    string
+	logger  *slog.Logger
+	command *executil.Command
+}
+
+var _ admissionctrl.JobValidator = (*ExecValidator)(nil)
+
+func NewExecValidator(name string, options executil.Options, logger *slog.Logger) (*ExecValidator, error) {
+	command, err := executil.New(options)
+	if err != nil {
+		return nil, err
+	}
+	return &ExecValidator{name: name, logger: logger, command: command}, nil
+}
+
+func (v *ExecValidator) Validate(ctx context.Context, payload *types.Payload) ([]error, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	output, err := v.command.Call(ctx, data)
+	var rejection *executil.Rejection
+	if errors.As(err, &rejection) {
+		return nil, multierror.Append(nil, &types.Violation{Message: rejection.Message})
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := &validationWebhookResponse{}
+	if err := json.Unmarshal(output, result); err != nil {
+		return nil, fmt.Errorf("failed to decode exec command response: %w", err)
+	}
+
+	warnings, policyErr := types.DecodeResult(result.Warnings, result.Errors)
+	if policyErr != nil {
+		v.logger.Debug("validation errors", "errors", result.Errors, "rule", v.name)
+	}
+	return warnings, policyErr
+}
+
+func (v *ExecValidator) Name() string {
+	return v.name
+}
+
+// Close stops the persistent process, if there is one.
+func (v *ExecValidator) Close() error {
+	return v.command.Close()
+}
@@ -0,0 +1,115 @@
+package validator
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/mxab/nacp/pkg/admissionctrl/executil"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecValidator(t *testing.T) {
+	tt := []struct {
+		name         string
+		script       string
+		persistent   bool
+		wantWarnings []error
+		wantErr      string
+		wantRejected bool
+	}{
+		{
+			name:   "valid job",
+			script: `cat >/dev/null; echo '{}'`,
+		},
+		{
+			name:         "errors and warnings",
+			script:       `cat >/dev/null; echo '{"errors": ["job has no owner"], "warnings": ["job is deprecated"]}'`,
+			wantWarnings: []error{&types.Violation{Message: "job is deprecated"}},
+			wantErr:      "job has no owner",
+			wantRejected: true,
+		},
+		{
+			name:         "reject exit code",
+			script:       `cat >/dev/null; echo "job has no owner" >&2; exit 1`,
+			wantErr:      "job has no owner",
+			wantRejected: true,
+		},
+		{
+			name:    "other exit code",
+			script:  `cat >/dev/null; exit 2`,
+			wantErr: "exit status 2",
+		},
+		{
+			name:    "invalid response",
+			script:  `cat >/dev/null; echo 'not json'`,
+			wantErr: "failed to decode exec command response",
+		},
+		{
+			name:         "persistent",
+			script:       `while IFS= read -r line; do echo '{"warnings": ["checked"]}'; done`,
+			persistent:   true,
+			wantWarnings: []error{&types.Violation{Message: "checked"}},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			validator, err := NewExecValidator(tc.name, executil.Options{
+				Command:         "sh",
+				Args:            []string{"-c", tc.script},
+				RejectExitCodes: []int{1},
+				Persistent:      tc.persistent,
+			}, slog.New(slog.DiscardHandler))
+			require.NoError(t, err)
+			t.Cleanup(func() { validator.Close() })
+			assert.Equal(t, tc.name, validator.Name())
+
+			warnings, err := validator.Validate(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+
+			assert.Equal(t, tc.wantWarnings, warnings)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.wantErr)
+			var rejection *multierror.Error
+			assert.Equal(t, tc.wantRejected, errors.As(err, &rejection), "Only policy errors are a rejection")
+		})
+	}
+}
+
+func TestExecValidatorClose(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "pid")
+	validator, err := NewExecValidator("exec", executil.Options{
+		Command:    "sh",
+		Args:       []string{"-c", `echo $$ > "$PID_FILE"; while read -r line; do echo '{}'; done`},
+		Env:        map[string]string{"PID_FILE": pidFile},
+		Persistent: true,
+	}, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+
+	_, err = validator.Validate(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	require.NoError(t, err)
+	data, err := os.ReadFile(pidFile)
+	require.NoError(t, err)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	require.NoError(t, err)
+	require.NoError(t, syscall.Kill(pid, 0), "the persistent process is running")
+
+	require.NoError(t, validator.Close())
+	assert.ErrorIs(t, syscall.Kill(pid, 0), syscall.ESRCH, "the persistent process was stopped and reaped")
+}
+
+func TestNewExecValidatorMissingCommand(t *testing.T) {
+	_, err := NewExecValidator("exec", executil.Options{Command: "nacp-does-not-exist"}, slog.New(slog.DiscardHandler))
+	assert.ErrorContains(t, err, "not found")
+}
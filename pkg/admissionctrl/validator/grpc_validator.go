@@ -0,0 +1,51 @@
+package validator
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/mxab/nacp/pkg/admissionctrl"
+	"github.com/mxab/nacp/pkg/admissionctrl/grpcutil"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/grpc/admissionpb"
+)
+
+// GrpcValidator validates jobs with the Validate call of an Admission gRPC
+// server, the gRPC counterpart of the WebhookValidator.
+type GrpcValidator struct {
+	name   string
+	logger *slog.Logger
+	client *grpcutil.Client
+}
+
+var _ admissionctrl.JobValidator = (*GrpcValidator)(nil)
+
+func NewGrpcValidator(name string, options grpcutil.Options, logger *slog.Logger) (*GrpcValidator, error) {
+	client, err := grpcutil.NewClient(options)
+	if err != nil {
+		return nil, err
+	}
+	return &GrpcValidator{name: name, logger: logger, client: client}, nil
+}
+
+func (v *GrpcValidator) Validate(ctx context.Context, payload *types.Payload) ([]error, error) {
+	response, err := v.client.Validate(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings, policyErr := types.DecodeResult(admissionpb.ToViolations(response.GetWarnings()), admissionpb.ToViolations(response.GetErrors()))
+	if policyErr != nil {
+		v.logger.Debug("validation errors", "errors", response.GetErrors(), "rule", v.name)
+	}
+	return warnings, policyErr
+}
+
+func (v *GrpcValidator) Name() string {
+	return v.name
+}
+
+// Close closes the connection to the server.
+func (v *GrpcValidator) Close() error {
+	return v.client.Close()
+}
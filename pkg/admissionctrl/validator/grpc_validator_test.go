@@ -0,0 +1,87 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/mxab/nacp/pkg/admissionctrl/grpcutil"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/grpc/server"
+	"github.com/mxab/nacp/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrpcValidator(t *testing.T) {
+	address := testutil.StartAdmissionServer(t, &server.Service{
+		Validator: func(ctx context.Context, payload *types.Payload) (*server.ValidateResult, error) {
+			switch payload.Job.Meta["case"] {
+			case "reject":
+				return &server.ValidateResult{
+					Errors:   []*types.Violation{{Message: "job has no owner", Code: "OWNER"}},
+					Warnings: []*types.Violation{{Message: "careful"}},
+				}, nil
+			case "fail":
+				return nil, errors.New("backend down")
+			case "warn":
+				return &server.ValidateResult{Warnings: []*types.Violation{{Message: "careful"}}}, nil
+			}
+			return nil, nil
+		},
+	}, nil)
+	validator, err := NewGrpcValidator("grpc", grpcutil.Options{Address: address}, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	assert.Equal(t, "grpc", validator.Name())
+
+	tt := []struct {
+		name         string
+		meta         map[string]string
+		wantWarnings []error
+		wantErr      string
+		wantRejected bool
+	}{
+		{
+			name: "valid job",
+		},
+		{
+			name:         "warnings",
+			meta:         map[string]string{"case": "warn"},
+			wantWarnings: []error{&types.Violation{Message: "careful"}},
+		},
+		{
+			name:         "errors",
+			meta:         map[string]string{"case": "reject"},
+			wantWarnings: []error{&types.Violation{Message: "careful"}},
+			wantErr:      "[OWNER] job has no owner",
+			wantRejected: true,
+		},
+		{
+			name:    "server failure",
+			meta:    map[string]string{"case": "fail"},
+			wantErr: "backend down",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			job := testutil.BaseJob()
+			job.Meta = tc.meta
+			warnings, err := validator.Validate(t.Context(), &types.Payload{Job: job})
+
+			assert.Equal(t, tc.wantWarnings, warnings)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.wantErr)
+			var rejection *multierror.Error
+			assert.Equal(t, tc.wantRejected, errors.As(err, &rejection), "Only policy errors are a rejection")
+		})
+	}
+
+	require.NoError(t, validator.Close())
+	_, err = validator.Validate(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	assert.ErrorContains(t, err, "the client connection is closing")
+}
@@ -0,0 +1,109 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/mxab/nacp/pkg/admissionctrl"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+var schemaMessages = message.NewPrinter(language.English)
+
+// JSONSchemaValidator rejects jobs, or whole payloads, that do not satisfy
+// its JSON schemas. Every failed keyword is reported as its own error.
+type JSONSchemaValidator struct {
+	name    string
+	logger  *slog.Logger
+	schemas []*jsonschema.Schema
+	payload bool
+}
+
+var _ admissionctrl.JobValidator = (*JSONSchemaValidator)(nil)
+
+// NewJSONSchemaValidator compiles the schema files. Schemas without a
+// $schema keyword are read as draft 2020-12.
+func NewJSONSchemaValidator(name string, files []string, payload bool, logger *slog.Logger) (*JSONSchemaValidator, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	schemas := make([]*jsonschema.Schema, 0, len(files))
+	for _, file := range files {
+		schema, err := compiler.Compile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile schema %q: %w", file, err)
+		}
+		schemas = append(schemas, schema)
+	}
+	return &JSONSchemaValidator{name: name, logger: logger, schemas: schemas, payload: payload}, nil
+}
+
+func (v *JSONSchemaValidator) Validate(ctx context.Context, payload *types.Payload) ([]error, error) {
+	var document interface{} = payload.Job
+	if v.payload {
+		document = payload
+	}
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var result error
+	for _, schema := range v.schemas {
+		err := schema.Validate(instance)
+		if err == nil {
+			continue
+		}
+		var validationErr *jsonschema.ValidationError
+		if !errors.As(err, &validationErr) {
+			return nil, fmt.Errorf("failed to validate against schema %s: %w", schema.Location, err)
+		}
+		for _, violation := range schemaViolations(validationErr) {
+			result = multierror.Append(result, violation)
+		}
+	}
+	if result != nil {
+		v.logger.DebugContext(ctx, "JSON schema validation failed", "rule", v.name, "errors", result)
+	}
+	return nil, result
+}
+
+func (v *JSONSchemaValidator) Name() string {
+	return v.name
+}
+
+// schemaViolations flattens a validation error into its failed keywords.
+func schemaViolations(err *jsonschema.ValidationError) []*types.Violation {
+	if len(err.Causes) == 0 {
+		return []*types.Violation{{
+			Message: err.ErrorKind.LocalizedString(schemaMessages),
+			Path:    jsonPointer(err.InstanceLocation),
+		}}
+	}
+	var violations []*types.Violation
+	for _, cause := range err.Causes {
+		violations = append(violations, schemaViolations(cause)...)
+	}
+	return violations
+}
+
+func jsonPointer(tokens []string) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteByte('/')
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return sb.String()
+}
@@ -0,0 +1,76 @@
+package validator
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/config"
+	"github.com/mxab/nacp/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSchemaValidator(t *testing.T) {
+	conventions := testutil.Filepath(t, "jsonschema/conventions.json")
+	payloadSchema := testutil.Filepath(t, "jsonschema/payload.json")
+
+	tests := []struct {
+		name    string
+		files   []string
+		payload bool
+		job     *api.Job
+		wantErr []*types.Violation
+	}{
+		{
+			name:  "valid job",
+			files: []string{conventions},
+			job:   &api.Job{ID: config.Ptr("web"), Type: config.Ptr("service"), Meta: map[string]string{"owner": "team-a"}},
+		},
+		{
+			name:  "every failed keyword is reported",
+			files: []string{conventions},
+			job:   &api.Job{ID: config.Ptr("Web_1"), Type: config.Ptr("system"), Meta: map[string]string{}},
+			wantErr: []*types.Violation{
+				{Message: "value must be one of 'service', 'batch', <nil>", Path: "/Type"},
+				{Message: "missing property 'owner'", Path: "/Meta"},
+				{Message: "'Web_1' does not match pattern '^[a-z][a-z0-9-]*$'", Path: "/ID"},
+			},
+		},
+		{
+			name:    "whole payload",
+			files:   []string{payloadSchema},
+			payload: true,
+			job:     testutil.BaseJob(),
+			wantErr: []*types.Violation{
+				{Message: "value must be 'register'", Path: "/operation"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			validator, err := NewJSONSchemaValidator("schema", tc.files, tc.payload, slog.New(slog.DiscardHandler))
+			require.NoError(t, err)
+
+			warnings, err := validator.Validate(t.Context(), &types.Payload{Job: tc.job, Operation: config.OperationPlan})
+			assert.Empty(t, warnings)
+			if len(tc.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var rejection *multierror.Error
+			require.ErrorAs(t, err, &rejection)
+			assert.ElementsMatch(t, tc.wantErr, types.Violations(rejection.Errors))
+		})
+	}
+}
+
+func TestNewJSONSchemaValidator(t *testing.T) {
+	_, err := NewJSONSchemaValidator("schema", []string{testutil.Filepath(t, "jsonschema/invalid.json")}, false, slog.New(slog.DiscardHandler))
+	assert.ErrorContains(t, err, "failed to compile schema")
+
+	_, err = NewJSONSchemaValidator("schema", []string{testutil.Filepath(t, "jsonschema/missing.json")}, false, slog.New(slog.DiscardHandler))
+	assert.Error(t, err)
+}
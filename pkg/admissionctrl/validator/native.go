@@ -0,0 +1,254 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/config"
+)
+
+// NativeValidator checks jobs against a built-in guardrail. Its violations
+// carry the validator type as code and point at the offending field.
+type NativeValidator struct {
+	name    string
+	message string
+	check   func(job *api.Job) []*types.Violation
+}
+
+var _ admissionctrl.JobValidator = (*NativeValidator)(nil)
+
+func (v *NativeValidator) Validate(_ context.Context, payload *types.Payload) ([]error, error) {
+	if payload.Job == nil {
+		return nil, nil
+	}
+	var result error
+	for _, violation := range v.check(payload.Job) {
+		if v.message != "" {
+			violation.Message = v.message
+		}
+		result = multierror.Append(result, violation)
+	}
+	return nil, result
+}
+
+func (v *NativeValidator) Name() string {
+	return v.name
+}
+
+// NewResourceLimitsValidator rejects tasks in scope requesting more resources
+// than the limits. Unset limits and unset task resources are not checked.
+func NewResourceLimitsValidator(name string, limits *api.Resources, scope *types.Scope, message string) *NativeValidator {
+	type limit struct {
+		field string
+		get   func(*api.Resources) *int
+	}
+	checked := []limit{
+		{"CPU", func(r *api.Resources) *int { return r.CPU }},
+		{"Cores", func(r *api.Resources) *int { return r.Cores }},
+		{"MemoryMB", func(r *api.Resources) *int { return r.MemoryMB }},
+		{"MemoryMaxMB", func(r *api.Resources) *int { return r.MemoryMaxMB }},
+	}
+	return &NativeValidator{name: name, message: message, check: func(job *api.Job) []*types.Violation {
+		var violations []*types.Violation
+		eachTask(job, scope, func(path string, group *api.TaskGroup, task *api.Task) {
+			if task.Resources == nil {
+				return
+			}
+			for _, l := range checked {
+				ceiling, requested := l.get(limits), l.get(task.Resources)
+				if ceiling == nil || requested == nil || *requested <= *ceiling {
+					continue
+				}
+				violations = append(violations, &types.Violation{
+					Message: fmt.Sprintf("%s requests %s %d, more than the limit of %d", describeTask(group, task), l.field, *requested, *ceiling),
+					Code:    "resource_limits",
+					Path:    path + "/Resources/" + l.field,
+				})
+			}
+		})
+		return violations
+	}}
+}
+
+// NewAllowedDriversValidator rejects tasks in scope using other drivers.
+func NewAllowedDriversValidator(name string, drivers []string, scope *types.Scope, message string) *NativeValidator {
+	return &NativeValidator{name: name, message: message, check: func(job *api.Job) []*types.Violation {
+		var violations []*types.Violation
+		eachTask(job, scope, func(path string, group *api.TaskGroup, task *api.Task) {
+			if slices.Contains(drivers, task.Driver) {
+				return
+			}
+			violations = append(violations, &types.Violation{
+				Message: fmt.Sprintf("%s uses driver %q, allowed drivers are %s", describeTask(group, task), task.Driver, strings.Join(drivers, ", ")),
+				Code:    "allowed_drivers",
+				Path:    path + "/Driver",
+			})
+		})
+		return violations
+	}}
+}
+
+// NewRequiredMetaValidator rejects jobs, task groups or tasks missing meta
+// keys. Like in Nomad, groups inherit the job's meta and tasks their group's.
+func NewRequiredMetaValidator(name string, keys []string, target string, scope *types.Scope, message string) *NativeValidator {
+	return &NativeValidator{name: name, message: message, check: func(job *api.Job) []*types.Violation {
+		var violations []*types.Violation
+		require := func(path, subject string, metas ...map[string]string) {
+			for _, key := range keys {
+				if !slices.ContainsFunc(metas, func(meta map[string]string) bool { _, ok := meta[key]; return ok }) {
+					violations = append(violations, &types.Violation{
+						Message: fmt.Sprintf("%s is missing the required meta key %q", subject, key),
+						Code:    "required_meta",
+						Path:    path + "/Meta",
+					})
+				}
+			}
+		}
+		switch target {
+		case config.TargetGroup:
+			eachGroup(job, scope, func(path string, group *api.TaskGroup) {
+				require(path, describeGroup(group), job.Meta, group.Meta)
+			})
+		case config.TargetTask:
+			eachTask(job, scope, func(path string, group *api.TaskGroup, task *api.Task) {
+				require(path, describeTask(group, task), job.Meta, group.Meta, task.Meta)
+			})
+		default:
+			require("", "job", job.Meta)
+		}
+		return violations
+	}}
+}
+
+// NewImageRegistryValidator rejects docker and podman tasks in scope whose
+// image is not from one of the registries. A registry may include a
+// repository prefix, e.g. ghcr.io/my-org; images without a registry are
+// from docker.io.
+func NewImageRegistryValidator(name string, registries []string, scope *types.Scope, message string) *NativeValidator {
+	return &NativeValidator{name: name, message: message, check: func(job *api.Job) []*types.Violation {
+		var violations []*types.Violation
+		eachContainerTask(job, scope, func(path string, group *api.TaskGroup, task *api.Task) {
+			image, ok := task.Config["image"].(string)
+			if !ok {
+				return
+			}
+			reference := normalizeImage(image)
+			allowed := slices.ContainsFunc(registries, func(registry string) bool {
+				registry = strings.TrimSuffix(registry, "/")
+				return strings.HasPrefix(reference, registry+"/")
+			})
+			if allowed {
+				return
+			}
+			violations = append(violations, &types.Violation{
+				Message: fmt.Sprintf("%s uses image %q, allowed registries are %s", describeTask(group, task), image, strings.Join(registries, ", ")),
+				Code:    "image_registries",
+				Path:    path + "/Config/image",
+			})
+		})
+		return violations
+	}}
+}
+
+// NewPrivilegedValidator rejects docker and podman tasks in scope running
+// privileged containers.
+func NewPrivilegedValidator(name string, scope *types.Scope, message string) *NativeValidator {
+	return &NativeValidator{name: name, message: message, check: func(job *api.Job) []*types.Violation {
+		var violations []*types.Violation
+		eachContainerTask(job, scope, func(path string, group *api.TaskGroup, task *api.Task) {
+			if privileged, _ := task.Config["privileged"].(bool); !privileged {
+				return
+			}
+			violations = append(violations, &types.Violation{
+				Message: fmt.Sprintf("%s runs a privileged container", describeTask(group, task)),
+				Code:    "forbid_privileged",
+				Path:    path + "/Config/privileged",
+			})
+		})
+		return violations
+	}}
+}
+
+// NewMaxParallelValidator rejects update blocks of the job and of the task
+// groups in scope updating more than maxParallel allocations at a time.
+func NewMaxParallelValidator(name string, maxParallel int, scope *types.Scope, message string) *NativeValidator {
+	return &NativeValidator{name: name, message: message, check: func(job *api.Job) []*types.Violation {
+		var violations []*types.Violation
+		check := func(path, subject string, update *api.UpdateStrategy) {
+			if update == nil || update.MaxParallel == nil || *update.MaxParallel <= maxParallel {
+				return
+			}
+			violations = append(violations, &types.Violation{
+				Message: fmt.Sprintf("%s updates %d allocations at a time, more than the limit of %d", subject, *update.MaxParallel, maxParallel),
+				Code:    "max_parallel",
+				Path:    path + "/Update/MaxParallel",
+			})
+		}
+		check("", "job", job.Update)
+		eachGroup(job, scope, func(path string, group *api.TaskGroup) {
+			check(path, describeGroup(group), group.Update)
+		})
+		return violations
+	}}
+}
+
+func eachGroup(job *api.Job, scope *types.Scope, fn func(path string, group *api.TaskGroup)) {
+	for i, group := range job.TaskGroups {
+		if group != nil && scope.MatchesGroup(groupName(group)) {
+			fn(fmt.Sprintf("/TaskGroups/%d", i), group)
+		}
+	}
+}
+
+func eachTask(job *api.Job, scope *types.Scope, fn func(path string, group *api.TaskGroup, task *api.Task)) {
+	eachGroup(job, scope, func(groupPath string, group *api.TaskGroup) {
+		for i, task := range group.Tasks {
+			if task != nil && scope.MatchesTask(task.Name) {
+				fn(fmt.Sprintf("%s/Tasks/%d", groupPath, i), group, task)
+			}
+		}
+	})
+}
+
+func eachContainerTask(job *api.Job, scope *types.Scope, fn func(path string, group *api.TaskGroup, task *api.Task)) {
+	eachTask(job, scope, func(path string, group *api.TaskGroup, task *api.Task) {
+		if task.Driver == "docker" || task.Driver == "podman" {
+			fn(path, group, task)
+		}
+	})
+}
+
+// normalizeImage returns the image reference with its registry, the way
+// docker resolves it, e.g. docker.io/library/redis for redis.
+func normalizeImage(image string) string {
+	image = strings.TrimPrefix(image, "docker://")
+	first, _, found := strings.Cut(image, "/")
+	if !found {
+		return "docker.io/library/" + image
+	}
+	if !strings.ContainsAny(first, ".:") && first != "localhost" {
+		return "docker.io/" + image
+	}
+	return image
+}
+
+func groupName(group *api.TaskGroup) string {
+	if group.Name == nil {
+		return ""
+	}
+	return *group.Name
+}
+
+func describeGroup(group *api.TaskGroup) string {
+	return fmt.Sprintf("group %q", groupName(group))
+}
+
+func describeTask(group *api.TaskGroup, task *api.Task) string {
+	return fmt.Sprintf("task %q of group %q", task.Name, groupName(group))
+}
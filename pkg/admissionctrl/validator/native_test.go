@@ -0,0 +1,153 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/config"
+	"github.com/mxab/nacp/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func guardrailJob() *api.Job {
+	job := testutil.BaseJob()
+	job.Meta = map[string]string{"owner": "team-a"}
+	job.Update = &api.UpdateStrategy{MaxParallel: config.Ptr(1)}
+	job.TaskGroups = []*api.TaskGroup{
+		{
+			Name:   config.Ptr("web"),
+			Update: &api.UpdateStrategy{MaxParallel: config.Ptr(5)},
+			Tasks: []*api.Task{
+				{
+					Name:      "app",
+					Driver:    "docker",
+					Config:    map[string]interface{}{"image": "registry.example.com/web/app:1.0"},
+					Resources: &api.Resources{CPU: config.Ptr(500), MemoryMB: config.Ptr(2048)},
+				},
+				{
+					Name:   "proxy",
+					Driver: "docker",
+					Config: map[string]interface{}{"image": "envoyproxy/envoy:v1.30", "privileged": true},
+					Meta:   map[string]string{"tier": "edge"},
+				},
+			},
+		},
+		{
+			Name:  config.Ptr("batch"),
+			Tasks: []*api.Task{{Name: "run", Driver: "raw_exec"}},
+		},
+	}
+	return job
+}
+
+func TestNativeValidators(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator *NativeValidator
+		want      []*types.Violation
+	}{
+		{
+			name:      "resource limits",
+			validator: NewResourceLimitsValidator("limits", &api.Resources{CPU: config.Ptr(1000), MemoryMB: config.Ptr(1024)}, nil, ""),
+			want: []*types.Violation{{
+				Message: `task "app" of group "web" requests MemoryMB 2048, more than the limit of 1024`,
+				Code:    "resource_limits",
+				Path:    "/TaskGroups/0/Tasks/0/Resources/MemoryMB",
+			}},
+		},
+		{
+			name:      "allowed drivers",
+			validator: NewAllowedDriversValidator("drivers", []string{"docker", "exec"}, nil, ""),
+			want: []*types.Violation{{
+				Message: `task "run" of group "batch" uses driver "raw_exec", allowed drivers are docker, exec`,
+				Code:    "allowed_drivers",
+				Path:    "/TaskGroups/1/Tasks/0/Driver",
+			}},
+		},
+		{
+			name:      "allowed drivers in scope",
+			validator: NewAllowedDriversValidator("drivers", []string{"docker"}, &types.Scope{TaskGroups: []string{"web"}}, ""),
+		},
+		{
+			name:      "required job meta",
+			validator: NewRequiredMetaValidator("meta", []string{"owner", "cost-center"}, "", nil, ""),
+			want: []*types.Violation{{
+				Message: `job is missing the required meta key "cost-center"`,
+				Code:    "required_meta",
+				Path:    "/Meta",
+			}},
+		},
+		{
+			name:      "required task meta is inherited",
+			validator: NewRequiredMetaValidator("meta", []string{"owner", "tier"}, config.TargetTask, &types.Scope{TaskGroups: []string{"web"}}, ""),
+			want: []*types.Violation{{
+				Message: `task "app" of group "web" is missing the required meta key "tier"`,
+				Code:    "required_meta",
+				Path:    "/TaskGroups/0/Tasks/0/Meta",
+			}},
+		},
+		{
+			name:      "image registries",
+			validator: NewImageRegistryValidator("registries", []string{"registry.example.com"}, nil, ""),
+			want: []*types.Violation{{
+				Message: `task "proxy" of group "web" uses image "envoyproxy/envoy:v1.30", allowed registries are registry.example.com`,
+				Code:    "image_registries",
+				Path:    "/TaskGroups/0/Tasks/1/Config/image",
+			}},
+		},
+		{
+			name:      "image registries with docker hub repository",
+			validator: NewImageRegistryValidator("registries", []string{"registry.example.com/web", "docker.io/envoyproxy/"}, nil, ""),
+		},
+		{
+			name:      "forbid privileged",
+			validator: NewPrivilegedValidator("privileged", nil, ""),
+			want: []*types.Violation{{
+				Message: `task "proxy" of group "web" runs a privileged container`,
+				Code:    "forbid_privileged",
+				Path:    "/TaskGroups/0/Tasks/1/Config/privileged",
+			}},
+		},
+		{
+			name:      "max parallel",
+			validator: NewMaxParallelValidator("parallel", 2, nil, ""),
+			want: []*types.Violation{{
+				Message: `group "web" updates 5 allocations at a time, more than the limit of 2`,
+				Code:    "max_parallel",
+				Path:    "/TaskGroups/0/Update/MaxParallel",
+			}},
+		},
+		{
+			name:      "custom message",
+			validator: NewMaxParallelValidator("parallel", 0, nil, "use canary deployments instead"),
+			want: []*types.Violation{
+				{Message: "use canary deployments instead", Code: "max_parallel", Path: "/Update/MaxParallel"},
+				{Message: "use canary deployments instead", Code: "max_parallel", Path: "/TaskGroups/0/Update/MaxParallel"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings, err := tc.validator.Validate(t.Context(), &types.Payload{Job: guardrailJob()})
+			assert.Empty(t, warnings)
+			if len(tc.want) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var rejection *multierror.Error
+			require.ErrorAs(t, err, &rejection)
+			assert.Equal(t, tc.want, types.Violations(rejection.Errors))
+		})
+	}
+}
+
+func TestNormalizeImage(t *testing.T) {
+	assert.Equal(t, "docker.io/library/redis:7", normalizeImage("redis:7"))
+	assert.Equal(t, "docker.io/grafana/grafana", normalizeImage("grafana/grafana"))
+	assert.Equal(t, "ghcr.io/org/app", normalizeImage("ghcr.io/org/app"))
+	assert.Equal(t, "localhost/app", normalizeImage("localhost/app"))
+	assert.Equal(t, "registry:5000/app", normalizeImage("docker://registry:5000/app"))
+}
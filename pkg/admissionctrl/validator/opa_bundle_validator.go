@@ -81,11 +81,7 @@ func parseBundleMessages(raw interface{}, kind string) ([]error, error) {
 
 	messages := make([]error, 0, len(entries))
 	for _, entry := range entries {
-		message, ok := entry.(string)
-		if !ok {
-			return nil, fmt.Errorf("policy yielded an invalid %s value: %v", kind, entry)
-		}
-		messages = append(messages, errors.New(message))
+		messages = append(messages, types.PolicyViolation(entry))
 	}
 	return messages, nil
 }
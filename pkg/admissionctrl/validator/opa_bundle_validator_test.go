@@ -4,6 +4,7 @@ import (
 	"log/slog"
 	"testing"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/mxab/nacp/pkg/admissionctrl/types"
 	"github.com/mxab/nacp/testutil"
 	"github.com/stretchr/testify/assert"
@@ -63,13 +64,6 @@ func TestOpaBundleValidator(t *testing.T) {
 			path:           "/mypolicy",
 			expectErrParts: []string{"policy yielded an invalid errors value"},
 		},
-		{
-			name: "handle invalid error value",
-			policy: `package mypolicy
-			errors = [5]`,
-			path:           "/mypolicy",
-			expectErrParts: []string{"policy yielded an invalid error value"},
-		},
 		{
 			name: "handle invalid warnings collection",
 			policy: `package mypolicy
@@ -77,13 +71,6 @@ func TestOpaBundleValidator(t *testing.T) {
 			path:           "/mypolicy",
 			expectErrParts: []string{"policy yielded an invalid warnings value"},
 		},
-		{
-			name: "handle invalid warning entry",
-			policy: `package mypolicy
-				warnings = [5]`,
-			path:           "/mypolicy",
-			expectErrParts: []string{"policy yielded an invalid warning value"},
-		},
 		{
 			name: "reject non-object decision",
 			policy: `package mypolicy
@@ -129,6 +116,25 @@ func TestOpaBundleValidator(t *testing.T) {
 	}
 }
 
+func TestOpaBundleValidatorMalformedResults(t *testing.T) {
+	opa := testutil.SetupOpa(t, `package mypolicy
+		errors = ["fine", 5, {"code": "X1"}]
+		warnings = [true]`)
+	validator, err := NewOpaBundleValidator("testopabundlevalidator", "/mypolicy", slog.New(slog.DiscardHandler), opa)
+	require.NoError(t, err)
+
+	warnings, err := validator.Validate(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+
+	assert.Equal(t, []error{&types.Violation{Message: "true"}}, warnings)
+	var merr *multierror.Error
+	require.ErrorAs(t, err, &merr, "malformed entries are rejections, not controller failures")
+	assert.Equal(t, []error{
+		&types.Violation{Message: "fine"},
+		&types.Violation{Message: "5"},
+		&types.Violation{Message: "map[code:X1]"},
+	}, merr.Errors)
+}
+
 func TestBundleValidatorName(t *testing.T) {
 	opa := testutil.SetupOpa(t, "package mypolicy")
 	validator, err := NewOpaBundleValidator("testopabundlevalidator", "/mypolicy", slog.New(slog.DiscardHandler), opa)
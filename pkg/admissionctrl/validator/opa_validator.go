@@ -2,7 +2,6 @@ package validator
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 
 	"github.com/hashicorp/go-multierror"
@@ -38,7 +37,8 @@ func (v *OpaValidator) Validate(ctx context.Context, payload *types.Payload) ([]
 	if len(warnings) > 0 {
 		v.logger.Debug("Got warnings from rule", "rule", v.Name(), "warnings", warnings, "job", payload.Job.ID)
 		for _, warn := range warnings {
-			allWarnings = append(allWarnings, fmt.Errorf("%s (%s)", warn, v.Name()))
+			violation := opa.RuleViolation(warn, v.Name())
+			allWarnings = append(allWarnings, violation)
 		}
 	}
 
@@ -47,8 +47,9 @@ func (v *OpaValidator) Validate(ctx context.Context, payload *types.Payload) ([]
 	if len(errors) > 0 { // no errors is ok
 		v.logger.Debug("Got errors from rule", "rule", v.Name(), "errors", errors, "job", payload.Job.ID)
 		errsForRule := &multierror.Error{}
-		for _, err := range errors {
-			errsForRule = multierror.Append(errsForRule, fmt.Errorf("%s (%s)", err, v.Name()))
+		for _, entry := range errors {
+			violation := opa.RuleViolation(entry, v.Name())
+			errsForRule = multierror.Append(errsForRule, violation)
 		}
 		allErrs = multierror.Append(allErrs, errsForRule)
 	}
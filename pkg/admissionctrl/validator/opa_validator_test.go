@@ -7,6 +7,7 @@ import (
 	"github.com/mxab/nacp/pkg/admissionctrl/types"
 	"github.com/mxab/nacp/pkg/config"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/api"
 	"github.com/mxab/nacp/testutil"
 	"github.com/stretchr/testify/assert"
@@ -199,3 +200,64 @@ func TestOpaValidatorContext(t *testing.T) {
 		})
 	}
 }
+
+func TestOpaValidatorStructuredResults(t *testing.T) {
+	validator, err := NewOpaValidator(
+		"memory",
+		testutil.Filepath(t, "opa/validators/structured/structured.rego"),
+		`
+		errors = data.structured.errors
+		warnings = data.structured.warnings
+		`,
+		slog.New(slog.DiscardHandler),
+		nil,
+	)
+	require.NoError(t, err)
+
+	job := &api.Job{TaskGroups: []*api.TaskGroup{{
+		Tasks: []*api.Task{{Name: "web", Resources: &api.Resources{MemoryMB: new(int)}}},
+	}}}
+	*job.TaskGroups[0].Tasks[0].Resources.MemoryMB = 2048
+	warnings, err := validator.Validate(t.Context(), &types.Payload{Job: job})
+
+	assert.Equal(t, []error{&types.Violation{Message: "plain warnings still work (memory)"}}, warnings)
+	require.Error(t, err)
+	var merr *multierror.Error
+	require.ErrorAs(t, err, &merr)
+	assert.Equal(t, []error{&types.Violation{
+		Message:        "task web requests more than 1024 MB (memory)",
+		Code:           "MEM001",
+		Path:           "/TaskGroups/0/Tasks/0/Resources/MemoryMB",
+		Severity:       "high",
+		RemediationURL: "https://example.com/policies/memory",
+	}}, merr.Errors)
+	assert.EqualError(t, merr.Errors[0], "[MEM001] /TaskGroups/0/Tasks/0/Resources/MemoryMB: task web requests more than 1024 MB (memory)")
+}
+
+func TestOpaValidatorMalformedResults(t *testing.T) {
+	validator, err := NewOpaValidator(
+		"malformed",
+		testutil.Filepath(t, "opa/validators/malformed/malformed.rego"),
+		`
+		errors = data.malformed.errors
+		warnings = data.malformed.warnings
+		`,
+		slog.New(slog.DiscardHandler),
+		nil,
+	)
+	require.NoError(t, err)
+
+	job := testutil.BaseJob()
+	job.Meta = map[string]string{"broken": "true"}
+	warnings, err := validator.Validate(t.Context(), &types.Payload{Job: job})
+
+	// malformed entries are rejections, not controller failures, so a broken
+	// rule does not fail open under failure_policy = "ignore"
+	assert.Equal(t, []error{&types.Violation{Message: "[not a violation] (malformed)"}}, warnings)
+	var merr *multierror.Error
+	require.ErrorAs(t, err, &merr)
+	assert.ElementsMatch(t, []error{
+		&types.Violation{Message: "42 (malformed)"},
+		&types.Violation{Message: "map[code:NOMSG] (malformed)"},
+	}, merr.Errors)
+}
@@ -0,0 +1,62 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/mxab/nacp/pkg/admissionctrl"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/admissionctrl/wasmutil"
+)
+
+// WasmValidator validates jobs with a WebAssembly module. The module gets
+// the payload webhooks get and answers with the same errors and warnings.
+type WasmValidator struct {
+	name   string
+	logger *slog.Logger
+	module *wasmutil.Module
+}
+
+var _ admissionctrl.JobValidator = (*WasmValidator)(nil)
+
+// NewWasmValidator compiles the module in file, which has to export
+// nacp_validate.
+func NewWasmValidator(name string, file string, options wasmutil.Options, logger *slog.Logger) (*WasmValidator, error) {
+	module, err := wasmutil.Load(context.Background(), file, wasmutil.ValidateFunction, options)
+	if err != nil {
+		return nil, err
+	}
+	return &WasmValidator{name: name, logger: logger, module: module}, nil
+}
+
+func (v *WasmValidator) Validate(ctx context.Context, payload *types.Payload) ([]error, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	output, err := v.module.Call(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	result := &validationWebhookResponse{}
+	if err := json.Unmarshal(output, result); err != nil {
+		return nil, fmt.Errorf("failed to decode wasm module response: %w", err)
+	}
+
+	warnings, policyErr := types.DecodeResult(result.Warnings, result.Errors)
+	if policyErr != nil {
+		v.logger.Debug("validation errors", "errors", result.Errors, "rule", v.name)
+	}
+	return warnings, policyErr
+}
+
+func (v *WasmValidator) Name() string {
+	return v.name
+}
+
+// Close releases the module's runtime and instances.
+func (v *WasmValidator) Close() error {
+	return v.module.Close(context.Background())
+}
@@ -0,0 +1,86 @@
+package validator
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/admissionctrl/wasmutil"
+	"github.com/mxab/nacp/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWasmValidator(t *testing.T) {
+	validator, err := NewWasmValidator("wasm", testutil.BuildWasm(t, "wasm/policy"), wasmutil.Options{}, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	assert.Equal(t, "wasm", validator.Name())
+
+	tt := []struct {
+		name         string
+		meta         map[string]string
+		wantWarnings []error
+		wantErr      string
+	}{
+		{
+			name: "valid job",
+			meta: map[string]string{"owner": "team-a"},
+		},
+		{
+			name:         "warnings",
+			meta:         map[string]string{"owner": "team-a", "deprecated": "true"},
+			wantWarnings: []error{&types.Violation{Message: "job is deprecated"}},
+		},
+		{
+			name:    "errors",
+			meta:    map[string]string{},
+			wantErr: "job has no owner",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			job := testutil.BaseJob()
+			job.Meta = tc.meta
+			warnings, err := validator.Validate(t.Context(), &types.Payload{Job: job})
+
+			assert.Equal(t, tc.wantWarnings, warnings)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.wantErr)
+			var rejection *multierror.Error
+			require.ErrorAs(t, err, &rejection, "Policy errors are a rejection")
+			assert.Len(t, types.Violations(rejection.Errors), 1)
+		})
+	}
+}
+
+func TestWasmValidatorClose(t *testing.T) {
+	validator, err := NewWasmValidator("wasm", testutil.BuildWasm(t, "wasm/policy"), wasmutil.Options{}, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+
+	require.NoError(t, validator.Close())
+	_, err = validator.Validate(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	assert.Error(t, err, "a closed module has no runtime left")
+}
+
+func TestWasmValidatorFailure(t *testing.T) {
+	validator, err := NewWasmValidator("wasm", testutil.BuildWasm(t, "wasm/policy"), wasmutil.Options{}, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+
+	job := &api.Job{Meta: map[string]string{"hog": "true"}}
+	_, err = validator.Validate(t.Context(), &types.Payload{Job: job})
+	assert.ErrorContains(t, err, "failed")
+	var rejection *multierror.Error
+	assert.False(t, errors.As(err, &rejection), "A crashing module is a failure, not a rejection")
+}
+
+func TestNewWasmValidatorInvalidOptions(t *testing.T) {
+	file := testutil.BuildWasm(t, "wasm/policy")
+	_, err := NewWasmValidator("wasm", file, wasmutil.Options{PoolSize: -1}, slog.New(slog.DiscardHandler))
+	assert.ErrorContains(t, err, "pool size must not be negative")
+}
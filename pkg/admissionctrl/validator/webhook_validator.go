@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"log/slog"
 	"net/http"
 	"net/url"
@@ -17,14 +16,16 @@ import (
 
 type WebhookValidator struct {
 	endpoint *url.URL
-	logger   *slog.Logger
-	method   string
-	name     string
+	// signingSecret signs requests when set.
+	signingSecret []byte
+	logger        *slog.Logger
+	method        string
+	name          string
 }
 
 type validationWebhookResponse struct {
-	Errors   []string `json:"errors"`
-	Warnings []string `json:"warnings"`
+	Errors   types.ViolationList `json:"errors"`
+	Warnings types.ViolationList `json:"warnings"`
 }
 
 func (w *WebhookValidator) Validate(ctx context.Context, payload *types.Payload) ([]error, error) {
@@ -38,6 +39,7 @@ func (w *WebhookValidator) Validate(ctx context.Context, payload *types.Payload)
 	}
 
 	remoteutil.ApplyContextHeaders(req, payload)
+	remoteutil.SignRequest(req, data, w.signingSecret)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 	resp, err := remoteutil.NewInstrumentedClient().Do(req)
@@ -55,7 +57,7 @@ func (w *WebhookValidator) Validate(ctx context.Context, payload *types.Payload)
 		w.logger.Error("validation errors", "errors", validationResult.Errors, "rule", w.name, "job", payload.Job.ID)
 		oneError := &multierror.Error{}
 		for _, e := range validationResult.Errors {
-			oneError = multierror.Append(oneError, fmt.Errorf("%v", e))
+			oneError = multierror.Append(oneError, e)
 		}
 		return nil, oneError
 	}
@@ -64,7 +66,7 @@ func (w *WebhookValidator) Validate(ctx context.Context, payload *types.Payload)
 	if len(validationResult.Warnings) > 0 {
 
 		for _, w := range validationResult.Warnings {
-			warnings = append(warnings, fmt.Errorf("%v", w))
+			warnings = append(warnings, w)
 		}
 		return warnings, nil
 
@@ -74,15 +76,16 @@ func (w *WebhookValidator) Validate(ctx context.Context, payload *types.Payload)
 func (w *WebhookValidator) Name() string {
 	return w.name
 }
-func NewWebhookValidator(name string, endpoint string, method string, logger *slog.Logger) (*WebhookValidator, error) {
+func NewWebhookValidator(name string, endpoint string, method string, signingSecret []byte, logger *slog.Logger) (*WebhookValidator, error) {
 	u, err := remoteutil.ParseEndpoint(endpoint)
 	if err != nil {
 		return nil, err
 	}
 	return &WebhookValidator{
-		name:     name,
-		logger:   logger,
-		endpoint: u,
-		method:   method,
+		name:          name,
+		logger:        logger,
+		endpoint:      u,
+		method:        method,
+		signingSecret: signingSecret,
 	}, nil
 }
@@ -56,9 +56,25 @@ func TestWebhookValidator(t *testing.T) {
 
 			method:       "POST",
 			response:     `{"errors": ["error1", "error2"], "warnings": []}`,
-			wantErr:      multierror.Append(fmt.Errorf("error1"), fmt.Errorf("error2")),
+			wantErr:      multierror.Append(&types.Violation{Message: "error1"}, &types.Violation{Message: "error2"}),
 			wantWarnings: nil,
 		},
+		{
+			name:         "structured errors",
+			endpointPath: "/validate",
+
+			method:   "POST",
+			response: `{"errors": [{"message": "memory exceeds limit", "code": "MEM001", "path": "/TaskGroups/0/Tasks/0/Resources/MemoryMB", "severity": "high", "remediation_url": "https://example.com/mem"}]}`,
+			wantErr: multierror.Append(&multierror.Error{}, &types.Violation{
+				Message:        "memory exceeds limit",
+				Code:           "MEM001",
+				Path:           "/TaskGroups/0/Tasks/0/Resources/MemoryMB",
+				Severity:       "high",
+				RemediationURL: "https://example.com/mem",
+			}),
+			wantWarnings: nil,
+		},
+
 		{
 			name:           "non-success HTTP response",
 			endpointPath:   "/validate",
@@ -74,7 +90,7 @@ func TestWebhookValidator(t *testing.T) {
 			method:       "POST",
 			response:     `{"errors": [], "warnings": ["warning1", "warning2"]}`,
 			wantErr:      nil,
-			wantWarnings: []error{fmt.Errorf("warning1"), fmt.Errorf("warning2")},
+			wantWarnings: []error{&types.Violation{Message: "warning1"}, &types.Violation{Message: "warning2"}},
 		},
 		{
 			name:         "with AccessorID context",
@@ -138,7 +154,7 @@ func TestWebhookValidator(t *testing.T) {
 			}))
 			defer server.Close()
 
-			validator, err := NewWebhookValidator("test", server.URL+tc.endpointPath, tc.method, slog.New(slog.DiscardHandler))
+			validator, err := NewWebhookValidator("test", server.URL+tc.endpointPath, tc.method, nil, slog.New(slog.DiscardHandler))
 			require.NoError(t, err)
 
 			payload := &types.Payload{Job: &api.Job{ID: &tc.name}, Context: tc.context}
@@ -151,3 +167,22 @@ func TestWebhookValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestWebhookValidatorNullViolations(t *testing.T) {
+	for _, response := range []string{`{"errors": [null]}`, `{"warnings": ["fine", null]}`} {
+		t.Run(response, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(response))
+			}))
+			defer server.Close()
+
+			validator, err := NewWebhookValidator("test", server.URL, http.MethodPost, nil, slog.New(slog.DiscardHandler))
+			require.NoError(t, err)
+
+			warnings, err := validator.Validate(t.Context(), &types.Payload{Job: &api.Job{ID: &response}})
+			assert.ErrorContains(t, err, "failed to decode webhook response: violation")
+			assert.ErrorContains(t, err, "is null")
+			assert.Nil(t, warnings)
+		})
+	}
+}
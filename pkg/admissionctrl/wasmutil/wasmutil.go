@@ -0,0 +1,212 @@
+// Package wasmutil runs the WebAssembly modules of the wasm validator and
+// mutator on the wazero runtime.
+//
+// A module is a WASI reactor that exports its memory and
+//
+//	nacp_malloc(size u32) -> ptr u32
+//	nacp_validate(ptr u32, len u32) -> u64
+//	nacp_mutate(ptr u32, len u32) -> u64
+//
+// NACP allocates a buffer with nacp_malloc, writes the JSON payload into it
+// and calls nacp_validate or nacp_mutate. They return the location of their
+// JSON response packed as ptr<<32 | len. A module exporting
+// nacp_free(ptr u32, len u32) gets both buffers back after the call.
+package wasmutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mxab/nacp/pkg/admissionctrl/remoteutil"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+const (
+	// ValidateFunction is the export the wasm validator calls.
+	ValidateFunction = "nacp_validate"
+	// MutateFunction is the export the wasm mutator calls.
+	MutateFunction = "nacp_mutate"
+
+	mallocFunction = "nacp_malloc"
+	freeFunction   = "nacp_free"
+
+	DefaultMemoryLimitMB = 128
+	DefaultCallTimeout   = time.Second
+	DefaultPoolSize      = 4
+
+	// maxMemoryLimitMB is the 4 GiB a 32-bit module can address.
+	maxMemoryLimitMB = 4096
+	pagesPerMB       = 16
+)
+
+// compilationCache is shared by all runtimes, so a module used by several
+// controllers is compiled once.
+var compilationCache = wazero.NewCompilationCache()
+
+// Options limit the resources of a module. Zero values mean the defaults.
+type Options struct {
+	// MemoryLimitMB caps the linear memory of each instance.
+	MemoryLimitMB int
+	// CallTimeout aborts calls running longer.
+	CallTimeout time.Duration
+	// PoolSize is the number of instances, and so of concurrent calls.
+	PoolSize int
+}
+
+// Module is a compiled module with a pool of instances. Instances are
+// reused across calls; an instance whose call failed is discarded.
+type Module struct {
+	file     string
+	function string
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+	timeout  time.Duration
+	slots    chan struct{}
+	idle     chan api.Module
+}
+
+// Load compiles the module in file and checks it exports function.
+func Load(ctx context.Context, file string, function string, options Options) (*Module, error) {
+	if options.MemoryLimitMB == 0 {
+		options.MemoryLimitMB = DefaultMemoryLimitMB
+	}
+	if options.CallTimeout == 0 {
+		options.CallTimeout = DefaultCallTimeout
+	}
+	if options.PoolSize == 0 {
+		options.PoolSize = DefaultPoolSize
+	}
+	if options.MemoryLimitMB < 0 || options.MemoryLimitMB > maxMemoryLimitMB {
+		return nil, fmt.Errorf("wasm memory limit must be between 1 and %d MB", maxMemoryLimitMB)
+	}
+	if options.PoolSize < 0 {
+		return nil, fmt.Errorf("wasm pool size must not be negative")
+	}
+
+	binary, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wasm module: %w", err)
+	}
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(uint32(options.MemoryLimitMB*pagesPerMB)).
+		WithCloseOnContextDone(true).
+		WithCompilationCache(compilationCache))
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("failed to instantiate WASI: %w", err)
+	}
+	compiled, err := runtime.CompileModule(ctx, binary)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("failed to compile wasm module %s: %w", file, err)
+	}
+	exports := compiled.ExportedFunctions()
+	for _, name := range []string{mallocFunction, function} {
+		if _, ok := exports[name]; !ok {
+			runtime.Close(ctx)
+			return nil, fmt.Errorf("wasm module %s does not export %s", file, name)
+		}
+	}
+	return &Module{
+		file:     file,
+		function: function,
+		runtime:  runtime,
+		compiled: compiled,
+		timeout:  options.CallTimeout,
+		slots:    make(chan struct{}, options.PoolSize),
+		idle:     make(chan api.Module, options.PoolSize),
+	}, nil
+}
+
+// Call passes input to the module's function and returns its response. It
+// waits for a free instance if all of them are busy.
+func (m *Module) Call(ctx context.Context, input []byte) ([]byte, error) {
+	select {
+	case m.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-m.slots }()
+
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	instance, err := m.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	output, err := m.call(ctx, instance, input)
+	if err != nil {
+		instance.Close(context.Background())
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("wasm module %s exceeded its call timeout of %s: %w", m.file, m.timeout, context.DeadlineExceeded)
+		}
+		return nil, fmt.Errorf("wasm module %s failed: %w", m.file, err)
+	}
+	m.idle <- instance
+	return output, nil
+}
+
+// Close releases the runtime and all instances.
+func (m *Module) Close(ctx context.Context) error {
+	return m.runtime.Close(ctx)
+}
+
+func (m *Module) acquire(ctx context.Context) (api.Module, error) {
+	select {
+	case instance := <-m.idle:
+		return instance, nil
+	default:
+	}
+	config := wazero.NewModuleConfig().
+		WithName("").
+		WithStartFunctions("_initialize").
+		WithStderr(os.Stderr)
+	instance, err := m.runtime.InstantiateModule(ctx, m.compiled, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate wasm module %s: %w", m.file, err)
+	}
+	return instance, nil
+}
+
+func (m *Module) call(ctx context.Context, instance api.Module, input []byte) ([]byte, error) {
+	results, err := instance.ExportedFunction(mallocFunction).Call(ctx, uint64(len(input)))
+	if err != nil {
+		return nil, err
+	}
+	inputPtr := uint32(results[0])
+	memory := instance.Memory()
+	if memory == nil || !memory.Write(inputPtr, input) {
+		return nil, fmt.Errorf("%s returned a buffer outside of the module memory", mallocFunction)
+	}
+
+	results, err = instance.ExportedFunction(m.function).Call(ctx, uint64(inputPtr), uint64(len(input)))
+	if err != nil {
+		return nil, err
+	}
+	outputPtr, outputLen := uint32(results[0]>>32), uint32(results[0])
+	if outputLen > remoteutil.MaxResponseBodyBytes {
+		return nil, fmt.Errorf("%s response exceeds %d bytes", m.function, remoteutil.MaxResponseBodyBytes)
+	}
+	data, ok := memory.Read(outputPtr, outputLen)
+	if !ok {
+		return nil, fmt.Errorf("%s returned a response outside of the module memory", m.function)
+	}
+	output := make([]byte, len(data))
+	copy(output, data)
+
+	if free := instance.ExportedFunction(freeFunction); free != nil {
+		if _, err := free.Call(ctx, uint64(inputPtr), uint64(len(input))); err != nil {
+			return nil, err
+		}
+		if _, err := free.Call(ctx, uint64(outputPtr), uint64(outputLen)); err != nil {
+			return nil, err
+		}
+	}
+	return output, nil
+}
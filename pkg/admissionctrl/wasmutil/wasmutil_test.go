@@ -0,0 +1,92 @@
+package wasmutil
+
+import (
+	"context"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mxab/nacp/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadPolicy(t *testing.T, function string, options Options) *Module {
+	t.Helper()
+	module, err := Load(t.Context(), testutil.BuildWasm(t, "wasm/policy"), function, options)
+	require.NoError(t, err)
+	t.Cleanup(func() { module.Close(context.Background()) })
+	return module
+}
+
+func TestLoad(t *testing.T) {
+	file := testutil.BuildWasm(t, "wasm/policy")
+
+	_, err := Load(t.Context(), file, "nacp_unknown", Options{})
+	assert.ErrorContains(t, err, "does not export nacp_unknown")
+
+	_, err = Load(t.Context(), file, ValidateFunction, Options{MemoryLimitMB: 5000})
+	assert.ErrorContains(t, err, "memory limit must be between")
+
+	_, err = Load(t.Context(), path.Join(t.TempDir(), "missing.wasm"), ValidateFunction, Options{})
+	assert.ErrorContains(t, err, "failed to read wasm module")
+
+	invalid := path.Join(t.TempDir(), "invalid.wasm")
+	require.NoError(t, os.WriteFile(invalid, []byte("not wasm"), 0o600))
+	_, err = Load(t.Context(), invalid, ValidateFunction, Options{})
+	assert.ErrorContains(t, err, "failed to compile wasm module")
+}
+
+func TestCall(t *testing.T) {
+	module := loadPolicy(t, ValidateFunction, Options{})
+
+	output, err := module.Call(t.Context(), []byte(`{"job": {"Meta": {"deprecated": "true"}}}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"errors": ["job has no owner"], "warnings": ["job is deprecated"]}`, string(output))
+
+	output, err = module.Call(t.Context(), []byte(`{"job": {"Meta": {"owner": "team-a"}}}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(output))
+}
+
+func TestCallConcurrently(t *testing.T) {
+	module := loadPolicy(t, ValidateFunction, Options{PoolSize: 2})
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			output, err := module.Call(t.Context(), []byte(`{"job": {"Meta": {"owner": "team-a"}}}`))
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{}`, string(output))
+		})
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, len(module.idle), 2, "Pool does not grow beyond its size")
+}
+
+func TestCallTimeout(t *testing.T) {
+	module := loadPolicy(t, ValidateFunction, Options{CallTimeout: 100 * time.Millisecond})
+
+	start := time.Now()
+	_, err := module.Call(t.Context(), []byte(`{"job": {"Meta": {"spin": "true"}}}`))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "exceeded its call timeout of 100ms")
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// the aborted instance is discarded, the next call gets a fresh one
+	_, err = module.Call(t.Context(), []byte(`{"job": {"Meta": {"owner": "team-a"}}}`))
+	assert.NoError(t, err)
+}
+
+func TestCallMemoryLimit(t *testing.T) {
+	module := loadPolicy(t, ValidateFunction, Options{MemoryLimitMB: 64})
+
+	_, err := module.Call(t.Context(), []byte(`{"job": {"Meta": {"hog": "true"}}}`))
+	assert.ErrorContains(t, err, "failed")
+	assert.NotErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = module.Call(t.Context(), []byte(`{"job": {"Meta": {"owner": "team-a"}}}`))
+	assert.NoError(t, err)
+}
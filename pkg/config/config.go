@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"path"
+	"regexp"
 	"slices"
 	"strings"
 	"time"
@@ -11,15 +13,113 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsimple"
 	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl/celutil"
+	"github.com/santhosh-tekuri/jsonschema/v6"
 )
 
 func Ptr[T any](v T) *T {
 	return &v
 }
 
+// Admission operations a controller can take part in.
+const (
+	OperationRegister   = "register"
+	OperationPlan       = "plan"
+	OperationValidate   = "validate"
+	OperationDispatch   = "dispatch"
+	OperationDeregister = "deregister"
+	OperationScale      = "scale"
+)
+
+// JobOperations are the operations controllers run for when they do not list
+// any operations themselves.
+var JobOperations = []string{OperationRegister, OperationPlan, OperationValidate}
+
+var (
+	validValidatorOperations = []string{OperationRegister, OperationPlan, OperationValidate, OperationDispatch, OperationDeregister, OperationScale}
+	validMutatorOperations   = []string{OperationRegister, OperationPlan, OperationValidate, OperationScale}
+)
+
+// Modes of an admission controller.
+const (
+	// ModeEnforce applies the controller's patches and rejections.
+	ModeEnforce = "enforce"
+	// ModeAudit only records the patches and rejections the controller
+	// would have applied.
+	ModeAudit = "audit"
+)
+
+var validModes = []string{ModeEnforce, ModeAudit}
+
+// Failure policies of an admission controller, decided when the controller
+// itself fails, e.g. because a webhook is unreachable or times out.
+const (
+	FailurePolicyFail   = "fail"
+	FailurePolicyIgnore = "ignore"
+)
+
+var validFailurePolicies = []string{FailurePolicyFail, FailurePolicyIgnore}
+
+// Reinvocation policies of a mutator, deciding whether it runs again when a
+// later mutator changed the job.
+const (
+	ReinvocationNever    = "never"
+	ReinvocationIfNeeded = "if_needed"
+)
+
+var validReinvocationPolicies = []string{ReinvocationNever, ReinvocationIfNeeded}
+
+// DefaultMaxMutatorPasses bounds how often the mutator chain runs when
+// mutators are reinvoked.
+const DefaultMaxMutatorPasses = 3
+
+var validJobTypes = []string{"service", "batch", "system", "sysbatch"}
+
+// Enforcement levels of a validator, modelled after Sentinel.
+const (
+	// EnforcementAdvisory turns validation errors into warnings.
+	EnforcementAdvisory = "advisory"
+	// EnforcementSoftMandatory rejects the job unless the caller overrides it.
+	EnforcementSoftMandatory = "soft-mandatory"
+	// EnforcementHardMandatory always rejects the job on validation errors.
+	EnforcementHardMandatory = "hard-mandatory"
+)
+
+var validEnforcementLevels = []string{EnforcementAdvisory, EnforcementSoftMandatory, EnforcementHardMandatory}
+
+// Strategies for the HCL submission of a job registration once mutators
+// changed the job.
+const (
+	SubmissionKeep       = "keep"
+	SubmissionDrop       = "drop"
+	SubmissionAnnotate   = "annotate"
+	SubmissionRegenerate = "regenerate"
+)
+
+// DefaultSubmissionMetaKey is the job meta key the annotate strategy records
+// the applied mutations under.
+const DefaultSubmissionMetaKey = "nacp.mutations"
+
+// DefaultProvenanceMetaKey is the job meta key provenance is recorded under.
+const DefaultProvenanceMetaKey = "nacp.mutated-by"
+
+var validSubmissionStrategies = []string{SubmissionKeep, SubmissionDrop, SubmissionAnnotate, SubmissionRegenerate}
+
+// Targets of the native controllers, the level of the job they act on.
+const (
+	TargetJob   = "job"
+	TargetGroup = "group"
+	TargetTask  = "task"
+)
+
+var validTargets = []string{TargetJob, TargetGroup, TargetTask}
+
 type Webhook struct {
 	Endpoint string `hcl:"endpoint"`
 	Method   string `hcl:"method"`
+	// SigningSecretFile holds the secret NACP signs its requests with, see
+	// package webhook/server. Empty means unsigned requests.
+	SigningSecretFile string `hcl:"signing_secret_file,optional"`
 }
 type OpaRule struct {
 	Query    string                  `hcl:"query"`
@@ -37,8 +137,135 @@ type Validator struct {
 	OpaSdkRule   *OpaSdkRule `hcl:"opa_sdk_rule,block"`
 	Webhook      *Webhook    `hcl:"webhook,block"`
 	ResolveToken bool        `hcl:"resolve_token,optional"`
+	Operations   []string    `hcl:"operations,optional"`
+
+	// EnforcementLevel is advisory, soft-mandatory or hard-mandatory.
+	// Empty means hard-mandatory.
+	EnforcementLevel string          `hcl:"enforcement_level,optional"`
+	Override         *PolicyOverride `hcl:"override,block"`
+	// Mode is enforce or audit. Empty means the global mode.
+	Mode  string `hcl:"mode,optional"`
+	Match *Match `hcl:"match,block"`
+	// Timeout is a duration like "5s" after which NACP stops waiting for
+	// the validator. Empty means no limit.
+	Timeout string `hcl:"timeout,optional"`
+	// FailurePolicy is fail or ignore. Empty means fail.
+	FailurePolicy string `hcl:"failure_policy,optional"`
 
 	Notation *NotationVerifierConfig `hcl:"notation,block"`
+
+	ResourceLimits   *ResourceLimits   `hcl:"resource_limits,block"`
+	AllowedDrivers   *AllowedDrivers   `hcl:"allowed_drivers,block"`
+	RequiredMeta     *RequiredMeta     `hcl:"required_meta,block"`
+	ImageRegistries  *ImageRegistries  `hcl:"image_registries,block"`
+	ForbidPrivileged *ForbidPrivileged `hcl:"forbid_privileged,block"`
+	MaxParallel      *MaxParallel      `hcl:"max_parallel,block"`
+
+	Cel *CelValidator `hcl:"cel,block"`
+
+	JSONSchema *JSONSchema `hcl:"json_schema,block"`
+
+	Wasm *Wasm `hcl:"wasm,block"`
+	Exec *Exec `hcl:"exec,block"`
+	Grpc *Grpc `hcl:"grpc,block"`
+}
+
+// Grpc configures a grpc_validator or grpc_mutator, a server implementing the
+// Admission service of package admissionpb.
+type Grpc struct {
+	// Address is a gRPC target like "dns:///policy.example.com:9090".
+	Address string `hcl:"address"`
+	// CallTimeout is a duration like "2s", the deadline of each call. Empty
+	// means 30s.
+	CallTimeout string `hcl:"call_timeout,optional"`
+	// TLS enables TLS. Without it, the connection is plaintext.
+	TLS *GrpcTLS `hcl:"tls,block"`
+}
+
+// GrpcTLS configures the TLS connection to a grpc server. The system's CAs
+// are trusted unless CaFile is set.
+type GrpcTLS struct {
+	CaFile             string `hcl:"ca_file,optional"`
+	CertFile           string `hcl:"cert_file,optional"`
+	KeyFile            string `hcl:"key_file,optional"`
+	ServerName         string `hcl:"server_name,optional"`
+	InsecureSkipVerify bool   `hcl:"insecure_skip_verify,optional"`
+}
+
+// Exec configures an exec validator or mutator, a command that gets the
+// payload on stdin and answers like a webhook on stdout.
+type Exec struct {
+	Command string   `hcl:"command"`
+	Args    []string `hcl:"args,optional"`
+	// Env is added to the environment of NACP.
+	Env map[string]string `hcl:"env,optional"`
+	// CallTimeout is a duration like "2s" after which the command is
+	// killed. Empty means 5s.
+	CallTimeout string `hcl:"call_timeout,optional"`
+	// RejectExitCodes are the exit codes that reject the job with the
+	// command's stderr as message. Other non-zero exit codes are failures.
+	RejectExitCodes []int `hcl:"reject_exit_codes,optional"`
+	// Persistent keeps one process running that exchanges newline-delimited
+	// JSON, instead of starting the command for every call.
+	Persistent bool `hcl:"persistent,optional"`
+}
+
+// Wasm configures a wasm validator or mutator, a WebAssembly module that
+// answers like a webhook.
+type Wasm struct {
+	// Module is the path of the .wasm file.
+	Module string `hcl:"module"`
+	// MemoryLimitMB caps the memory of a module instance. Unset means 128.
+	MemoryLimitMB *int `hcl:"memory_limit_mb,optional"`
+	// CallTimeout is a duration like "100ms" after which a call is aborted.
+	// Empty means 1s.
+	CallTimeout string `hcl:"call_timeout,optional"`
+	// PoolSize is the number of module instances, and so of concurrent
+	// calls. Zero means 4.
+	PoolSize int `hcl:"pool_size,optional"`
+}
+
+// JSONSchema configures a json_schema validator.
+type JSONSchema struct {
+	// Files are the schemas the job has to satisfy. Schemas without a
+	// $schema keyword are read as draft 2020-12.
+	Files []string `hcl:"files"`
+	// Payload validates the whole admission payload instead of the job.
+	Payload bool `hcl:"payload,optional"`
+}
+
+// CelValidator configures a cel validator.
+type CelValidator struct {
+	Validations []CelValidation `hcl:"validation,block"`
+}
+
+// CelMutator configures a cel mutator.
+type CelMutator struct {
+	// Mutations are applied in order.
+	Mutations []CelMutation `hcl:"mutation,block"`
+}
+
+// CelValidation is a CEL expression that has to evaluate to true for a job
+// to be admitted.
+type CelValidation struct {
+	Expression string `hcl:"expression"`
+	// Message is reported when the expression is false. Empty means a
+	// message naming the expression.
+	Message string `hcl:"message,optional"`
+}
+
+// CelMutation sets the job field at Path, a JSON pointer, to the result of a
+// CEL expression. A null result leaves the field alone.
+type CelMutation struct {
+	Path       string `hcl:"path"`
+	Expression string `hcl:"expression"`
+}
+
+// PolicyOverride lists the ACL policies and roles whose tokens may override
+// a soft-mandatory validator.
+type PolicyOverride struct {
+	Policies []string `hcl:"policies,optional"`
+	Roles    []string `hcl:"roles,optional"`
 }
 type Mutator struct {
 	Type         string      `hcl:"type,label"`
@@ -47,13 +274,155 @@ type Mutator struct {
 	OpaSdkRule   *OpaSdkRule `hcl:"opa_sdk_rule,block"`
 	Webhook      *Webhook    `hcl:"webhook,block"`
 	ResolveToken bool        `hcl:"resolve_token,optional"`
+	Operations   []string    `hcl:"operations,optional"`
+	// Mode is enforce or audit. Empty means the global mode.
+	Mode  string `hcl:"mode,optional"`
+	Match *Match `hcl:"match,block"`
+	// Timeout is a duration like "5s" after which NACP stops waiting for
+	// the mutator. Empty means no limit.
+	Timeout string `hcl:"timeout,optional"`
+	// FailurePolicy is fail or ignore. Empty means fail.
+	FailurePolicy string `hcl:"failure_policy,optional"`
+	// ReinvocationPolicy is never or if_needed. Empty means never.
+	ReinvocationPolicy string `hcl:"reinvocation_policy,optional"`
+
+	Defaults      *JobDefaults   `hcl:"defaults,block"`
+	InjectMeta    *InjectMeta    `hcl:"inject_meta,block"`
+	AddConstraint *AddConstraint `hcl:"add_constraint,block"`
+
+	Cel *CelMutator `hcl:"cel,block"`
+
+	Wasm *Wasm `hcl:"wasm,block"`
+	Exec *Exec `hcl:"exec,block"`
+	Grpc *Grpc `hcl:"grpc,block"`
+}
+
+// JobDefaults configures a defaults mutator, the values it sets on jobs that
+// leave them unset.
+type JobDefaults struct {
+	Namespace   string         `hcl:"namespace,optional"`
+	NodePool    string         `hcl:"node_pool,optional"`
+	Datacenters []string       `hcl:"datacenters,optional"`
+	Resources   *TaskResources `hcl:"resources,block"`
+	// Overwrite replaces values that are already set.
+	Overwrite bool   `hcl:"overwrite,optional"`
+	Scope     *Scope `hcl:"scope,block"`
+}
+
+// InjectMeta configures an inject_meta mutator.
+type InjectMeta struct {
+	Meta map[string]string `hcl:"meta"`
+	// Target is job, group or task. Empty means job.
+	Target string `hcl:"target,optional"`
+	// Overwrite replaces meta keys that are already set.
+	Overwrite bool   `hcl:"overwrite,optional"`
+	Scope     *Scope `hcl:"scope,block"`
+}
+
+// AddConstraint configures an add_constraint mutator.
+type AddConstraint struct {
+	Constraint *Constraint `hcl:"constraint,block"`
+	// Target is job, group or task. Empty means job.
+	Target string `hcl:"target,optional"`
+	Scope  *Scope `hcl:"scope,block"`
+}
+
+// ResourceLimits configures a resource_limits validator. Like the other
+// guardrail validators, it takes a Message that replaces the default message.
+type ResourceLimits struct {
+	// Limits are the task resource ceilings.
+	Limits  *TaskResources `hcl:"limits,block"`
+	Scope   *Scope         `hcl:"scope,block"`
+	Message string         `hcl:"message,optional"`
+}
+
+// AllowedDrivers configures an allowed_drivers validator.
+type AllowedDrivers struct {
+	Drivers []string `hcl:"drivers"`
+	Scope   *Scope   `hcl:"scope,block"`
+	Message string   `hcl:"message,optional"`
+}
+
+// RequiredMeta configures a required_meta validator.
+type RequiredMeta struct {
+	// Keys are the meta keys that have to be set.
+	Keys []string `hcl:"keys"`
+	// Target is job, group or task. Empty means job.
+	Target  string `hcl:"target,optional"`
+	Scope   *Scope `hcl:"scope,block"`
+	Message string `hcl:"message,optional"`
+}
+
+// ImageRegistries configures an image_registries validator.
+type ImageRegistries struct {
+	// Registries are the allowed image registries, optionally with a
+	// repository prefix like "ghcr.io/my-org".
+	Registries []string `hcl:"registries"`
+	Scope      *Scope   `hcl:"scope,block"`
+	Message    string   `hcl:"message,optional"`
+}
+
+// ForbidPrivileged configures a forbid_privileged validator. The block is
+// optional.
+type ForbidPrivileged struct {
+	Scope   *Scope `hcl:"scope,block"`
+	Message string `hcl:"message,optional"`
+}
+
+// MaxParallel configures a max_parallel validator.
+type MaxParallel struct {
+	// Max is the highest update max_parallel allowed.
+	Max     int    `hcl:"max"`
+	Scope   *Scope `hcl:"scope,block"`
+	Message string `hcl:"message,optional"`
+}
+
+// TaskResources are task resources, the values a defaults mutator sets or
+// the ceilings a resource_limits validator enforces.
+type TaskResources struct {
+	CPU         *int `hcl:"cpu,optional"`
+	Cores       *int `hcl:"cores,optional"`
+	MemoryMB    *int `hcl:"memory_mb,optional"`
+	MemoryMaxMB *int `hcl:"memory_max_mb,optional"`
+}
+
+// Constraint mirrors a Nomad constraint block. Interpolations like
+// ${attr.kernel.name} have to be escaped as $${attr.kernel.name} in HCL.
+type Constraint struct {
+	Attribute string `hcl:"attribute,optional"`
+	// Operator defaults to "=".
+	Operator string `hcl:"operator,optional"`
+	Value    string `hcl:"value,optional"`
+}
+
+// Scope restricts a native controller to the task groups and tasks whose
+// names match one of the glob patterns. Empty lists match everything.
+type Scope struct {
+	TaskGroups []string `hcl:"task_groups,optional"`
+	Tasks      []string `hcl:"tasks,optional"`
+}
+
+// Match restricts the jobs a controller runs for. Every attribute that is set
+// has to match.
+type Match struct {
+	// Namespaces are glob patterns, e.g. "team-*".
+	Namespaces []string `hcl:"namespaces,optional"`
+	// JobTypes are service, batch, system or sysbatch.
+	JobTypes  []string `hcl:"job_types,optional"`
+	Regions   []string `hcl:"regions,optional"`
+	NodePools []string `hcl:"node_pools,optional"`
+	// JobID is a regular expression the job ID has to match.
+	JobID string `hcl:"job_id,optional"`
+	// Meta lists job meta keys and the values they must have.
+	Meta map[string]string `hcl:"meta,optional"`
 }
 
 type RequestContext struct {
-	ClientIP     string           `json:"clientIP"`
-	AccessorID   string           `json:"accessorID"`
-	ResolveToken bool             `json:"resolveToken"`
-	TokenInfo    *ACLTokenContext `json:"tokenInfo,omitempty"`
+	ClientIP       string           `json:"clientIP"`
+	AccessorID     string           `json:"accessorID"`
+	ResolveToken   bool             `json:"resolveToken"`
+	TokenInfo      *ACLTokenContext `json:"tokenInfo,omitempty"`
+	PolicyOverride bool             `json:"policyOverride,omitempty"`
 }
 
 type ACLTokenContext struct {
@@ -153,6 +522,40 @@ type Config struct {
 	Telemetry *Telemetry `hcl:"telemetry,block"`
 
 	OpaSdk *OpaSdk `hcl:"opa_sdk,block"`
+
+	// MutateParsedJobs runs the mutators on jobs returned by /v1/jobs/parse.
+	MutateParsedJobs bool `hcl:"mutate_parsed_jobs,optional"`
+
+	Submission *Submission `hcl:"submission,block"`
+
+	// Mode is the default mode of all controllers, enforce or audit.
+	Mode string `hcl:"mode,optional"`
+
+	// MaxConcurrency is the number of validators run at the same time.
+	// Zero runs them one after another.
+	MaxConcurrency int `hcl:"max_concurrency,optional"`
+
+	// MaxMutatorPasses bounds how often the mutator chain runs when
+	// mutators are reinvoked. Zero means DefaultMaxMutatorPasses.
+	MaxMutatorPasses int `hcl:"max_mutator_passes,optional"`
+
+	Provenance *Provenance `hcl:"provenance,block"`
+}
+
+// Provenance records the mutators that changed a job in the job's meta and
+// in the response to the caller.
+type Provenance struct {
+	// MetaKey is the job meta key the mutators are recorded under.
+	MetaKey string `hcl:"meta_key,optional"`
+}
+
+// Submission controls what happens to the source submitted with a job
+// registration when mutators changed the job.
+type Submission struct {
+	// Strategy is one of keep, drop, annotate or regenerate.
+	Strategy string `hcl:"strategy"`
+	// MetaKey is the job meta key the annotate strategy writes to.
+	MetaKey string `hcl:"meta_key,optional"`
 }
 type OpaSdk struct {
 	Id         string `hcl:"id,label"`
@@ -214,6 +617,12 @@ func LoadConfig(name string) (*Config, error) {
 			setNotationDefaults(c.Mutators[i].OpaRule.Notation)
 		}
 	}
+	if c.Submission != nil && c.Submission.MetaKey == "" {
+		c.Submission.MetaKey = DefaultSubmissionMetaKey
+	}
+	if c.Provenance != nil && c.Provenance.MetaKey == "" {
+		c.Provenance.MetaKey = DefaultProvenanceMetaKey
+	}
 
 	// verify json/text out
 	var validOuts = []string{"stdout", "stderr"}
@@ -248,6 +657,18 @@ func (c *Config) Validate() error {
 	if c.OpaSdk != nil && (strings.TrimSpace(c.OpaSdk.Id) == "" || strings.TrimSpace(c.OpaSdk.ConfigPath) == "") {
 		return fmt.Errorf("opa_sdk requires a non-empty id and config_path")
 	}
+	if err := validateMode("global", "", c.Mode); err != nil {
+		return err
+	}
+	if c.MaxConcurrency < 0 {
+		return fmt.Errorf("max_concurrency must not be negative")
+	}
+	if c.MaxMutatorPasses < 0 {
+		return fmt.Errorf("max_mutator_passes must not be negative")
+	}
+	if c.Submission != nil && !slices.Contains(validSubmissionStrategies, c.Submission.Strategy) {
+		return fmt.Errorf("unknown submission strategy %q", c.Submission.Strategy)
+	}
 	return validateControllers(c)
 }
 
@@ -309,22 +730,147 @@ func validateMutator(mutator Mutator, hasOpaSDK bool) error {
 	if strings.TrimSpace(mutator.Name) == "" {
 		return fmt.Errorf("mutator name is required")
 	}
+	if err := validateOperations("mutator", mutator.Name, mutator.Operations, validMutatorOperations); err != nil {
+		return err
+	}
+	if err := validateMode("mutator", mutator.Name, mutator.Mode); err != nil {
+		return err
+	}
+	if err := validateMatch("mutator", mutator.Name, mutator.Match); err != nil {
+		return err
+	}
+	if err := validateTimeout("mutator", mutator.Name, "timeout", mutator.Timeout); err != nil {
+		return err
+	}
+	if err := validateFailurePolicy("mutator", mutator.Name, mutator.FailurePolicy); err != nil {
+		return err
+	}
+	if mutator.ReinvocationPolicy != "" && !slices.Contains(validReinvocationPolicies, mutator.ReinvocationPolicy) {
+		return fmt.Errorf("mutator %q has an unknown reinvocation_policy %q", mutator.Name, mutator.ReinvocationPolicy)
+	}
 	switch mutator.Type {
 	case "opa_json_patch":
 		return validateOpaRule("mutator", mutator.Name, mutator.OpaRule)
-	case "json_patch_webhook":
+	case "json_patch_webhook", "webhook":
 		return validateWebhook("mutator", mutator.Name, mutator.Webhook)
 	case "opa_bundle_json_patch":
 		return validateOpaSDKRule("mutator", mutator.Name, mutator.OpaSdkRule, hasOpaSDK)
+	case "defaults":
+		return validateDefaults(mutator.Name, mutator.Defaults)
+	case "inject_meta":
+		return validateInjectMeta(mutator.Name, mutator.InjectMeta)
+	case "cel":
+		if mutator.Cel == nil || len(mutator.Cel.Mutations) == 0 {
+			return fmt.Errorf("mutator %q requires a cel block with at least one mutation block", mutator.Name)
+		}
+		for _, mutation := range mutator.Cel.Mutations {
+			if !strings.HasPrefix(mutation.Path, "/") {
+				return fmt.Errorf("mutator %q mutation path %q must be a JSON pointer", mutator.Name, mutation.Path)
+			}
+			if _, err := celutil.Compile(mutation.Expression); err != nil {
+				return fmt.Errorf("mutator %q: %w", mutator.Name, err)
+			}
+		}
+		return nil
+	case "add_constraint":
+		return validateAddConstraint(mutator.Name, mutator.AddConstraint)
+	case "wasm":
+		return validateWasm("mutator", mutator.Name, mutator.Wasm)
+	case "exec":
+		return validateExec("mutator", mutator.Name, mutator.Exec)
+	case "grpc_mutator":
+		return validateGrpc("mutator", mutator.Name, mutator.Grpc)
 	default:
 		return fmt.Errorf("unknown mutator type %q", mutator.Type)
 	}
 }
 
+func validateDefaults(name string, defaults *JobDefaults) error {
+	if defaults == nil || (defaults.Namespace == "" && defaults.NodePool == "" && len(defaults.Datacenters) == 0 && defaults.Resources == nil) {
+		return fmt.Errorf("mutator %q requires a defaults block with at least one value", name)
+	}
+	if err := validateResources("mutator", name, "defaults", defaults.Resources); err != nil {
+		return err
+	}
+	return validateScope("mutator", name, defaults.Scope)
+}
+
+func validateInjectMeta(name string, injectMeta *InjectMeta) error {
+	if injectMeta == nil || len(injectMeta.Meta) == 0 {
+		return fmt.Errorf("mutator %q requires an inject_meta block with meta", name)
+	}
+	return validateTarget("mutator", name, injectMeta.Target, injectMeta.Scope)
+}
+
+func validateAddConstraint(name string, addConstraint *AddConstraint) error {
+	if addConstraint == nil || addConstraint.Constraint == nil || (addConstraint.Constraint.Attribute == "" && addConstraint.Constraint.Value == "") {
+		return fmt.Errorf("mutator %q requires an add_constraint block with a constraint with attribute or value", name)
+	}
+	return validateTarget("mutator", name, addConstraint.Target, addConstraint.Scope)
+}
+
+func validateResources(kind, name, block string, resources *TaskResources) error {
+	if resources == nil {
+		return nil
+	}
+	fields := []string{"cpu", "cores", "memory_mb", "memory_max_mb"}
+	for i, value := range []*int{resources.CPU, resources.Cores, resources.MemoryMB, resources.MemoryMaxMB} {
+		if value != nil && *value < 0 {
+			return fmt.Errorf("%s %q %s %s must not be negative", kind, name, block, fields[i])
+		}
+	}
+	return nil
+}
+
+// validateTarget checks a native controller's target and that a scope is
+// only used with targets below the job.
+func validateTarget(kind, name, target string, scope *Scope) error {
+	if target != "" && !slices.Contains(validTargets, target) {
+		return fmt.Errorf("%s %q has an unknown target %q", kind, name, target)
+	}
+	if scope != nil && (target == "" || target == TargetJob) {
+		return fmt.Errorf("%s %q has a scope but targets the job", kind, name)
+	}
+	if scope != nil && len(scope.Tasks) > 0 && target != TargetTask {
+		return fmt.Errorf("%s %q scopes tasks but does not target tasks", kind, name)
+	}
+	return validateScope(kind, name, scope)
+}
+
+func validateScope(kind, name string, scope *Scope) error {
+	if scope == nil {
+		return nil
+	}
+	for _, pattern := range append(slices.Clone(scope.TaskGroups), scope.Tasks...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s %q scope has an invalid pattern %q: %w", kind, name, pattern, err)
+		}
+	}
+	return nil
+}
+
 func validateValidator(validator Validator, hasOpaSDK bool) error {
 	if strings.TrimSpace(validator.Name) == "" {
 		return fmt.Errorf("validator name is required")
 	}
+	if err := validateOperations("validator", validator.Name, validator.Operations, validValidatorOperations); err != nil {
+		return err
+	}
+	if err := validateEnforcement(validator); err != nil {
+		return err
+	}
+	if err := validateMode("validator", validator.Name, validator.Mode); err != nil {
+		return err
+	}
+	if err := validateMatch("validator", validator.Name, validator.Match); err != nil {
+		return err
+	}
+	if err := validateTimeout("validator", validator.Name, "timeout", validator.Timeout); err != nil {
+		return err
+	}
+	if err := validateFailurePolicy("validator", validator.Name, validator.FailurePolicy); err != nil {
+		return err
+	}
 	switch validator.Type {
 	case "opa":
 		if err := validateOpaRule("validator", validator.Name, validator.OpaRule); err != nil {
@@ -340,11 +886,209 @@ func validateValidator(validator Validator, hasOpaSDK bool) error {
 		return validateWebhook("validator", validator.Name, validator.Webhook)
 	case "notation":
 		return validateNotation("validator", validator.Name, validator.Notation)
+	case "resource_limits":
+		return validateResourceLimits(validator.Name, validator.ResourceLimits)
+	case "allowed_drivers":
+		if validator.AllowedDrivers == nil || len(validator.AllowedDrivers.Drivers) == 0 {
+			return fmt.Errorf("validator %q requires an allowed_drivers block with drivers", validator.Name)
+		}
+		return validateScope("validator", validator.Name, validator.AllowedDrivers.Scope)
+	case "required_meta":
+		if validator.RequiredMeta == nil || len(validator.RequiredMeta.Keys) == 0 {
+			return fmt.Errorf("validator %q requires a required_meta block with keys", validator.Name)
+		}
+		return validateTarget("validator", validator.Name, validator.RequiredMeta.Target, validator.RequiredMeta.Scope)
+	case "image_registries":
+		if validator.ImageRegistries == nil || len(validator.ImageRegistries.Registries) == 0 {
+			return fmt.Errorf("validator %q requires an image_registries block with registries", validator.Name)
+		}
+		return validateScope("validator", validator.Name, validator.ImageRegistries.Scope)
+	case "forbid_privileged":
+		if validator.ForbidPrivileged == nil {
+			return nil
+		}
+		return validateScope("validator", validator.Name, validator.ForbidPrivileged.Scope)
+	case "cel":
+		if validator.Cel == nil || len(validator.Cel.Validations) == 0 {
+			return fmt.Errorf("validator %q requires a cel block with at least one validation block", validator.Name)
+		}
+		for _, validation := range validator.Cel.Validations {
+			if _, err := celutil.CompileCondition(validation.Expression); err != nil {
+				return fmt.Errorf("validator %q: %w", validator.Name, err)
+			}
+		}
+		return nil
+	case "json_schema":
+		return validateJSONSchema(validator)
+	case "wasm":
+		return validateWasm("validator", validator.Name, validator.Wasm)
+	case "exec":
+		return validateExec("validator", validator.Name, validator.Exec)
+	case "grpc_validator":
+		return validateGrpc("validator", validator.Name, validator.Grpc)
+	case "max_parallel":
+		return validateMaxParallel(validator.Name, validator.MaxParallel)
 	default:
 		return fmt.Errorf("unknown validator type %q", validator.Type)
 	}
 }
 
+func validateJSONSchema(validator Validator) error {
+	if validator.JSONSchema == nil || len(validator.JSONSchema.Files) == 0 {
+		return fmt.Errorf("validator %q requires a json_schema block with files", validator.Name)
+	}
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	for _, file := range validator.JSONSchema.Files {
+		if _, err := compiler.Compile(file); err != nil {
+			return fmt.Errorf("validator %q has an invalid schema %q: %w", validator.Name, file, err)
+		}
+	}
+	return nil
+}
+
+func validateWasm(kind, name string, wasm *Wasm) error {
+	if wasm == nil || strings.TrimSpace(wasm.Module) == "" {
+		return fmt.Errorf("%s %q requires a wasm block with module", kind, name)
+	}
+	if wasm.MemoryLimitMB != nil && (*wasm.MemoryLimitMB < 1 || *wasm.MemoryLimitMB > 4096) {
+		return fmt.Errorf("%s %q wasm memory_limit_mb must be between 1 and 4096", kind, name)
+	}
+	if wasm.PoolSize < 0 {
+		return fmt.Errorf("%s %q wasm pool_size must not be negative", kind, name)
+	}
+	return validateTimeout(kind, name, "wasm call_timeout", wasm.CallTimeout)
+}
+
+func validateExec(kind, name string, exec *Exec) error {
+	if exec == nil || strings.TrimSpace(exec.Command) == "" {
+		return fmt.Errorf("%s %q requires an exec block with command", kind, name)
+	}
+	for _, code := range exec.RejectExitCodes {
+		if code < 1 || code > 255 {
+			return fmt.Errorf("%s %q exec reject_exit_codes must be between 1 and 255", kind, name)
+		}
+	}
+	if exec.Persistent && len(exec.RejectExitCodes) > 0 {
+		return fmt.Errorf("%s %q exec reject_exit_codes cannot be used with a persistent process", kind, name)
+	}
+	return validateTimeout(kind, name, "exec call_timeout", exec.CallTimeout)
+}
+
+func validateGrpc(kind, name string, grpc *Grpc) error {
+	if grpc == nil || strings.TrimSpace(grpc.Address) == "" {
+		return fmt.Errorf("%s %q requires a grpc block with address", kind, name)
+	}
+	if grpc.TLS != nil && (grpc.TLS.CertFile == "") != (grpc.TLS.KeyFile == "") {
+		return fmt.Errorf("%s %q grpc TLS cert_file and key_file must be configured together", kind, name)
+	}
+	return validateTimeout(kind, name, "grpc call_timeout", grpc.CallTimeout)
+}
+
+func validateResourceLimits(name string, resourceLimits *ResourceLimits) error {
+	if resourceLimits == nil {
+		return fmt.Errorf("validator %q requires a resource_limits block with limits", name)
+	}
+	limits := resourceLimits.Limits
+	if limits == nil || (limits.CPU == nil && limits.Cores == nil && limits.MemoryMB == nil && limits.MemoryMaxMB == nil) {
+		return fmt.Errorf("validator %q requires a limits block with at least one value", name)
+	}
+	if err := validateResources("validator", name, "limits", limits); err != nil {
+		return err
+	}
+	return validateScope("validator", name, resourceLimits.Scope)
+}
+
+func validateMaxParallel(name string, maxParallel *MaxParallel) error {
+	if maxParallel == nil || maxParallel.Max < 0 {
+		return fmt.Errorf("validator %q requires a max_parallel block with a max that is not negative", name)
+	}
+	if maxParallel.Scope != nil && len(maxParallel.Scope.Tasks) > 0 {
+		return fmt.Errorf("validator %q scopes tasks but checks task groups", name)
+	}
+	return validateScope("validator", name, maxParallel.Scope)
+}
+
+func validateOperations(kind, name string, operations, valid []string) error {
+	for _, operation := range operations {
+		if !slices.Contains(valid, operation) {
+			return fmt.Errorf("%s %q has an unknown operation %q", kind, name, operation)
+		}
+	}
+	return nil
+}
+
+func validateMatch(kind, name string, match *Match) error {
+	if match == nil {
+		return nil
+	}
+	for _, pattern := range match.Namespaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s %q match has an invalid namespace pattern %q: %w", kind, name, pattern, err)
+		}
+	}
+	for _, jobType := range match.JobTypes {
+		if !slices.Contains(validJobTypes, jobType) {
+			return fmt.Errorf("%s %q match has an unknown job type %q", kind, name, jobType)
+		}
+	}
+	if match.JobID != "" {
+		if _, err := regexp.Compile(match.JobID); err != nil {
+			return fmt.Errorf("%s %q match has an invalid job_id pattern: %w", kind, name, err)
+		}
+	}
+	return nil
+}
+
+// validateTimeout checks the optional duration set for attribute, for example
+// "timeout" or "wasm call_timeout".
+func validateTimeout(kind, name, attribute, timeout string) error {
+	if timeout == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(timeout)
+	if err != nil {
+		return fmt.Errorf("%s %q has an invalid %s: %w", kind, name, attribute, err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("%s %q %s must be positive", kind, name, attribute)
+	}
+	return nil
+}
+
+func validateFailurePolicy(kind, name, policy string) error {
+	if policy != "" && !slices.Contains(validFailurePolicies, policy) {
+		return fmt.Errorf("%s %q has an unknown failure_policy %q", kind, name, policy)
+	}
+	return nil
+}
+
+func validateMode(kind, name, mode string) error {
+	if mode == "" || slices.Contains(validModes, mode) {
+		return nil
+	}
+	if name == "" {
+		return fmt.Errorf("unknown %s mode %q", kind, mode)
+	}
+	return fmt.Errorf("%s %q has an unknown mode %q", kind, name, mode)
+}
+
+func validateEnforcement(validator Validator) error {
+	if validator.EnforcementLevel != "" && !slices.Contains(validEnforcementLevels, validator.EnforcementLevel) {
+		return fmt.Errorf("validator %q has an unknown enforcement_level %q", validator.Name, validator.EnforcementLevel)
+	}
+	if validator.Override == nil {
+		return nil
+	}
+	if validator.EnforcementLevel != EnforcementSoftMandatory {
+		return fmt.Errorf("validator %q has an override block but is not soft-mandatory", validator.Name)
+	}
+	if len(validator.Override.Policies) == 0 && len(validator.Override.Roles) == 0 {
+		return fmt.Errorf("validator %q override requires policies or roles", validator.Name)
+	}
+	return nil
+}
+
 func validateOpaRule(kind, name string, rule *OpaRule) error {
 	if rule == nil || strings.TrimSpace(rule.Filename) == "" || strings.TrimSpace(rule.Query) == "" {
 		return fmt.Errorf("%s %q requires an opa_rule block with filename and query", kind, name)
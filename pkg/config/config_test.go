@@ -341,6 +341,13 @@ func TestConfigValidation(t *testing.T) {
 			},
 			wantErr: "requires a webhook block",
 		},
+		{
+			name: "webhook mutator without webhook block",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "webhook", Name: "remote"}}
+			},
+			wantErr: `mutator "remote" requires a webhook block`,
+		},
 		{
 			name: "bundle controller without SDK",
 			mutate: func(c *Config) {
@@ -456,6 +463,377 @@ func TestConfigValidation(t *testing.T) {
 			},
 			wantErr: "requires an opa_sdk_rule block with path",
 		},
+		{
+			name: "validator with an unknown operation",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "opa", Name: "policy", Operations: []string{"dispatch", "stop"}, OpaRule: &OpaRule{Filename: "rule.rego", Query: "errors"}}}
+			},
+			wantErr: `validator "policy" has an unknown operation "stop"`,
+		},
+		{
+			name: "mutator with a validator only operation",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "opa_json_patch", Name: "patch", Operations: []string{"dispatch"}, OpaRule: &OpaRule{Filename: "rule.rego", Query: "patch"}}}
+			},
+			wantErr: `mutator "patch" has an unknown operation "dispatch"`,
+		},
+		{
+			name: "validator with an unknown enforcement level",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "opa", Name: "policy", EnforcementLevel: "mandatory", OpaRule: &OpaRule{Filename: "rule.rego", Query: "errors"}}}
+			},
+			wantErr: `validator "policy" has an unknown enforcement_level "mandatory"`,
+		},
+		{
+			name: "override on a hard-mandatory validator",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "opa", Name: "policy", Override: &PolicyOverride{Policies: []string{"ops"}}, OpaRule: &OpaRule{Filename: "rule.rego", Query: "errors"}}}
+			},
+			wantErr: `validator "policy" has an override block but is not soft-mandatory`,
+		},
+		{
+			name: "override without policies or roles",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "opa", Name: "policy", EnforcementLevel: EnforcementSoftMandatory, Override: &PolicyOverride{}, OpaRule: &OpaRule{Filename: "rule.rego", Query: "errors"}}}
+			},
+			wantErr: `validator "policy" override requires policies or roles`,
+		},
+		{
+			name: "unknown global mode",
+			mutate: func(c *Config) {
+				c.Mode = "dry-run"
+			},
+			wantErr: `unknown global mode "dry-run"`,
+		},
+		{
+			name: "mutator with an unknown mode",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "opa_json_patch", Name: "patch", Mode: "dry-run", OpaRule: &OpaRule{Filename: "rule.rego", Query: "patch"}}}
+			},
+			wantErr: `mutator "patch" has an unknown mode "dry-run"`,
+		},
+		{
+			name: "match with an invalid namespace pattern",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "opa", Name: "policy", Match: &Match{Namespaces: []string{"team-["}}, OpaRule: &OpaRule{Filename: "rule.rego", Query: "errors"}}}
+			},
+			wantErr: `validator "policy" match has an invalid namespace pattern "team-["`,
+		},
+		{
+			name: "match with an unknown job type",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "opa_json_patch", Name: "patch", Match: &Match{JobTypes: []string{"cron"}}, OpaRule: &OpaRule{Filename: "rule.rego", Query: "patch"}}}
+			},
+			wantErr: `mutator "patch" match has an unknown job type "cron"`,
+		},
+		{
+			name: "match with an invalid job id pattern",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "opa", Name: "policy", Match: &Match{JobID: "web-("}, OpaRule: &OpaRule{Filename: "rule.rego", Query: "errors"}}}
+			},
+			wantErr: `validator "policy" match has an invalid job_id pattern`,
+		},
+		{
+			name: "negative max_concurrency",
+			mutate: func(c *Config) {
+				c.MaxConcurrency = -1
+			},
+			wantErr: "max_concurrency must not be negative",
+		},
+		{
+			name: "validator with an invalid timeout",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "opa", Name: "policy", Timeout: "soon", OpaRule: &OpaRule{Filename: "rule.rego", Query: "errors"}}}
+			},
+			wantErr: `validator "policy" has an invalid timeout`,
+		},
+		{
+			name: "validator with a zero timeout",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "opa", Name: "policy", Timeout: "0s", OpaRule: &OpaRule{Filename: "rule.rego", Query: "errors"}}}
+			},
+			wantErr: `validator "policy" timeout must be positive`,
+		},
+		{
+			name: "mutator with an invalid timeout",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "opa_json_patch", Name: "patch", Timeout: "5", OpaRule: &OpaRule{Filename: "rule.rego", Query: "patch"}}}
+			},
+			wantErr: `mutator "patch" has an invalid timeout`,
+		},
+		{
+			name: "validator with an unknown failure policy",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "opa", Name: "policy", FailurePolicy: "open", OpaRule: &OpaRule{Filename: "rule.rego", Query: "errors"}}}
+			},
+			wantErr: `validator "policy" has an unknown failure_policy "open"`,
+		},
+		{
+			name: "mutator with an unknown reinvocation policy",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "opa_json_patch", Name: "patch", ReinvocationPolicy: "always", OpaRule: &OpaRule{Filename: "rule.rego", Query: "patch"}}}
+			},
+			wantErr: `mutator "patch" has an unknown reinvocation_policy "always"`,
+		},
+		{
+			name: "json_schema validator without files",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "json_schema", Name: "schema", JSONSchema: &JSONSchema{}}}
+			},
+			wantErr: `validator "schema" requires a json_schema block with files`,
+		},
+		{
+			name: "json_schema validator with an invalid schema",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "json_schema", Name: "schema", JSONSchema: &JSONSchema{Files: []string{"../../testdata/jsonschema/invalid.json"}}}}
+			},
+			wantErr: `validator "schema" has an invalid schema "../../testdata/jsonschema/invalid.json"`,
+		},
+		{
+			name: "json_schema validator with a missing schema",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "json_schema", Name: "schema", JSONSchema: &JSONSchema{Files: []string{"missing.json"}}}}
+			},
+			wantErr: `validator "schema" has an invalid schema "missing.json"`,
+		},
+		{
+			name: "cel validator without validations",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "cel", Name: "cel"}}
+			},
+			wantErr: `validator "cel" requires a cel block with at least one validation block`,
+		},
+		{
+			name: "cel validator with a syntax error",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "cel", Name: "cel", Cel: &CelValidator{Validations: []CelValidation{{Expression: "job.ID =="}}}}}
+			},
+			wantErr: `validator "cel": invalid expression "job.ID =="`,
+		},
+		{
+			name: "cel validator with a non bool expression",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "cel", Name: "cel", Cel: &CelValidator{Validations: []CelValidation{{Expression: `"yes"`}}}}}
+			},
+			wantErr: `validator "cel": expression "\"yes\"" evaluates to string, not bool`,
+		},
+		{
+			name: "cel mutator with an undeclared variable",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "cel", Name: "cel", Cel: &CelMutator{Mutations: []CelMutation{{Path: "/Meta/owner", Expression: "token.Name"}}}}}
+			},
+			wantErr: `mutator "cel": invalid expression "token.Name"`,
+		},
+		{
+			name: "cel mutator with a relative path",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "cel", Name: "cel", Cel: &CelMutator{Mutations: []CelMutation{{Path: "Meta/owner", Expression: `"x"`}}}}}
+			},
+			wantErr: `mutator "cel" mutation path "Meta/owner" must be a JSON pointer`,
+		},
+		{
+			name: "wasm validator without a module",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "wasm", Name: "wasm", Wasm: &Wasm{}}}
+			},
+			wantErr: `validator "wasm" requires a wasm block with module`,
+		},
+		{
+			name: "wasm validator with a too large memory limit",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "wasm", Name: "wasm", Wasm: &Wasm{Module: "policy.wasm", MemoryLimitMB: Ptr(8192)}}}
+			},
+			wantErr: `validator "wasm" wasm memory_limit_mb must be between 1 and 4096`,
+		},
+		{
+			name: "wasm validator with a zero memory limit",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "wasm", Name: "wasm", Wasm: &Wasm{Module: "policy.wasm", MemoryLimitMB: Ptr(0)}}}
+			},
+			wantErr: `validator "wasm" wasm memory_limit_mb must be between 1 and 4096`,
+		},
+		{
+			name: "wasm mutator with an invalid call timeout",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "wasm", Name: "wasm", Wasm: &Wasm{Module: "policy.wasm", CallTimeout: "soon"}}}
+			},
+			wantErr: `mutator "wasm" has an invalid wasm call_timeout`,
+		},
+		{
+			name: "wasm mutator with a negative pool size",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "wasm", Name: "wasm", Wasm: &Wasm{Module: "policy.wasm", PoolSize: -1}}}
+			},
+			wantErr: `mutator "wasm" wasm pool_size must not be negative`,
+		},
+		{
+			name: "exec validator without a command",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "exec", Name: "exec", Exec: &Exec{}}}
+			},
+			wantErr: `validator "exec" requires an exec block with command`,
+		},
+		{
+			name: "exec validator with an invalid reject exit code",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "exec", Name: "exec", Exec: &Exec{Command: "check", RejectExitCodes: []int{0}}}}
+			},
+			wantErr: `validator "exec" exec reject_exit_codes must be between 1 and 255`,
+		},
+		{
+			name: "exec mutator with reject exit codes and a persistent process",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "exec", Name: "exec", Exec: &Exec{Command: "check", RejectExitCodes: []int{1}, Persistent: true}}}
+			},
+			wantErr: `mutator "exec" exec reject_exit_codes cannot be used with a persistent process`,
+		},
+		{
+			name: "exec mutator with a negative call timeout",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "exec", Name: "exec", Exec: &Exec{Command: "check", CallTimeout: "-1s"}}}
+			},
+			wantErr: `mutator "exec" exec call_timeout must be positive`,
+		},
+		{
+			name: "grpc validator without an address",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "grpc_validator", Name: "grpc", Grpc: &Grpc{}}}
+			},
+			wantErr: `validator "grpc" requires a grpc block with address`,
+		},
+		{
+			name: "grpc validator with a cert but no key",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "grpc_validator", Name: "grpc", Grpc: &Grpc{Address: "localhost:9090", TLS: &GrpcTLS{CertFile: "client.pem"}}}}
+			},
+			wantErr: `validator "grpc" grpc TLS cert_file and key_file must be configured together`,
+		},
+		{
+			name: "grpc mutator with an invalid call timeout",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "grpc_mutator", Name: "grpc", Grpc: &Grpc{Address: "localhost:9090", CallTimeout: "1 minute"}}}
+			},
+			wantErr: `mutator "grpc" has an invalid grpc call_timeout`,
+		},
+		{
+			name: "resource_limits validator without limits",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "resource_limits", Name: "limits", ResourceLimits: &ResourceLimits{Limits: &TaskResources{}}}}
+			},
+			wantErr: `validator "limits" requires a limits block with at least one value`,
+		},
+		{
+			name: "resource_limits validator with negative limits",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "resource_limits", Name: "limits", ResourceLimits: &ResourceLimits{Limits: &TaskResources{CPU: Ptr(-5)}}}}
+			},
+			wantErr: `validator "limits" limits cpu must not be negative`,
+		},
+		{
+			name: "allowed_drivers validator without drivers",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "allowed_drivers", Name: "drivers"}}
+			},
+			wantErr: `validator "drivers" requires an allowed_drivers block with drivers`,
+		},
+		{
+			name: "required_meta validator with a scope on the job",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "required_meta", Name: "meta", RequiredMeta: &RequiredMeta{Keys: []string{"owner"}, Scope: &Scope{TaskGroups: []string{"web"}}}}}
+			},
+			wantErr: `validator "meta" has a scope but targets the job`,
+		},
+		{
+			name: "image_registries validator without registries",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "image_registries", Name: "registries"}}
+			},
+			wantErr: `validator "registries" requires an image_registries block with registries`,
+		},
+		{
+			name: "max_parallel validator with a negative max",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "max_parallel", Name: "parallel", MaxParallel: &MaxParallel{Max: -1}}}
+			},
+			wantErr: `validator "parallel" requires a max_parallel block with a max that is not negative`,
+		},
+		{
+			name: "max_parallel validator scoping tasks",
+			mutate: func(c *Config) {
+				c.Validators = []Validator{{Type: "max_parallel", Name: "parallel", MaxParallel: &MaxParallel{Max: 1, Scope: &Scope{Tasks: []string{"app"}}}}}
+			},
+			wantErr: `validator "parallel" scopes tasks but checks task groups`,
+		},
+		{
+			name: "defaults mutator without values",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "defaults", Name: "defaults", Defaults: &JobDefaults{}}}
+			},
+			wantErr: `mutator "defaults" requires a defaults block with at least one value`,
+		},
+		{
+			name: "defaults mutator with negative resources",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "defaults", Name: "defaults", Defaults: &JobDefaults{Resources: &TaskResources{MemoryMB: Ptr(-1)}}}}
+			},
+			wantErr: `mutator "defaults" defaults memory_mb must not be negative`,
+		},
+		{
+			name: "inject_meta mutator without meta",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "inject_meta", Name: "meta", InjectMeta: &InjectMeta{}}}
+			},
+			wantErr: `mutator "meta" requires an inject_meta block with meta`,
+		},
+		{
+			name: "inject_meta mutator with an unknown target",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "inject_meta", Name: "meta", InjectMeta: &InjectMeta{Meta: map[string]string{"a": "b"}, Target: "alloc"}}}
+			},
+			wantErr: `mutator "meta" has an unknown target "alloc"`,
+		},
+		{
+			name: "add_constraint mutator without a constraint",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "add_constraint", Name: "linux", AddConstraint: &AddConstraint{Constraint: &Constraint{Operator: "="}}}}
+			},
+			wantErr: `mutator "linux" requires an add_constraint block with a constraint with attribute or value`,
+		},
+		{
+			name: "scope on a job target",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "inject_meta", Name: "meta", InjectMeta: &InjectMeta{Meta: map[string]string{"a": "b"}, Scope: &Scope{TaskGroups: []string{"web"}}}}}
+			},
+			wantErr: `mutator "meta" has a scope but targets the job`,
+		},
+		{
+			name: "task scope on a group target",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "add_constraint", Name: "linux", AddConstraint: &AddConstraint{Constraint: &Constraint{Attribute: "a", Value: "b"}, Target: "group", Scope: &Scope{Tasks: []string{"app"}}}}}
+			},
+			wantErr: `mutator "linux" scopes tasks but does not target tasks`,
+		},
+		{
+			name: "scope with an invalid pattern",
+			mutate: func(c *Config) {
+				c.Mutators = []Mutator{{Type: "defaults", Name: "defaults", Defaults: &JobDefaults{Namespace: "a", Scope: &Scope{TaskGroups: []string{"["}}}}}
+			},
+			wantErr: `mutator "defaults" scope has an invalid pattern "["`,
+		},
+		{
+			name: "negative max mutator passes",
+			mutate: func(c *Config) {
+				c.MaxMutatorPasses = -1
+			},
+			wantErr: "max_mutator_passes must not be negative",
+		},
+		{
+			name: "unknown submission strategy",
+			mutate: func(c *Config) {
+				c.Submission = &Submission{Strategy: "rewrite"}
+			},
+			wantErr: `unknown submission strategy "rewrite"`,
+		},
 		{
 			name: "webhook without a method",
 			mutate: func(c *Config) {
@@ -641,3 +1019,96 @@ func TestLoadConfigDefaults(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConfigNativeMutators(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.hcl")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+mutator "defaults" "team-defaults" {
+	defaults {
+		namespace   = "team-a"
+		datacenters = ["dc1"]
+		resources {
+			cpu       = 100
+			memory_mb = 128
+		}
+		scope {
+			task_groups = ["web-*"]
+		}
+	}
+}
+mutator "inject_meta" "owner" {
+	inject_meta {
+		meta = {
+			owner = "team-a"
+		}
+		target    = "task"
+		overwrite = true
+	}
+}
+mutator "add_constraint" "linux" {
+	add_constraint {
+		constraint {
+			attribute = "$${attr.kernel.name}"
+			value     = "linux"
+		}
+		target = "group"
+	}
+}
+`), 0644))
+
+	c, err := LoadConfig(configFile)
+	require.NoError(t, err)
+	require.Len(t, c.Mutators, 3)
+	assert.Equal(t, &JobDefaults{
+		Namespace:   "team-a",
+		Datacenters: []string{"dc1"},
+		Resources:   &TaskResources{CPU: Ptr(100), MemoryMB: Ptr(128)},
+		Scope:       &Scope{TaskGroups: []string{"web-*"}},
+	}, c.Mutators[0].Defaults)
+	assert.Equal(t, &InjectMeta{Meta: map[string]string{"owner": "team-a"}, Target: TargetTask, Overwrite: true}, c.Mutators[1].InjectMeta)
+	assert.Equal(t, &AddConstraint{
+		Constraint: &Constraint{Attribute: "${attr.kernel.name}", Value: "linux"},
+		Target:     TargetGroup,
+	}, c.Mutators[2].AddConstraint)
+}
+
+func TestLoadConfigNativeValidators(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.hcl")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+validator "resource_limits" "ceilings" {
+	resource_limits {
+		limits {
+			memory_mb = 4096
+		}
+		message = "too large"
+	}
+}
+validator "required_meta" "owner" {
+	required_meta {
+		keys   = ["owner"]
+		target = "group"
+		scope {
+			task_groups = ["web-*"]
+		}
+	}
+}
+validator "forbid_privileged" "unprivileged" {}
+validator "max_parallel" "rollouts" {
+	max_parallel {
+		max = 2
+	}
+}
+`), 0644))
+
+	c, err := LoadConfig(configFile)
+	require.NoError(t, err)
+	require.Len(t, c.Validators, 4)
+	assert.Equal(t, &ResourceLimits{Limits: &TaskResources{MemoryMB: Ptr(4096)}, Message: "too large"}, c.Validators[0].ResourceLimits)
+	assert.Equal(t, &RequiredMeta{
+		Keys:   []string{"owner"},
+		Target: TargetGroup,
+		Scope:  &Scope{TaskGroups: []string{"web-*"}},
+	}, c.Validators[1].RequiredMeta)
+	assert.Nil(t, c.Validators[2].ForbidPrivileged)
+	assert.Equal(t, &MaxParallel{Max: 2}, c.Validators[3].MaxParallel)
+}
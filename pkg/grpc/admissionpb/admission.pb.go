@@ -0,0 +1,919 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: admission.proto
+
+package admissionpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AdmissionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// job is the JSON encoding of the Nomad API job.
+	Job     []byte          `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	Context *RequestContext `protobuf:"bytes,2,opt,name=context,proto3" json:"context,omitempty"`
+	// operation is register, plan, dispatch, deregister or scale.
+	Operation     string      `protobuf:"bytes,3,opt,name=operation,proto3" json:"operation,omitempty"`
+	Dispatch      *Dispatch   `protobuf:"bytes,4,opt,name=dispatch,proto3" json:"dispatch,omitempty"`
+	Deregister    *Deregister `protobuf:"bytes,5,opt,name=deregister,proto3" json:"deregister,omitempty"`
+	Scale         *Scale      `protobuf:"bytes,6,opt,name=scale,proto3" json:"scale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdmissionRequest) Reset() {
+	*x = AdmissionRequest{}
+	mi := &file_admission_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdmissionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdmissionRequest) ProtoMessage() {}
+
+func (x *AdmissionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admission_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdmissionRequest.ProtoReflect.Descriptor instead.
+func (*AdmissionRequest) Descriptor() ([]byte, []int) {
+	return file_admission_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AdmissionRequest) GetJob() []byte {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+func (x *AdmissionRequest) GetContext() *RequestContext {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+func (x *AdmissionRequest) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *AdmissionRequest) GetDispatch() *Dispatch {
+	if x != nil {
+		return x.Dispatch
+	}
+	return nil
+}
+
+func (x *AdmissionRequest) GetDeregister() *Deregister {
+	if x != nil {
+		return x.Deregister
+	}
+	return nil
+}
+
+func (x *AdmissionRequest) GetScale() *Scale {
+	if x != nil {
+		return x.Scale
+	}
+	return nil
+}
+
+// RequestContext describes the client of the request.
+type RequestContext struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ClientIp       string                 `protobuf:"bytes,1,opt,name=client_ip,json=clientIp,proto3" json:"client_ip,omitempty"`
+	AccessorId     string                 `protobuf:"bytes,2,opt,name=accessor_id,json=accessorId,proto3" json:"accessor_id,omitempty"`
+	ResolveToken   bool                   `protobuf:"varint,3,opt,name=resolve_token,json=resolveToken,proto3" json:"resolve_token,omitempty"`
+	TokenInfo      *TokenInfo             `protobuf:"bytes,4,opt,name=token_info,json=tokenInfo,proto3" json:"token_info,omitempty"`
+	PolicyOverride bool                   `protobuf:"varint,5,opt,name=policy_override,json=policyOverride,proto3" json:"policy_override,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RequestContext) Reset() {
+	*x = RequestContext{}
+	mi := &file_admission_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestContext) ProtoMessage() {}
+
+func (x *RequestContext) ProtoReflect() protoreflect.Message {
+	mi := &file_admission_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestContext.ProtoReflect.Descriptor instead.
+func (*RequestContext) Descriptor() ([]byte, []int) {
+	return file_admission_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RequestContext) GetClientIp() string {
+	if x != nil {
+		return x.ClientIp
+	}
+	return ""
+}
+
+func (x *RequestContext) GetAccessorId() string {
+	if x != nil {
+		return x.AccessorId
+	}
+	return ""
+}
+
+func (x *RequestContext) GetResolveToken() bool {
+	if x != nil {
+		return x.ResolveToken
+	}
+	return false
+}
+
+func (x *RequestContext) GetTokenInfo() *TokenInfo {
+	if x != nil {
+		return x.TokenInfo
+	}
+	return nil
+}
+
+func (x *RequestContext) GetPolicyOverride() bool {
+	if x != nil {
+		return x.PolicyOverride
+	}
+	return false
+}
+
+// TokenInfo is the ACL token of the client, without its secret.
+type TokenInfo struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AccessorId     string                 `protobuf:"bytes,1,opt,name=accessor_id,json=accessorId,proto3" json:"accessor_id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type           string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Policies       []string               `protobuf:"bytes,4,rep,name=policies,proto3" json:"policies,omitempty"`
+	Roles          []*RoleLink            `protobuf:"bytes,5,rep,name=roles,proto3" json:"roles,omitempty"`
+	Global         bool                   `protobuf:"varint,6,opt,name=global,proto3" json:"global,omitempty"`
+	CreateTime     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	ExpirationTime *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=expiration_time,json=expirationTime,proto3" json:"expiration_time,omitempty"`
+	CreateIndex    uint64                 `protobuf:"varint,9,opt,name=create_index,json=createIndex,proto3" json:"create_index,omitempty"`
+	ModifyIndex    uint64                 `protobuf:"varint,10,opt,name=modify_index,json=modifyIndex,proto3" json:"modify_index,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TokenInfo) Reset() {
+	*x = TokenInfo{}
+	mi := &file_admission_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenInfo) ProtoMessage() {}
+
+func (x *TokenInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_admission_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenInfo.ProtoReflect.Descriptor instead.
+func (*TokenInfo) Descriptor() ([]byte, []int) {
+	return file_admission_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TokenInfo) GetAccessorId() string {
+	if x != nil {
+		return x.AccessorId
+	}
+	return ""
+}
+
+func (x *TokenInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TokenInfo) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TokenInfo) GetPolicies() []string {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+func (x *TokenInfo) GetRoles() []*RoleLink {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *TokenInfo) GetGlobal() bool {
+	if x != nil {
+		return x.Global
+	}
+	return false
+}
+
+func (x *TokenInfo) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *TokenInfo) GetExpirationTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpirationTime
+	}
+	return nil
+}
+
+func (x *TokenInfo) GetCreateIndex() uint64 {
+	if x != nil {
+		return x.CreateIndex
+	}
+	return 0
+}
+
+func (x *TokenInfo) GetModifyIndex() uint64 {
+	if x != nil {
+		return x.ModifyIndex
+	}
+	return 0
+}
+
+type RoleLink struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoleLink) Reset() {
+	*x = RoleLink{}
+	mi := &file_admission_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoleLink) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoleLink) ProtoMessage() {}
+
+func (x *RoleLink) ProtoReflect() protoreflect.Message {
+	mi := &file_admission_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoleLink.ProtoReflect.Descriptor instead.
+func (*RoleLink) Descriptor() ([]byte, []int) {
+	return file_admission_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RoleLink) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RoleLink) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Dispatch describes a dispatch of the parameterized job.
+type Dispatch struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Meta             map[string]string      `protobuf:"bytes,1,rep,name=meta,proto3" json:"meta,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	PayloadSize      int64                  `protobuf:"varint,2,opt,name=payload_size,json=payloadSize,proto3" json:"payload_size,omitempty"`
+	IdPrefixTemplate string                 `protobuf:"bytes,3,opt,name=id_prefix_template,json=idPrefixTemplate,proto3" json:"id_prefix_template,omitempty"`
+	Priority         int64                  `protobuf:"varint,4,opt,name=priority,proto3" json:"priority,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Dispatch) Reset() {
+	*x = Dispatch{}
+	mi := &file_admission_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Dispatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dispatch) ProtoMessage() {}
+
+func (x *Dispatch) ProtoReflect() protoreflect.Message {
+	mi := &file_admission_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dispatch.ProtoReflect.Descriptor instead.
+func (*Dispatch) Descriptor() ([]byte, []int) {
+	return file_admission_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Dispatch) GetMeta() map[string]string {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *Dispatch) GetPayloadSize() int64 {
+	if x != nil {
+		return x.PayloadSize
+	}
+	return 0
+}
+
+func (x *Dispatch) GetIdPrefixTemplate() string {
+	if x != nil {
+		return x.IdPrefixTemplate
+	}
+	return ""
+}
+
+func (x *Dispatch) GetPriority() int64 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+// Deregister holds the flags of a job deregistration.
+type Deregister struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Purge         bool                   `protobuf:"varint,1,opt,name=purge,proto3" json:"purge,omitempty"`
+	Global        bool                   `protobuf:"varint,2,opt,name=global,proto3" json:"global,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Deregister) Reset() {
+	*x = Deregister{}
+	mi := &file_admission_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Deregister) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Deregister) ProtoMessage() {}
+
+func (x *Deregister) ProtoReflect() protoreflect.Message {
+	mi := &file_admission_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Deregister.ProtoReflect.Descriptor instead.
+func (*Deregister) Descriptor() ([]byte, []int) {
+	return file_admission_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Deregister) GetPurge() bool {
+	if x != nil {
+		return x.Purge
+	}
+	return false
+}
+
+func (x *Deregister) GetGlobal() bool {
+	if x != nil {
+		return x.Global
+	}
+	return false
+}
+
+// Scale describes a scaling request for a task group.
+type Scale struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         string                 `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	PreviousCount int64                  `protobuf:"varint,3,opt,name=previous_count,json=previousCount,proto3" json:"previous_count,omitempty"`
+	// scaling is the JSON encoding of the Nomad API scaling policy.
+	Scaling       []byte           `protobuf:"bytes,4,opt,name=scaling,proto3" json:"scaling,omitempty"`
+	Message       string           `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	Meta          *structpb.Struct `protobuf:"bytes,6,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Scale) Reset() {
+	*x = Scale{}
+	mi := &file_admission_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Scale) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Scale) ProtoMessage() {}
+
+func (x *Scale) ProtoReflect() protoreflect.Message {
+	mi := &file_admission_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Scale.ProtoReflect.Descriptor instead.
+func (*Scale) Descriptor() ([]byte, []int) {
+	return file_admission_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Scale) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *Scale) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *Scale) GetPreviousCount() int64 {
+	if x != nil {
+		return x.PreviousCount
+	}
+	return 0
+}
+
+func (x *Scale) GetScaling() []byte {
+	if x != nil {
+		return x.Scaling
+	}
+	return nil
+}
+
+func (x *Scale) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Scale) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+// Violation is a policy error or warning.
+type Violation struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Code    string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	// path is a JSON pointer to the offending field of the job.
+	Path           string `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Severity       string `protobuf:"bytes,4,opt,name=severity,proto3" json:"severity,omitempty"`
+	RemediationUrl string `protobuf:"bytes,5,opt,name=remediation_url,json=remediationUrl,proto3" json:"remediation_url,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Violation) Reset() {
+	*x = Violation{}
+	mi := &file_admission_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Violation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Violation) ProtoMessage() {}
+
+func (x *Violation) ProtoReflect() protoreflect.Message {
+	mi := &file_admission_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Violation.ProtoReflect.Descriptor instead.
+func (*Violation) Descriptor() ([]byte, []int) {
+	return file_admission_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Violation) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Violation) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Violation) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Violation) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Violation) GetRemediationUrl() string {
+	if x != nil {
+		return x.RemediationUrl
+	}
+	return ""
+}
+
+type ValidateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Errors        []*Violation           `protobuf:"bytes,1,rep,name=errors,proto3" json:"errors,omitempty"`
+	Warnings      []*Violation           `protobuf:"bytes,2,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateResponse) Reset() {
+	*x = ValidateResponse{}
+	mi := &file_admission_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateResponse) ProtoMessage() {}
+
+func (x *ValidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admission_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateResponse.ProtoReflect.Descriptor instead.
+func (*ValidateResponse) Descriptor() ([]byte, []int) {
+	return file_admission_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ValidateResponse) GetErrors() []*Violation {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *ValidateResponse) GetWarnings() []*Violation {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+type MutateResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Errors   []*Violation           `protobuf:"bytes,1,rep,name=errors,proto3" json:"errors,omitempty"`
+	Warnings []*Violation           `protobuf:"bytes,2,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	// patch is a JSON array of RFC 6902 operations, applied after merge_patch.
+	Patch []byte `protobuf:"bytes,3,opt,name=patch,proto3" json:"patch,omitempty"`
+	// merge_patch is an RFC 7386 JSON merge patch object.
+	MergePatch    []byte `protobuf:"bytes,4,opt,name=merge_patch,json=mergePatch,proto3" json:"merge_patch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MutateResponse) Reset() {
+	*x = MutateResponse{}
+	mi := &file_admission_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MutateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MutateResponse) ProtoMessage() {}
+
+func (x *MutateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admission_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MutateResponse.ProtoReflect.Descriptor instead.
+func (*MutateResponse) Descriptor() ([]byte, []int) {
+	return file_admission_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *MutateResponse) GetErrors() []*Violation {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *MutateResponse) GetWarnings() []*Violation {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *MutateResponse) GetPatch() []byte {
+	if x != nil {
+		return x.Patch
+	}
+	return nil
+}
+
+func (x *MutateResponse) GetMergePatch() []byte {
+	if x != nil {
+		return x.MergePatch
+	}
+	return nil
+}
+
+var File_admission_proto protoreflect.FileDescriptor
+
+const file_admission_proto_rawDesc = "" +
+	"\n" +
+	"\x0fadmission.proto\x12\x11nacp.admission.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa7\x02\n" +
+	"\x10AdmissionRequest\x12\x10\n" +
+	"\x03job\x18\x01 \x01(\fR\x03job\x12;\n" +
+	"\acontext\x18\x02 \x01(\v2!.nacp.admission.v1.RequestContextR\acontext\x12\x1c\n" +
+	"\toperation\x18\x03 \x01(\tR\toperation\x127\n" +
+	"\bdispatch\x18\x04 \x01(\v2\x1b.nacp.admission.v1.DispatchR\bdispatch\x12=\n" +
+	"\n" +
+	"deregister\x18\x05 \x01(\v2\x1d.nacp.admission.v1.DeregisterR\n" +
+	"deregister\x12.\n" +
+	"\x05scale\x18\x06 \x01(\v2\x18.nacp.admission.v1.ScaleR\x05scale\"\xd9\x01\n" +
+	"\x0eRequestContext\x12\x1b\n" +
+	"\tclient_ip\x18\x01 \x01(\tR\bclientIp\x12\x1f\n" +
+	"\vaccessor_id\x18\x02 \x01(\tR\n" +
+	"accessorId\x12#\n" +
+	"\rresolve_token\x18\x03 \x01(\bR\fresolveToken\x12;\n" +
+	"\n" +
+	"token_info\x18\x04 \x01(\v2\x1c.nacp.admission.v1.TokenInfoR\ttokenInfo\x12'\n" +
+	"\x0fpolicy_override\x18\x05 \x01(\bR\x0epolicyOverride\"\x83\x03\n" +
+	"\tTokenInfo\x12\x1f\n" +
+	"\vaccessor_id\x18\x01 \x01(\tR\n" +
+	"accessorId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x1a\n" +
+	"\bpolicies\x18\x04 \x03(\tR\bpolicies\x121\n" +
+	"\x05roles\x18\x05 \x03(\v2\x1b.nacp.admission.v1.RoleLinkR\x05roles\x12\x16\n" +
+	"\x06global\x18\x06 \x01(\bR\x06global\x12;\n" +
+	"\vcreate_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\x12C\n" +
+	"\x0fexpiration_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x0eexpirationTime\x12!\n" +
+	"\fcreate_index\x18\t \x01(\x04R\vcreateIndex\x12!\n" +
+	"\fmodify_index\x18\n" +
+	" \x01(\x04R\vmodifyIndex\".\n" +
+	"\bRoleLink\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\xeb\x01\n" +
+	"\bDispatch\x129\n" +
+	"\x04meta\x18\x01 \x03(\v2%.nacp.admission.v1.Dispatch.MetaEntryR\x04meta\x12!\n" +
+	"\fpayload_size\x18\x02 \x01(\x03R\vpayloadSize\x12,\n" +
+	"\x12id_prefix_template\x18\x03 \x01(\tR\x10idPrefixTemplate\x12\x1a\n" +
+	"\bpriority\x18\x04 \x01(\x03R\bpriority\x1a7\n" +
+	"\tMetaEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\":\n" +
+	"\n" +
+	"Deregister\x12\x14\n" +
+	"\x05purge\x18\x01 \x01(\bR\x05purge\x12\x16\n" +
+	"\x06global\x18\x02 \x01(\bR\x06global\"\xbb\x01\n" +
+	"\x05Scale\x12\x14\n" +
+	"\x05group\x18\x01 \x01(\tR\x05group\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12%\n" +
+	"\x0eprevious_count\x18\x03 \x01(\x03R\rpreviousCount\x12\x18\n" +
+	"\ascaling\x18\x04 \x01(\fR\ascaling\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\x12+\n" +
+	"\x04meta\x18\x06 \x01(\v2\x17.google.protobuf.StructR\x04meta\"\x92\x01\n" +
+	"\tViolation\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\x12\x1a\n" +
+	"\bseverity\x18\x04 \x01(\tR\bseverity\x12'\n" +
+	"\x0fremediation_url\x18\x05 \x01(\tR\x0eremediationUrl\"\x82\x01\n" +
+	"\x10ValidateResponse\x124\n" +
+	"\x06errors\x18\x01 \x03(\v2\x1c.nacp.admission.v1.ViolationR\x06errors\x128\n" +
+	"\bwarnings\x18\x02 \x03(\v2\x1c.nacp.admission.v1.ViolationR\bwarnings\"\xb7\x01\n" +
+	"\x0eMutateResponse\x124\n" +
+	"\x06errors\x18\x01 \x03(\v2\x1c.nacp.admission.v1.ViolationR\x06errors\x128\n" +
+	"\bwarnings\x18\x02 \x03(\v2\x1c.nacp.admission.v1.ViolationR\bwarnings\x12\x14\n" +
+	"\x05patch\x18\x03 \x01(\fR\x05patch\x12\x1f\n" +
+	"\vmerge_patch\x18\x04 \x01(\fR\n" +
+	"mergePatch2\xb3\x01\n" +
+	"\tAdmission\x12T\n" +
+	"\bValidate\x12#.nacp.admission.v1.AdmissionRequest\x1a#.nacp.admission.v1.ValidateResponse\x12P\n" +
+	"\x06Mutate\x12#.nacp.admission.v1.AdmissionRequest\x1a!.nacp.admission.v1.MutateResponseB+Z)github.com/mxab/nacp/pkg/grpc/admissionpbb\x06proto3"
+
+var (
+	file_admission_proto_rawDescOnce sync.Once
+	file_admission_proto_rawDescData []byte
+)
+
+func file_admission_proto_rawDescGZIP() []byte {
+	file_admission_proto_rawDescOnce.Do(func() {
+		file_admission_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_admission_proto_rawDesc), len(file_admission_proto_rawDesc)))
+	})
+	return file_admission_proto_rawDescData
+}
+
+var file_admission_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_admission_proto_goTypes = []any{
+	(*AdmissionRequest)(nil),      // 0: nacp.admission.v1.AdmissionRequest
+	(*RequestContext)(nil),        // 1: nacp.admission.v1.RequestContext
+	(*TokenInfo)(nil),             // 2: nacp.admission.v1.TokenInfo
+	(*RoleLink)(nil),              // 3: nacp.admission.v1.RoleLink
+	(*Dispatch)(nil),              // 4: nacp.admission.v1.Dispatch
+	(*Deregister)(nil),            // 5: nacp.admission.v1.Deregister
+	(*Scale)(nil),                 // 6: nacp.admission.v1.Scale
+	(*Violation)(nil),             // 7: nacp.admission.v1.Violation
+	(*ValidateResponse)(nil),      // 8: nacp.admission.v1.ValidateResponse
+	(*MutateResponse)(nil),        // 9: nacp.admission.v1.MutateResponse
+	nil,                           // 10: nacp.admission.v1.Dispatch.MetaEntry
+	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),       // 12: google.protobuf.Struct
+}
+var file_admission_proto_depIdxs = []int32{
+	1,  // 0: nacp.admission.v1.AdmissionRequest.context:type_name -> nacp.admission.v1.RequestContext
+	4,  // 1: nacp.admission.v1.AdmissionRequest.dispatch:type_name -> nacp.admission.v1.Dispatch
+	5,  // 2: nacp.admission.v1.AdmissionRequest.deregister:type_name -> nacp.admission.v1.Deregister
+	6,  // 3: nacp.admission.v1.AdmissionRequest.scale:type_name -> nacp.admission.v1.Scale
+	2,  // 4: nacp.admission.v1.RequestContext.token_info:type_name -> nacp.admission.v1.TokenInfo
+	3,  // 5: nacp.admission.v1.TokenInfo.roles:type_name -> nacp.admission.v1.RoleLink
+	11, // 6: nacp.admission.v1.TokenInfo.create_time:type_name -> google.protobuf.Timestamp
+	11, // 7: nacp.admission.v1.TokenInfo.expiration_time:type_name -> google.protobuf.Timestamp
+	10, // 8: nacp.admission.v1.Dispatch.meta:type_name -> nacp.admission.v1.Dispatch.MetaEntry
+	12, // 9: nacp.admission.v1.Scale.meta:type_name -> google.protobuf.Struct
+	7,  // 10: nacp.admission.v1.ValidateResponse.errors:type_name -> nacp.admission.v1.Violation
+	7,  // 11: nacp.admission.v1.ValidateResponse.warnings:type_name -> nacp.admission.v1.Violation
+	7,  // 12: nacp.admission.v1.MutateResponse.errors:type_name -> nacp.admission.v1.Violation
+	7,  // 13: nacp.admission.v1.MutateResponse.warnings:type_name -> nacp.admission.v1.Violation
+	0,  // 14: nacp.admission.v1.Admission.Validate:input_type -> nacp.admission.v1.AdmissionRequest
+	0,  // 15: nacp.admission.v1.Admission.Mutate:input_type -> nacp.admission.v1.AdmissionRequest
+	8,  // 16: nacp.admission.v1.Admission.Validate:output_type -> nacp.admission.v1.ValidateResponse
+	9,  // 17: nacp.admission.v1.Admission.Mutate:output_type -> nacp.admission.v1.MutateResponse
+	16, // [16:18] is the sub-list for method output_type
+	14, // [14:16] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_admission_proto_init() }
+func file_admission_proto_init() {
+	if File_admission_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_admission_proto_rawDesc), len(file_admission_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_admission_proto_goTypes,
+		DependencyIndexes: file_admission_proto_depIdxs,
+		MessageInfos:      file_admission_proto_msgTypes,
+	}.Build()
+	File_admission_proto = out.File
+	file_admission_proto_goTypes = nil
+	file_admission_proto_depIdxs = nil
+}
@@ -0,0 +1,171 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: admission.proto
+
+package admissionpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Admission_Validate_FullMethodName = "/nacp.admission.v1.Admission/Validate"
+	Admission_Mutate_FullMethodName   = "/nacp.admission.v1.Admission/Mutate"
+)
+
+// AdmissionClient is the client API for Admission service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Admission is implemented by remote validators and mutators. It is the gRPC
+// counterpart of the validation and JSON-Patch webhooks: requests carry what
+// webhooks get as JSON payload, responses what they answer.
+type AdmissionClient interface {
+	// Validate admits or rejects a request.
+	Validate(ctx context.Context, in *AdmissionRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+	// Mutate returns the changes to make to the request's job.
+	Mutate(ctx context.Context, in *AdmissionRequest, opts ...grpc.CallOption) (*MutateResponse, error)
+}
+
+type admissionClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdmissionClient(cc grpc.ClientConnInterface) AdmissionClient {
+	return &admissionClient{cc}
+}
+
+func (c *admissionClient) Validate(ctx context.Context, in *AdmissionRequest, opts ...grpc.CallOption) (*ValidateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateResponse)
+	err := c.cc.Invoke(ctx, Admission_Validate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *admissionClient) Mutate(ctx context.Context, in *AdmissionRequest, opts ...grpc.CallOption) (*MutateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MutateResponse)
+	err := c.cc.Invoke(ctx, Admission_Mutate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdmissionServer is the server API for Admission service.
+// All implementations must embed UnimplementedAdmissionServer
+// for forward compatibility.
+//
+// Admission is implemented by remote validators and mutators. It is the gRPC
+// counterpart of the validation and JSON-Patch webhooks: requests carry what
+// webhooks get as JSON payload, responses what they answer.
+type AdmissionServer interface {
+	// Validate admits or rejects a request.
+	Validate(context.Context, *AdmissionRequest) (*ValidateResponse, error)
+	// Mutate returns the changes to make to the request's job.
+	Mutate(context.Context, *AdmissionRequest) (*MutateResponse, error)
+	mustEmbedUnimplementedAdmissionServer()
+}
+
+// UnimplementedAdmissionServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdmissionServer struct{}
+
+func (UnimplementedAdmissionServer) Validate(context.Context, *AdmissionRequest) (*ValidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedAdmissionServer) Mutate(context.Context, *AdmissionRequest) (*MutateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Mutate not implemented")
+}
+func (UnimplementedAdmissionServer) mustEmbedUnimplementedAdmissionServer() {}
+func (UnimplementedAdmissionServer) testEmbeddedByValue()                   {}
+
+// UnsafeAdmissionServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdmissionServer will
+// result in compilation errors.
+type UnsafeAdmissionServer interface {
+	mustEmbedUnimplementedAdmissionServer()
+}
+
+func RegisterAdmissionServer(s grpc.ServiceRegistrar, srv AdmissionServer) {
+	// If the following call pancis, it indicates UnimplementedAdmissionServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Admission_ServiceDesc, srv)
+}
+
+func _Admission_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdmissionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdmissionServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admission_Validate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdmissionServer).Validate(ctx, req.(*AdmissionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admission_Mutate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdmissionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdmissionServer).Mutate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admission_Mutate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdmissionServer).Mutate(ctx, req.(*AdmissionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Admission_ServiceDesc is the grpc.ServiceDesc for Admission service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Admission_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "nacp.admission.v1.Admission",
+	HandlerType: (*AdmissionServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Validate",
+			Handler:    _Admission_Validate_Handler,
+		},
+		{
+			MethodName: "Mutate",
+			Handler:    _Admission_Mutate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admission.proto",
+}
@@ -0,0 +1,209 @@
+package admissionpb
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/config"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// NewAdmissionRequest converts a payload to the request webhooks would get
+// it as.
+func NewAdmissionRequest(payload *types.Payload) (*AdmissionRequest, error) {
+	job, err := json.Marshal(payload.Job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job: %w", err)
+	}
+	request := &AdmissionRequest{
+		Job:       job,
+		Context:   newRequestContext(payload.Context),
+		Operation: payload.Operation,
+	}
+	if payload.Dispatch != nil {
+		request.Dispatch = &Dispatch{
+			Meta:             payload.Dispatch.Meta,
+			PayloadSize:      int64(payload.Dispatch.PayloadSize),
+			IdPrefixTemplate: payload.Dispatch.IdPrefixTemplate,
+			Priority:         int64(payload.Dispatch.Priority),
+		}
+	}
+	if payload.Deregister != nil {
+		request.Deregister = &Deregister{Purge: payload.Deregister.Purge, Global: payload.Deregister.Global}
+	}
+	if payload.Scale != nil {
+		scale := &Scale{
+			Group:         payload.Scale.Group,
+			Count:         int64(payload.Scale.Count),
+			PreviousCount: int64(payload.Scale.PreviousCount),
+			Message:       payload.Scale.Message,
+		}
+		if payload.Scale.Scaling != nil {
+			if scale.Scaling, err = json.Marshal(payload.Scale.Scaling); err != nil {
+				return nil, fmt.Errorf("failed to marshal scaling policy: %w", err)
+			}
+		}
+		if payload.Scale.Meta != nil {
+			if scale.Meta, err = structpb.NewStruct(payload.Scale.Meta); err != nil {
+				return nil, fmt.Errorf("failed to convert scale meta: %w", err)
+			}
+		}
+		request.Scale = scale
+	}
+	return request, nil
+}
+
+// ToPayload converts the request back to the payload it was created from.
+func (r *AdmissionRequest) ToPayload() (*types.Payload, error) {
+	payload := &types.Payload{
+		Context:   r.GetContext().toRequestContext(),
+		Operation: r.GetOperation(),
+	}
+	if len(r.GetJob()) > 0 {
+		if err := json.Unmarshal(r.GetJob(), &payload.Job); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal job: %w", err)
+		}
+	}
+	if dispatch := r.GetDispatch(); dispatch != nil {
+		payload.Dispatch = &types.Dispatch{
+			Meta:             dispatch.GetMeta(),
+			PayloadSize:      int(dispatch.GetPayloadSize()),
+			IdPrefixTemplate: dispatch.GetIdPrefixTemplate(),
+			Priority:         int(dispatch.GetPriority()),
+		}
+	}
+	if deregister := r.GetDeregister(); deregister != nil {
+		payload.Deregister = &types.Deregister{Purge: deregister.GetPurge(), Global: deregister.GetGlobal()}
+	}
+	if scale := r.GetScale(); scale != nil {
+		payload.Scale = &types.Scale{
+			Group:         scale.GetGroup(),
+			Count:         int(scale.GetCount()),
+			PreviousCount: int(scale.GetPreviousCount()),
+			Message:       scale.GetMessage(),
+		}
+		if len(scale.GetScaling()) > 0 {
+			if err := json.Unmarshal(scale.GetScaling(), &payload.Scale.Scaling); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal scaling policy: %w", err)
+			}
+		}
+		if scale.GetMeta() != nil {
+			payload.Scale.Meta = scale.GetMeta().AsMap()
+		}
+	}
+	return payload, nil
+}
+
+func newRequestContext(context *config.RequestContext) *RequestContext {
+	if context == nil {
+		return nil
+	}
+	return &RequestContext{
+		ClientIp:       context.ClientIP,
+		AccessorId:     context.AccessorID,
+		ResolveToken:   context.ResolveToken,
+		TokenInfo:      newTokenInfo(context.TokenInfo),
+		PolicyOverride: context.PolicyOverride,
+	}
+}
+
+func (c *RequestContext) toRequestContext() *config.RequestContext {
+	if c == nil {
+		return nil
+	}
+	return &config.RequestContext{
+		ClientIP:       c.GetClientIp(),
+		AccessorID:     c.GetAccessorId(),
+		ResolveToken:   c.GetResolveToken(),
+		TokenInfo:      c.GetTokenInfo().toACLTokenContext(),
+		PolicyOverride: c.GetPolicyOverride(),
+	}
+}
+
+func newTokenInfo(token *config.ACLTokenContext) *TokenInfo {
+	if token == nil {
+		return nil
+	}
+	info := &TokenInfo{
+		AccessorId:  token.AccessorID,
+		Name:        token.Name,
+		Type:        token.Type,
+		Policies:    token.Policies,
+		Global:      token.Global,
+		CreateTime:  timestamppb.New(token.CreateTime),
+		CreateIndex: token.CreateIndex,
+		ModifyIndex: token.ModifyIndex,
+	}
+	for _, role := range token.Roles {
+		if role != nil {
+			info.Roles = append(info.Roles, &RoleLink{Id: role.ID, Name: role.Name})
+		}
+	}
+	if token.ExpirationTime != nil {
+		info.ExpirationTime = timestamppb.New(*token.ExpirationTime)
+	}
+	return info
+}
+
+func (t *TokenInfo) toACLTokenContext() *config.ACLTokenContext {
+	if t == nil {
+		return nil
+	}
+	token := &config.ACLTokenContext{
+		AccessorID:  t.GetAccessorId(),
+		Name:        t.GetName(),
+		Type:        t.GetType(),
+		Policies:    t.GetPolicies(),
+		Global:      t.GetGlobal(),
+		CreateTime:  t.GetCreateTime().AsTime(),
+		CreateIndex: t.GetCreateIndex(),
+		ModifyIndex: t.GetModifyIndex(),
+	}
+	for _, role := range t.GetRoles() {
+		token.Roles = append(token.Roles, &api.ACLTokenRoleLink{ID: role.GetId(), Name: role.GetName()})
+	}
+	if t.GetExpirationTime() != nil {
+		expiration := t.GetExpirationTime().AsTime()
+		token.ExpirationTime = &expiration
+	}
+	return token
+}
+
+// NewViolations converts violations to their messages.
+func NewViolations(violations []*types.Violation) []*Violation {
+	var converted []*Violation
+	for _, violation := range violations {
+		if violation == nil {
+			continue
+		}
+		converted = append(converted, &Violation{
+			Message:        violation.Message,
+			Code:           violation.Code,
+			Path:           violation.Path,
+			Severity:       violation.Severity,
+			RemediationUrl: violation.RemediationURL,
+		})
+	}
+	return converted
+}
+
+// ToViolations converts violation messages to violations.
+func ToViolations(violations []*Violation) []*types.Violation {
+	var converted []*types.Violation
+	for _, violation := range violations {
+		if violation == nil {
+			continue
+		}
+		converted = append(converted, &types.Violation{
+			Message:        violation.GetMessage(),
+			Code:           violation.GetCode(),
+			Path:           violation.GetPath(),
+			Severity:       violation.GetSeverity(),
+			RemediationURL: violation.GetRemediationUrl(),
+		})
+	}
+	return converted
+}
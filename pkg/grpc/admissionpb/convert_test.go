@@ -0,0 +1,114 @@
+package admissionpb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/config"
+	"github.com/mxab/nacp/pkg/grpc/admissionpb"
+	"github.com/mxab/nacp/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAdmissionRequestRoundtrip(t *testing.T) {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	expires := created.Add(time.Hour)
+	tt := []struct {
+		name    string
+		payload *types.Payload
+	}{
+		{
+			name:    "job only",
+			payload: &types.Payload{Job: testutil.ReadJob(t, "job.json")},
+		},
+		{
+			name: "context with token",
+			payload: &types.Payload{
+				Job:       testutil.BaseJob(),
+				Operation: "register",
+				Context: &config.RequestContext{
+					ClientIP:     "127.0.0.1",
+					AccessorID:   "1234",
+					ResolveToken: true,
+					TokenInfo: &config.ACLTokenContext{
+						AccessorID:     "1234",
+						Name:           "deployer",
+						Type:           "client",
+						Policies:       []string{"deploy"},
+						Roles:          []*api.ACLTokenRoleLink{{ID: "r1", Name: "ops"}},
+						CreateTime:     created,
+						ExpirationTime: &expires,
+						CreateIndex:    1,
+						ModifyIndex:    2,
+					},
+					PolicyOverride: true,
+				},
+			},
+		},
+		{
+			name: "dispatch",
+			payload: &types.Payload{
+				Job:       testutil.BaseJob(),
+				Operation: "dispatch",
+				Dispatch:  &types.Dispatch{Meta: map[string]string{"input": "a"}, PayloadSize: 12, IdPrefixTemplate: "run", Priority: 70},
+			},
+		},
+		{
+			name: "deregister",
+			payload: &types.Payload{
+				Job:        testutil.BaseJob(),
+				Operation:  "deregister",
+				Deregister: &types.Deregister{Purge: true},
+			},
+		},
+		{
+			name: "scale",
+			payload: &types.Payload{
+				Job:       testutil.BaseJob(),
+				Operation: "scale",
+				Scale: &types.Scale{
+					Group:         "web",
+					Count:         3,
+					PreviousCount: 1,
+					Scaling:       &api.ScalingPolicy{Min: config.Ptr(int64(1)), Max: config.Ptr(int64(5))},
+					Message:       "load",
+					Meta:          map[string]any{"reason": "cpu", "value": 0.9},
+				},
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := admissionpb.NewAdmissionRequest(tc.payload)
+			require.NoError(t, err)
+
+			// the request survives the wire
+			data, err := proto.Marshal(request)
+			require.NoError(t, err)
+			received := &admissionpb.AdmissionRequest{}
+			require.NoError(t, proto.Unmarshal(data, received))
+
+			payload, err := received.ToPayload()
+			require.NoError(t, err)
+			assert.Equal(t, tc.payload, payload)
+		})
+	}
+}
+
+func TestToPayloadInvalidJob(t *testing.T) {
+	_, err := (&admissionpb.AdmissionRequest{Job: []byte("not json")}).ToPayload()
+	assert.ErrorContains(t, err, "failed to unmarshal job")
+}
+
+func TestViolationsRoundtrip(t *testing.T) {
+	violations := []*types.Violation{
+		{Message: "plain"},
+		{Message: "too much memory", Code: "MEM001", Path: "/TaskGroups/0/Tasks/0/Resources/MemoryMB", Severity: "high", RemediationURL: "https://example.com"},
+	}
+	assert.Equal(t, violations, admissionpb.ToViolations(admissionpb.NewViolations(violations)))
+	assert.Nil(t, admissionpb.ToViolations(nil))
+}
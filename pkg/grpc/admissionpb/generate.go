@@ -0,0 +1,14 @@
+// Package admissionpb holds the Admission gRPC service that grpc validators
+// and mutators call, and conversions between its messages and the types of
+// NACP.
+//
+// The job, its scaling policy and the patches travel as JSON inside the
+// messages, in the shape of the Nomad API, so servers decode them with the
+// Nomad api package. A gRPC call therefore encodes and decodes the job just
+// like a webhook call does; the service is for policy services that already
+// speak gRPC, not a cheaper way to send large jobs. Modelling the job in
+// protobuf would mean mirroring, and keeping up with, the whole Nomad job
+// schema.
+package admissionpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative admission.proto
@@ -0,0 +1,135 @@
+// Package server implements the Admission gRPC service with plain Go
+// functions, so a server for grpc validators and mutators takes a few lines:
+//
+//	s := grpc.NewServer()
+//	server.Register(s, &server.Service{
+//		Validator: func(ctx context.Context, payload *types.Payload) (*server.ValidateResult, error) {
+//			if payload.Job.Meta["owner"] == "" {
+//				return &server.ValidateResult{Errors: []*types.Violation{{Message: "job has no owner"}}}, nil
+//			}
+//			return nil, nil
+//		},
+//	})
+//	s.Serve(listener)
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/grpc/admissionpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ValidateResult is the answer of a validator. Errors reject the request.
+type ValidateResult struct {
+	Errors   []*types.Violation
+	Warnings []*types.Violation
+}
+
+// MutateResult is the answer of a mutator. Errors reject the request.
+type MutateResult struct {
+	// Job is the mutated job, which may be the payload's job changed in
+	// place. Nil leaves the job unchanged.
+	Job      *api.Job
+	Errors   []*types.Violation
+	Warnings []*types.Violation
+}
+
+type ValidateFunc func(ctx context.Context, payload *types.Payload) (*ValidateResult, error)
+
+type MutateFunc func(ctx context.Context, payload *types.Payload) (*MutateResult, error)
+
+// Service is an Admission server calling Validator and Mutator. Calls to a
+// nil function fail as unimplemented. Errors the functions return fail the
+// call, and so are controller failures in NACP rather than rejections.
+type Service struct {
+	admissionpb.UnimplementedAdmissionServer
+
+	Validator ValidateFunc
+	Mutator   MutateFunc
+}
+
+var _ admissionpb.AdmissionServer = (*Service)(nil)
+
+// Register registers the service with a gRPC server.
+func Register(registrar grpc.ServiceRegistrar, service *Service) {
+	admissionpb.RegisterAdmissionServer(registrar, service)
+}
+
+func (s *Service) Validate(ctx context.Context, request *admissionpb.AdmissionRequest) (*admissionpb.ValidateResponse, error) {
+	if s.Validator == nil {
+		return nil, status.Error(codes.Unimplemented, "validation is not implemented")
+	}
+	payload, err := request.ToPayload()
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	result, err := s.Validator(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return &admissionpb.ValidateResponse{}, nil
+	}
+	return &admissionpb.ValidateResponse{
+		Errors:   admissionpb.NewViolations(result.Errors),
+		Warnings: admissionpb.NewViolations(result.Warnings),
+	}, nil
+}
+
+func (s *Service) Mutate(ctx context.Context, request *admissionpb.AdmissionRequest) (*admissionpb.MutateResponse, error) {
+	if s.Mutator == nil {
+		return nil, status.Error(codes.Unimplemented, "mutation is not implemented")
+	}
+	payload, err := request.ToPayload()
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	// the baseline is the decoded job, so fields this server does not know
+	// are not patched away
+	original, err := json.Marshal(payload.Job)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.Mutator(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return &admissionpb.MutateResponse{}, nil
+	}
+	response := &admissionpb.MutateResponse{
+		Errors:   admissionpb.NewViolations(result.Errors),
+		Warnings: admissionpb.NewViolations(result.Warnings),
+	}
+	if result.Job != nil && len(result.Errors) == 0 {
+		if response.MergePatch, err = mergePatch(original, result.Job); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// mergePatch returns the JSON merge patch turning original into job, or nil
+// if they are the same.
+func mergePatch(original []byte, job *api.Job) ([]byte, error) {
+	modified, err := json.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := jsonpatch.CreateMergePatch(original, modified)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create merge patch: %w", err)
+	}
+	if string(patch) == "{}" {
+		return nil, nil
+	}
+	return patch, nil
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mxab/nacp/pkg/admissionctrl/grpcutil"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/config"
+	"github.com/mxab/nacp/pkg/grpc/admissionpb"
+	"github.com/mxab/nacp/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newClient(t *testing.T, service *Service) *grpcutil.Client {
+	t.Helper()
+	client, err := grpcutil.NewClient(grpcutil.Options{Address: testutil.StartAdmissionServer(t, service, nil)})
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestValidate(t *testing.T) {
+	var received *types.Payload
+	client := newClient(t, &Service{
+		Validator: func(ctx context.Context, payload *types.Payload) (*ValidateResult, error) {
+			received = payload
+			if _, ok := payload.Job.Meta["owner"]; ok {
+				return nil, nil
+			}
+			return &ValidateResult{
+				Errors:   []*types.Violation{{Message: "job has no owner", Code: "OWNER", Path: "/Meta"}},
+				Warnings: []*types.Violation{{Message: "careful"}},
+			}, nil
+		},
+	})
+
+	payload := &types.Payload{
+		Job:       testutil.BaseJob(),
+		Operation: "register",
+		Context:   &config.RequestContext{ClientIP: "127.0.0.1", AccessorID: "1234"},
+	}
+	response, err := client.Validate(t.Context(), payload)
+	require.NoError(t, err)
+	assert.Equal(t, payload, received, "The validator gets the payload")
+	assert.Equal(t, []*types.Violation{{Message: "job has no owner", Code: "OWNER", Path: "/Meta"}}, admissionpb.ToViolations(response.GetErrors()))
+	assert.Equal(t, []*types.Violation{{Message: "careful"}}, admissionpb.ToViolations(response.GetWarnings()))
+
+	job := testutil.BaseJob()
+	job.Meta = map[string]string{"owner": "team-a"}
+	response, err = client.Validate(t.Context(), &types.Payload{Job: job})
+	require.NoError(t, err)
+	assert.Empty(t, response.GetErrors())
+	assert.Empty(t, response.GetWarnings())
+}
+
+func TestMutate(t *testing.T) {
+	client := newClient(t, &Service{
+		Mutator: func(ctx context.Context, payload *types.Payload) (*MutateResult, error) {
+			job := payload.Job
+			switch job.Meta["case"] {
+			case "unchanged":
+				return &MutateResult{Job: job}, nil
+			case "rejected":
+				job.Meta["owner"] = "team-a"
+				return &MutateResult{Job: job, Errors: []*types.Violation{{Message: "denied"}}}, nil
+			}
+			job.Meta["owner"] = "team-a"
+			job.Datacenters = []string{"dc1"}
+			return &MutateResult{Job: job, Warnings: []*types.Violation{{Message: "owner set"}}}, nil
+		},
+	})
+
+	tt := []struct {
+		name           string
+		meta           map[string]string
+		wantMergePatch string
+		wantErrors     []*types.Violation
+		wantWarnings   []*types.Violation
+	}{
+		{
+			name:           "changed in place",
+			meta:           map[string]string{},
+			wantMergePatch: `{"Datacenters": ["dc1"], "Meta": {"owner": "team-a"}}`,
+			wantWarnings:   []*types.Violation{{Message: "owner set"}},
+		},
+		{
+			name: "unchanged",
+			meta: map[string]string{"case": "unchanged"},
+		},
+		{
+			name:       "rejected",
+			meta:       map[string]string{"case": "rejected"},
+			wantErrors: []*types.Violation{{Message: "denied"}},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			job := testutil.BaseJob()
+			job.Meta = tc.meta
+			response, err := client.Mutate(t.Context(), &types.Payload{Job: job})
+			require.NoError(t, err)
+
+			if tc.wantMergePatch == "" {
+				assert.Empty(t, response.GetMergePatch())
+			} else {
+				assert.JSONEq(t, tc.wantMergePatch, string(response.GetMergePatch()))
+			}
+			assert.Empty(t, response.GetPatch())
+			assert.Equal(t, tc.wantErrors, admissionpb.ToViolations(response.GetErrors()))
+			assert.Equal(t, tc.wantWarnings, admissionpb.ToViolations(response.GetWarnings()))
+		})
+	}
+}
+
+func TestUnimplemented(t *testing.T) {
+	client := newClient(t, &Service{})
+
+	_, err := client.Validate(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	assert.Equal(t, codes.Unimplemented, status.Code(errors.Unwrap(err)))
+	_, err = client.Mutate(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	assert.Equal(t, codes.Unimplemented, status.Code(errors.Unwrap(err)))
+}
+
+func TestFunctionError(t *testing.T) {
+	client := newClient(t, &Service{
+		Validator: func(ctx context.Context, payload *types.Payload) (*ValidateResult, error) {
+			return nil, errors.New("database unavailable")
+		},
+	})
+
+	_, err := client.Validate(t.Context(), &types.Payload{Job: testutil.BaseJob()})
+	assert.ErrorContains(t, err, "database unavailable")
+}
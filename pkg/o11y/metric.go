@@ -188,3 +188,197 @@ func (m NacpMutatorMutationCount) Add(
 		attribute.String("mutator.name", mutatorName),
 	))
 }
+
+// An instrument for recording `nacp.validator.override.count`
+type NacpValidatorOverrideCount struct {
+	inst metric.Float64Counter
+}
+
+// Construct a new instrument for measuring `nacp.validator.override.count`
+func NewNacpValidatorOverrideCount(m metric.Meter) (NacpValidatorOverrideCount, error) {
+	i, err := m.Float64Counter(
+		"nacp.validator.override.count",
+		metric.WithDescription("Count of all soft-mandatory validation failures overridden by a caller."),
+		metric.WithUnit("{override}"),
+	)
+	if err != nil {
+		return NacpValidatorOverrideCount{}, err
+	}
+	return NacpValidatorOverrideCount{i}, nil
+}
+
+// Adds an increment to the existing count.
+func (m NacpValidatorOverrideCount) Add(
+	ctx context.Context,
+	inc float64,
+
+	// The name of the validator.
+	validatorName string,
+
+) {
+
+	m.inst.Add(ctx, inc, metric.WithAttributes(
+
+		attribute.String("validator.name", validatorName),
+	))
+}
+
+// An instrument for recording `nacp.admission.audit.violation.count`
+type NacpAdmissionAuditViolationCount struct {
+	inst metric.Float64Counter
+}
+
+// Construct a new instrument for measuring `nacp.admission.audit.violation.count`
+func NewNacpAdmissionAuditViolationCount(m metric.Meter) (NacpAdmissionAuditViolationCount, error) {
+	i, err := m.Float64Counter(
+		"nacp.admission.audit.violation.count",
+		metric.WithDescription("Count of all rejections and patches controllers in audit mode would have applied."),
+		metric.WithUnit("{violation}"),
+	)
+	if err != nil {
+		return NacpAdmissionAuditViolationCount{}, err
+	}
+	return NacpAdmissionAuditViolationCount{i}, nil
+}
+
+// Adds an increment to the existing count.
+func (m NacpAdmissionAuditViolationCount) Add(
+	ctx context.Context,
+	inc float64,
+
+	// The kind of the admission controller, either validator or mutator.
+	controllerKind string,
+
+	// The name of the admission controller.
+	controllerName string,
+
+) {
+
+	m.inst.Add(ctx, inc, metric.WithAttributes(
+
+		attribute.String("controller.kind", controllerKind),
+		attribute.String("controller.name", controllerName),
+	))
+}
+
+// An instrument for recording `nacp.controller.skip.count`
+type NacpControllerSkipCount struct {
+	inst metric.Float64Counter
+}
+
+// Construct a new instrument for measuring `nacp.controller.skip.count`
+func NewNacpControllerSkipCount(m metric.Meter) (NacpControllerSkipCount, error) {
+	i, err := m.Float64Counter(
+		"nacp.controller.skip.count",
+		metric.WithDescription("Count of all admissions a controller was skipped for because its match block did not select the job."),
+		metric.WithUnit("{skip}"),
+	)
+	if err != nil {
+		return NacpControllerSkipCount{}, err
+	}
+	return NacpControllerSkipCount{i}, nil
+}
+
+// Adds an increment to the existing count.
+func (m NacpControllerSkipCount) Add(
+	ctx context.Context,
+	inc float64,
+
+	// The kind of the admission controller, either validator or mutator.
+	controllerKind string,
+
+	// The name of the admission controller.
+	controllerName string,
+
+) {
+
+	m.inst.Add(ctx, inc, metric.WithAttributes(
+
+		attribute.String("controller.kind", controllerKind),
+		attribute.String("controller.name", controllerName),
+	))
+}
+
+// An instrument for recording `nacp.controller.failure.count`
+type NacpControllerFailureCount struct {
+	inst metric.Float64Counter
+}
+
+// Construct a new instrument for measuring `nacp.controller.failure.count`
+func NewNacpControllerFailureCount(m metric.Meter) (NacpControllerFailureCount, error) {
+	i, err := m.Float64Counter(
+		"nacp.controller.failure.count",
+		metric.WithDescription("Count of all admission controller failures, such as unreachable webhooks or timeouts."),
+		metric.WithUnit("{failure}"),
+	)
+	if err != nil {
+		return NacpControllerFailureCount{}, err
+	}
+	return NacpControllerFailureCount{i}, nil
+}
+
+// Adds an increment to the existing count.
+func (m NacpControllerFailureCount) Add(
+	ctx context.Context,
+	inc float64,
+
+	// The kind of the admission controller, either validator or mutator.
+	controllerKind string,
+
+	// The name of the admission controller.
+	controllerName string,
+
+	// The failure policy applied to a failing admission controller.
+	policy string,
+
+) {
+
+	m.inst.Add(ctx, inc, metric.WithAttributes(
+
+		attribute.String("controller.kind", controllerKind),
+		attribute.String("controller.name", controllerName),
+		attribute.String("policy", policy),
+	))
+}
+
+// An instrument for recording `nacp.policy.violation.count`
+type NacpPolicyViolationCount struct {
+	inst metric.Float64Counter
+}
+
+// Construct a new instrument for measuring `nacp.policy.violation.count`
+func NewNacpPolicyViolationCount(m metric.Meter) (NacpPolicyViolationCount, error) {
+	i, err := m.Float64Counter(
+		"nacp.policy.violation.count",
+		metric.WithDescription("Count of all policy errors reported by admission controllers, by severity."),
+		metric.WithUnit("{violation}"),
+	)
+	if err != nil {
+		return NacpPolicyViolationCount{}, err
+	}
+	return NacpPolicyViolationCount{i}, nil
+}
+
+// Adds an increment to the existing count.
+func (m NacpPolicyViolationCount) Add(
+	ctx context.Context,
+	inc float64,
+
+	// The kind of the admission controller, either validator or mutator.
+	controllerKind string,
+
+	// The name of the admission controller.
+	controllerName string,
+
+	// The severity of a violation: low, medium, high, critical, other for any other value a policy sets, or empty for plain messages.
+	violationSeverity string,
+
+) {
+
+	m.inst.Add(ctx, inc, metric.WithAttributes(
+
+		attribute.String("controller.kind", controllerKind),
+		attribute.String("controller.name", controllerName),
+		attribute.String("violation.severity", violationSeverity),
+	))
+}
@@ -0,0 +1,212 @@
+// Package server implements the webhook contract of NACP, so a server for
+// webhook validators and json_patch_webhook mutators takes a few lines:
+//
+//	http.Handle("/validate", server.ValidationHandler(func(ctx context.Context, request *server.Request) (*server.ValidationResponse, error) {
+//		if request.Job.Meta["owner"] == "" {
+//			return &server.ValidationResponse{Errors: []*types.Violation{{Message: "job has no owner"}}}, nil
+//		}
+//		return &server.ValidationResponse{}, nil
+//	}, server.Options{SigningSecret: secret}))
+//
+// Mutators change the request's job and answer with the operations Patch
+// builds from it.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl/mutator/jsonpatcher"
+	"github.com/mxab/nacp/pkg/admissionctrl/remoteutil"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+)
+
+const (
+	DefaultMaxRequestBytes    = 10 << 20
+	DefaultSignatureTolerance = 5 * time.Minute
+)
+
+// Request is a request of NACP to a webhook.
+type Request struct {
+	*types.Payload
+
+	// ClientIP and AccessorID are the NACP-Client-IP and NACP-Accessor-ID
+	// headers.
+	ClientIP   string
+	AccessorID string
+
+	// original is the job as received, the baseline of Patch.
+	original []byte
+}
+
+// Patch returns the JSON Patch operations turning the job as received into
+// job, usually the request's job changed in place.
+func (r *Request) Patch(job *api.Job) ([]PatchOperation, error) {
+	modified, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job: %w", err)
+	}
+	return jsonpatcher.CreatePatch(r.original, modified)
+}
+
+// MergePatch returns the JSON Merge Patch (RFC 7386) turning the job as
+// received into job.
+func (r *Request) MergePatch(job *api.Job) (json.RawMessage, error) {
+	modified, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job: %w", err)
+	}
+	patch, err := jsonpatch.CreateMergePatch(r.original, modified)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create merge patch: %w", err)
+	}
+	return patch, nil
+}
+
+// PatchOperation is a JSON Patch (RFC 6902) operation.
+type PatchOperation = jsonpatcher.Operation
+
+// ValidationResponse is the answer of a webhook validator. Errors reject the
+// request.
+type ValidationResponse struct {
+	Errors   []*types.Violation `json:"errors"`
+	Warnings []*types.Violation `json:"warnings"`
+}
+
+// PatchResponse is the answer of a json_patch_webhook mutator. Errors reject
+// the request. MergePatch is applied before Patch.
+type PatchResponse struct {
+	Patch      []PatchOperation   `json:"patch"`
+	MergePatch any                `json:"merge_patch,omitempty"`
+	Warnings   []*types.Violation `json:"warnings"`
+	Errors     []*types.Violation `json:"errors"`
+}
+
+type ValidateFunc func(ctx context.Context, request *Request) (*ValidationResponse, error)
+
+type PatchFunc func(ctx context.Context, request *Request) (*PatchResponse, error)
+
+// Options configure a handler. Zero values mean the defaults.
+type Options struct {
+	// SigningSecret is the secret of the webhook's signing_secret_file.
+	// With it, requests without a valid signature are rejected.
+	SigningSecret []byte
+	// SignatureTolerance is how old a signature may be. Zero means 5m.
+	SignatureTolerance time.Duration
+	// MaxRequestBytes caps the request body. Zero means 10 MiB.
+	MaxRequestBytes int64
+	// Logger logs failed requests. Nil means slog.Default().
+	Logger *slog.Logger
+}
+
+// ValidationHandler serves validate as a webhook validator.
+func ValidationHandler(validate ValidateFunc, options Options) http.Handler {
+	return newHandler(options, func(ctx context.Context, request *Request) (any, error) {
+		response, err := validate(ctx, request)
+		if response == nil && err == nil {
+			response = &ValidationResponse{}
+		}
+		return response, err
+	})
+}
+
+// PatchHandler serves patch as a json_patch_webhook mutator.
+func PatchHandler(patch PatchFunc, options Options) http.Handler {
+	return newHandler(options, func(ctx context.Context, request *Request) (any, error) {
+		response, err := patch(ctx, request)
+		if response == nil && err == nil {
+			response = &PatchResponse{}
+		}
+		return response, err
+	})
+}
+
+type handler struct {
+	options Options
+	call    func(ctx context.Context, request *Request) (any, error)
+}
+
+func newHandler(options Options, call func(ctx context.Context, request *Request) (any, error)) *handler {
+	if options.SignatureTolerance == 0 {
+		options.SignatureTolerance = DefaultSignatureTolerance
+	}
+	if options.MaxRequestBytes == 0 {
+		options.MaxRequestBytes = DefaultMaxRequestBytes
+	}
+	if options.Logger == nil {
+		options.Logger = slog.Default()
+	}
+	return &handler{options: options, call: call}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.options.MaxRequestBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, fmt.Sprintf("request exceeds %d bytes", h.options.MaxRequestBytes), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+	if len(h.options.SigningSecret) > 0 {
+		if err := VerifySignature(r.Header.Get(remoteutil.SignatureHeader), body, h.options.SigningSecret, h.options.SignatureTolerance); err != nil {
+			h.options.Logger.Warn("rejected webhook request", "error", err)
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	request, err := newRequest(r, body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	response, err := h.call(r.Context(), request)
+	if err != nil {
+		// NACP treats a failed webhook as a controller failure, the message
+		// stays in this server's logs
+		h.options.Logger.Error("webhook failed", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		h.options.Logger.Error("failed to marshal webhook response", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func newRequest(r *http.Request, body []byte) (*Request, error) {
+	payload := &types.Payload{}
+	if err := json.Unmarshal(body, payload); err != nil {
+		return nil, fmt.Errorf("failed to decode payload: %w", err)
+	}
+	if payload.Job == nil {
+		return nil, fmt.Errorf("payload has no job")
+	}
+	// the baseline is the decoded job, so fields this server does not know
+	// are not patched away
+	original, err := json.Marshal(payload.Job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job: %w", err)
+	}
+	return &Request{
+		Payload:    payload,
+		ClientIP:   r.Header.Get(remoteutil.ClientIPHeader),
+		AccessorID: r.Header.Get(remoteutil.AccessorIDHeader),
+		original:   original,
+	}, nil
+}
@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/api"
+	"github.com/mxab/nacp/pkg/admissionctrl/mutator"
+	"github.com/mxab/nacp/pkg/admissionctrl/types"
+	"github.com/mxab/nacp/pkg/admissionctrl/validator"
+	"github.com/mxab/nacp/pkg/config"
+	"github.com/mxab/nacp/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var secret = []byte("s3cr3t")
+
+func TestValidationHandler(t *testing.T) {
+	ownerRequired := func(ctx context.Context, request *Request) (*ValidationResponse, error) {
+		response := &ValidationResponse{}
+		if request.Job.Meta["owner"] == "" {
+			response.Errors = append(response.Errors, &types.Violation{Message: "job has no owner", Code: "OWNER", Path: "/Meta/owner"})
+		}
+		if request.AccessorID == "" {
+			response.Warnings = append(response.Warnings, &types.Violation{Message: "anonymous request"})
+		}
+		return response, nil
+	}
+
+	tests := []struct {
+		name          string
+		meta          map[string]string
+		context       *config.RequestContext
+		validate      ValidateFunc
+		clientSecret  []byte
+		wantErrs      []error
+		wantWarns     []error
+		wantFailure   string
+		wantViolation *types.Violation
+	}{
+		{
+			name:         "accepts a valid job",
+			meta:         map[string]string{"owner": "team-a"},
+			context:      &config.RequestContext{AccessorID: "accessor"},
+			validate:     ownerRequired,
+			clientSecret: secret,
+		},
+		{
+			name:         "reports warnings",
+			meta:         map[string]string{"owner": "team-a"},
+			validate:     ownerRequired,
+			clientSecret: secret,
+			wantWarns:    []error{&types.Violation{Message: "anonymous request"}},
+		},
+		{
+			name:          "rejects an invalid job",
+			context:       &config.RequestContext{AccessorID: "accessor"},
+			validate:      ownerRequired,
+			clientSecret:  secret,
+			wantViolation: &types.Violation{Message: "job has no owner", Code: "OWNER", Path: "/Meta/owner"},
+		},
+		{
+			name: "nil response accepts the job",
+			validate: func(ctx context.Context, request *Request) (*ValidationResponse, error) {
+				return nil, nil
+			},
+			clientSecret: secret,
+		},
+		{
+			name: "failing function fails the call",
+			validate: func(ctx context.Context, request *Request) (*ValidationResponse, error) {
+				return nil, errors.New("database down")
+			},
+			clientSecret: secret,
+			wantFailure:  "500",
+		},
+		{
+			name:        "rejects unsigned requests",
+			validate:    ownerRequired,
+			wantFailure: "401",
+		},
+		{
+			name:         "rejects requests signed with another secret",
+			validate:     ownerRequired,
+			clientSecret: []byte("other"),
+			wantFailure:  "401",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(ValidationHandler(tt.validate, Options{SigningSecret: secret, Logger: slog.New(slog.DiscardHandler)}))
+			defer server.Close()
+
+			webhook, err := validator.NewWebhookValidator("test", server.URL, http.MethodPost, tt.clientSecret, slog.New(slog.DiscardHandler))
+			require.NoError(t, err)
+
+			job := testutil.BaseJob()
+			job.Meta = tt.meta
+			warns, err := webhook.Validate(context.Background(), &types.Payload{Job: job, Context: tt.context})
+
+			switch {
+			case tt.wantFailure != "":
+				assert.ErrorContains(t, err, tt.wantFailure)
+			case tt.wantViolation != nil:
+				var merr *multierror.Error
+				require.ErrorAs(t, err, &merr)
+				assert.Equal(t, []error{tt.wantViolation}, merr.Errors)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantWarns, warns)
+			}
+		})
+	}
+}
+
+func TestPatchHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		patch       PatchFunc
+		wantJob     func(job *api.Job)
+		wantMutated bool
+		wantErr     string
+	}{
+		{
+			name: "applies the patch of the changed job",
+			patch: func(ctx context.Context, request *Request) (*PatchResponse, error) {
+				request.Job.Meta = map[string]string{"owner": "team-a"}
+				request.Job.Datacenters = append(request.Job.Datacenters, "dc2")
+				request.Job.Namespace = nil
+				patch, err := request.Patch(request.Job)
+				return &PatchResponse{Patch: patch}, err
+			},
+			wantJob: func(job *api.Job) {
+				job.Meta = map[string]string{"owner": "team-a"}
+				job.Datacenters = append(job.Datacenters, "dc2")
+				job.Namespace = nil
+			},
+			wantMutated: true,
+		},
+		{
+			name: "applies the merge patch of the changed job",
+			patch: func(ctx context.Context, request *Request) (*PatchResponse, error) {
+				request.Job.Meta = map[string]string{"owner": "team-a"}
+				mergePatch, err := request.MergePatch(request.Job)
+				return &PatchResponse{MergePatch: mergePatch}, err
+			},
+			wantJob: func(job *api.Job) {
+				job.Meta = map[string]string{"owner": "team-a"}
+			},
+			wantMutated: true,
+		},
+		{
+			name: "unchanged job",
+			patch: func(ctx context.Context, request *Request) (*PatchResponse, error) {
+				patch, err := request.Patch(request.Job)
+				return &PatchResponse{Patch: patch}, err
+			},
+			wantJob: func(job *api.Job) {},
+		},
+		{
+			name: "errors reject the job",
+			patch: func(ctx context.Context, request *Request) (*PatchResponse, error) {
+				return &PatchResponse{Errors: []*types.Violation{{Message: "not allowed"}}}, nil
+			},
+			wantErr: "not allowed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(PatchHandler(tt.patch, Options{SigningSecret: secret}))
+			defer server.Close()
+
+			webhook, err := mutator.NewJsonPatchWebhookMutator("test", server.URL, http.MethodPost, secret, slog.New(slog.DiscardHandler))
+			require.NoError(t, err)
+
+			job, mutated, _, err := webhook.Mutate(context.Background(), &types.Payload{Job: testutil.ReadJob(t, "job.json")})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			want := testutil.ReadJob(t, "job.json")
+			tt.wantJob(want)
+			assert.Equal(t, want, job)
+			assert.Equal(t, tt.wantMutated, mutated)
+		})
+	}
+}
+
+func TestHandlerRequests(t *testing.T) {
+	validate := func(ctx context.Context, request *Request) (*ValidationResponse, error) {
+		return &ValidationResponse{}, nil
+	}
+	tests := []struct {
+		name       string
+		options    Options
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "accepts unsigned requests without a secret",
+			body:       `{"job": {"ID": "test-job"}}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "malformed payload",
+			body:       `{"job":`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "payload without a job",
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "request too large",
+			options:    Options{MaxRequestBytes: 8},
+			body:       `{"job": {"ID": "test-job"}}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ValidationHandler(validate, tt.options).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)))
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"crypto/hmac"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mxab/nacp/pkg/admissionctrl/remoteutil"
+)
+
+// now is replaced by tests.
+var now = time.Now
+
+// VerifySignature checks the NACP-Signature header value of a request with
+// body. It fails for signatures made with another secret and for signatures
+// older than tolerance, so captured requests cannot be replayed later.
+func VerifySignature(header string, body []byte, secret []byte, tolerance time.Duration) error {
+	if header == "" {
+		return fmt.Errorf("request is not signed")
+	}
+	var timestamp int64
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return fmt.Errorf("malformed signature header")
+		}
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("malformed signature timestamp: %w", err)
+			}
+			timestamp = parsed
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return fmt.Errorf("malformed signature header")
+	}
+	age := now().Sub(time.Unix(timestamp, 0))
+	if age > tolerance || age < -tolerance {
+		return fmt.Errorf("signature timestamp is outside the tolerance of %s", tolerance)
+	}
+	expected := []byte(remoteutil.Signature(secret, timestamp, body))
+	for _, signature := range signatures {
+		if hmac.Equal(expected, []byte(signature)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("signature does not match")
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mxab/nacp/pkg/admissionctrl/remoteutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"job": {"ID": "test-job"}}`)
+	signedAt := time.Unix(1700000000, 0)
+	signature := remoteutil.Signature(secret, signedAt.Unix(), body)
+
+	tests := []struct {
+		name     string
+		header   string
+		body     []byte
+		verifyAt time.Time
+		wantErr  string
+	}{
+		{
+			name:     "valid signature",
+			header:   fmt.Sprintf("t=%d,v1=%s", signedAt.Unix(), signature),
+			verifyAt: signedAt.Add(time.Minute),
+		},
+		{
+			name:     "one of several signatures matches",
+			header:   fmt.Sprintf("t=%d,v1=%s,v1=%s", signedAt.Unix(), "deadbeef", signature),
+			verifyAt: signedAt,
+		},
+		{
+			name:     "missing header",
+			verifyAt: signedAt,
+			wantErr:  "request is not signed",
+		},
+		{
+			name:     "malformed header",
+			header:   "garbage",
+			verifyAt: signedAt,
+			wantErr:  "malformed signature header",
+		},
+		{
+			name:     "missing signature",
+			header:   fmt.Sprintf("t=%d", signedAt.Unix()),
+			verifyAt: signedAt,
+			wantErr:  "malformed signature header",
+		},
+		{
+			name:     "malformed timestamp",
+			header:   "t=yesterday,v1=" + signature,
+			verifyAt: signedAt,
+			wantErr:  "malformed signature timestamp",
+		},
+		{
+			name:     "tampered body",
+			header:   fmt.Sprintf("t=%d,v1=%s", signedAt.Unix(), signature),
+			body:     []byte(`{"job": {"ID": "other-job"}}`),
+			verifyAt: signedAt,
+			wantErr:  "signature does not match",
+		},
+		{
+			name:     "replayed request",
+			header:   fmt.Sprintf("t=%d,v1=%s", signedAt.Unix(), signature),
+			verifyAt: signedAt.Add(10 * time.Minute),
+			wantErr:  "outside the tolerance of 5m0s",
+		},
+		{
+			name:     "signed in the future",
+			header:   fmt.Sprintf("t=%d,v1=%s", signedAt.Unix(), signature),
+			verifyAt: signedAt.Add(-10 * time.Minute),
+			wantErr:  "outside the tolerance of 5m0s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = func() time.Time { return tt.verifyAt }
+			t.Cleanup(func() { now = time.Now })
+
+			verified := body
+			if tt.body != nil {
+				verified = tt.body
+			}
+			err := VerifySignature(tt.header, verified, secret, DefaultSignatureTolerance)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSignRequestRoundtrip(t *testing.T) {
+	body := []byte(`{"job": {"ID": "test-job"}}`)
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/validate", nil)
+	require.NoError(t, err)
+
+	remoteutil.SignRequest(req, body, secret)
+
+	assert.NoError(t, VerifySignature(req.Header.Get(remoteutil.SignatureHeader), body, secret, DefaultSignatureTolerance))
+}
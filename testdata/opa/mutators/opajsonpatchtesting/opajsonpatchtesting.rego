@@ -34,3 +34,5 @@ patch contains operation if {
 		"value": "world",
 	}
 }
+
+merge_patch := {"Meta": {"owner": "platform"}}
@@ -0,0 +1,15 @@
+package malformed
+
+import future.keywords.contains
+import future.keywords.if
+
+# Neither strings nor violation objects.
+errors contains 42 if {
+	input.job.Meta.broken == "true"
+}
+
+errors contains {"code": "NOMSG"} if {
+	input.job.Meta.broken == "true"
+}
+
+warnings contains ["not", "a", "violation"]
@@ -0,0 +1,21 @@
+package structured
+
+import future.keywords.contains
+import future.keywords.if
+
+# Each error is an object pointing at the offending task.
+errors contains violation if {
+	some g, t
+	task := input.job.TaskGroups[g].Tasks[t]
+	task.Resources.MemoryMB > 1024
+
+	violation := {
+		"message": sprintf("task %s requests more than 1024 MB", [task.Name]),
+		"code": "MEM001",
+		"path": sprintf("/TaskGroups/%d/Tasks/%d/Resources/MemoryMB", [g, t]),
+		"severity": "high",
+		"remediation_url": "https://example.com/policies/memory",
+	}
+}
+
+warnings contains "plain warnings still work"
@@ -0,0 +1,95 @@
+// Command policy is the WebAssembly test policy of the wasm validator and
+// mutator. Build it with
+//
+//	GOOS=wasip1 GOARCH=wasm go build -buildmode=c-shared -o policy.wasm .
+//
+// It rejects jobs without an owner meta key, warns about jobs with a
+// deprecated meta key, and as a mutator sets the mutated-by meta key. The
+// spin and hog meta keys make it loop forever or allocate too much memory.
+package main
+
+import (
+	"encoding/json"
+	"unsafe"
+)
+
+type payload struct {
+	Job struct {
+		Meta map[string]string
+	} `json:"job"`
+}
+
+type response struct {
+	Errors     []string          `json:"errors,omitempty"`
+	Warnings   []string          `json:"warnings,omitempty"`
+	Patch      []json.RawMessage `json:"patch,omitempty"`
+	MergePatch interface{}       `json:"merge_patch,omitempty"`
+}
+
+// buffers keeps the memory handed to the host alive until it is freed.
+var buffers = map[uint32][]byte{}
+
+//go:wasmexport nacp_malloc
+func malloc(size uint32) uint32 {
+	buffer := make([]byte, size+1)
+	ptr := uint32(uintptr(unsafe.Pointer(unsafe.SliceData(buffer))))
+	buffers[ptr] = buffer
+	return ptr
+}
+
+//go:wasmexport nacp_free
+func free(ptr uint32, _ uint32) {
+	delete(buffers, ptr)
+}
+
+//go:wasmexport nacp_validate
+func validate(ptr uint32, size uint32) uint64 {
+	in := read(ptr, size)
+	out := response{}
+	if _, ok := in.Job.Meta["owner"]; !ok {
+		out.Errors = append(out.Errors, "job has no owner")
+	}
+	if _, ok := in.Job.Meta["deprecated"]; ok {
+		out.Warnings = append(out.Warnings, "job is deprecated")
+	}
+	return write(out)
+}
+
+//go:wasmexport nacp_mutate
+func mutate(ptr uint32, size uint32) uint64 {
+	read(ptr, size)
+	return write(response{
+		MergePatch: map[string]interface{}{"Meta": map[string]string{"mutated-by": "wasm"}},
+	})
+}
+
+var sink [][]byte
+
+func read(ptr uint32, size uint32) payload {
+	var in payload
+	if err := json.Unmarshal(buffers[ptr][:size], &in); err != nil {
+		panic(err)
+	}
+	if _, ok := in.Job.Meta["spin"]; ok {
+		for {
+		}
+	}
+	if _, ok := in.Job.Meta["hog"]; ok {
+		for {
+			sink = append(sink, make([]byte, 16<<20))
+		}
+	}
+	return in
+}
+
+func write(out response) uint64 {
+	data, err := json.Marshal(out)
+	if err != nil {
+		panic(err)
+	}
+	ptr := malloc(uint32(len(data)))
+	copy(buffers[ptr], data)
+	return uint64(ptr)<<32 | uint64(len(data))
+}
+
+func main() {}
@@ -0,0 +1,31 @@
+package testutil
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+
+	"github.com/mxab/nacp/pkg/grpc/admissionpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// StartAdmissionServer serves service on a local port until the test ends
+// and returns the server's address. A non-nil tlsConfig enables TLS.
+func StartAdmissionServer(t *testing.T, service admissionpb.AdmissionServer, tlsConfig *tls.Config) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %v", err)
+	}
+	var options []grpc.ServerOption
+	if tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	server := grpc.NewServer(options...)
+	admissionpb.RegisterAdmissionServer(server, service)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path"
 	"runtime"
+	"sync"
 	"testing"
 
 	"github.com/mxab/nacp/pkg/admissionctrl/types"
@@ -16,6 +18,7 @@ import (
 	"github.com/open-policy-agent/opa/v1/sdk"
 	sdktest "github.com/open-policy-agent/opa/v1/sdk/test"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/api"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -101,6 +104,41 @@ func Filepath(t *testing.T, name string) string {
 	return path.Join(path.Dir(filename), "..", "testdata", name)
 }
 
+var (
+	wasmMu      sync.Mutex
+	wasmModules = map[string]string{}
+)
+
+// BuildWasm compiles the Go program in the testdata directory name to a
+// WASI reactor module and returns the module's path. Modules are built once
+// per test binary.
+func BuildWasm(t *testing.T, name string) string {
+	t.Helper()
+
+	wasmMu.Lock()
+	defer wasmMu.Unlock()
+	if out, ok := wasmModules[name]; ok {
+		return out
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not found, cannot build wasm module")
+	}
+	dir, err := os.MkdirTemp("", "nacp-wasm-")
+	if err != nil {
+		t.Fatalf("Error creating wasm output directory: %v", err)
+	}
+	out := path.Join(dir, path.Base(name)+".wasm")
+	cmd := exec.Command(goBin, "build", "-buildmode=c-shared", "-o", out, ".")
+	cmd.Dir = Filepath(t, name)
+	cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Error building wasm module: %v\n%s", err, output)
+	}
+	wasmModules[name] = out
+	return out
+}
+
 func OtelExporters(t *testing.T) (*logtest.Recorder, *metricSdk.ManualReader, *tracetest.InMemoryExporter) {
 	t.Helper()
 	spanExporter := tracetest.NewInMemoryExporter()
@@ -123,6 +161,15 @@ func MockValidatorReturningError(err string) *MockValidator {
 	validator.On("Validate", mock.Anything, mock.Anything).Return([]error{}, fmt.Errorf("%s", err))
 	return validator
 }
+
+// MockValidatorRejecting returns a validator rejecting every job with the
+// given policy error.
+func MockValidatorRejecting(err string) *MockValidator {
+
+	validator := new(MockValidator)
+	validator.On("Validate", mock.Anything, mock.Anything).Return([]error{}, multierror.Append(nil, fmt.Errorf("%s", err)))
+	return validator
+}
 func MockMutatorReturningWarnings(warning string) *MockMutator {
 	mutator := new(MockMutator)
 	mutator.On("Mutate", mock.Anything, mock.Anything).Return(BaseJob(), false, []error{fmt.Errorf("%s", warning)}, nil)